@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// FakeProvider adalah provider tanpa jaringan untuk development dan CI.
+// Jawaban yang sama selalu dihasilkan untuk pesan yang sama. Jika prompt
+// system memuat contoh jawaban JSON, fake menjawab seperti model yang
+// mengikuti contoh tersebut. Reply dapat diisi untuk menentukan jawaban sendiri.
+type FakeProvider struct {
+	Reply func(req Request) (string, error)
+}
+
+func (f *FakeProvider) Complete(ctx context.Context, req Request) (Response, error) {
+	if err := ctx.Err(); err != nil {
+		return Response{}, err
+	}
+	prompt := countPromptTokens(req.Messages)
+	content := ""
+	if f.Reply != nil {
+		reply, err := f.Reply(req)
+		if err != nil {
+			return Response{}, err
+		}
+		content = reply
+	} else if example, found := promptExample(req); found {
+		content = example
+	} else {
+		content = fakeReply(req)
+	}
+	completion := countTokens(content)
+	return Response{
+		Content: content,
+		Model:   "fake",
+		Usage: Usage{
+			PromptTokens:     prompt,
+			CompletionTokens: completion,
+			TotalTokens:      prompt + completion,
+		},
+	}, nil
+}
+
+var fakeSuggestions = []string{
+	"Siapkan perlengkapan sehari sebelumnya",
+	"Bagi kegiatan menjadi beberapa langkah kecil",
+	"Sisihkan waktu 15 menit untuk persiapan",
+	"Ajak teman agar lebih semangat",
+	"Catat hasilnya setelah selesai",
+}
+
+// promptExample mencari baris JSON terakhir di prompt system, yaitu contoh
+// jawaban yang ditulis setelah bentuk jawaban yang diminta
+func promptExample(req Request) (string, bool) {
+	for _, msg := range req.Messages {
+		if msg.Role != RoleSystem {
+			continue
+		}
+		lines := strings.Split(msg.Content, "\n")
+		for i := len(lines) - 1; i >= 0; i-- {
+			line := strings.TrimSpace(lines[i])
+			if strings.HasPrefix(line, "{") && json.Valid([]byte(line)) {
+				return line, true
+			}
+		}
+	}
+	return "", false
+}
+
+func fakeReply(req Request) string {
+	last := ""
+	for _, msg := range req.Messages {
+		if msg.Role == RoleUser {
+			last = msg.Content
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(last))
+	return fmt.Sprintf("Rekomendasi: %s.", fakeSuggestions[h.Sum32()%uint32(len(fakeSuggestions))])
+}
+
+// Stream mengirim jawaban Complete per potongan kecil
+func (f *FakeProvider) Stream(ctx context.Context, req Request, onDelta func(delta string) error) (Response, error) {
+	res, err := f.Complete(ctx, req)
+	if err != nil {
+		return Response{}, err
+	}
+	for _, chunk := range splitChunks(res.Content, fakeChunkSize) {
+		if err := ctx.Err(); err != nil {
+			return Response{}, err
+		}
+		if err := onDelta(chunk); err != nil {
+			return Response{}, err
+		}
+	}
+	return res, nil
+}
+
+// panjang potongan stream FakeProvider dalam rune
+const fakeChunkSize = 8
+
+func splitChunks(s string, size int) []string {
+	runes := []rune(s)
+	res := []string{}
+	for len(runes) > 0 {
+		n := min(size, len(runes))
+		res = append(res, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return res
+}
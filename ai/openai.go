@@ -0,0 +1,152 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// OpenAIProvider memanggil chat completion OpenAI, atau server lain dengan
+// API yang sama jika baseURL diisi
+type OpenAIProvider struct {
+	client      *openai.Client
+	model       string
+	temperature float32
+	timeout     time.Duration
+}
+
+func NewOpenAIProvider(key, baseURL, model string, temperature float32, timeout time.Duration) *OpenAIProvider {
+	cfg := openai.DefaultConfig(key)
+	if baseURL != "" {
+		cfg.BaseURL = baseURL
+	}
+	if model == "" {
+		model = openai.GPT3Dot5Turbo
+	}
+	return &OpenAIProvider{
+		client:      openai.NewClientWithConfig(cfg),
+		model:       model,
+		temperature: temperature,
+		timeout:     timeout,
+	}
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, req Request) (Response, error) {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	res, err := p.client.CreateChatCompletion(ctx, p.chatRequest(req))
+	if err != nil {
+		return Response{}, providerError(err)
+	}
+	if len(res.Choices) == 0 {
+		return Response{}, errors.New("ai: provider returned no choices")
+	}
+	return Response{
+		Content: res.Choices[0].Message.Content,
+		Model:   res.Model,
+		Usage: Usage{
+			PromptTokens:     res.Usage.PromptTokens,
+			CompletionTokens: res.Usage.CompletionTokens,
+			TotalTokens:      res.Usage.TotalTokens,
+		},
+	}, nil
+}
+
+// Stream memakai streaming chat completion. API stream tidak mengirim usage,
+// sehingga token diperkirakan dari jumlah kata prompt dan jumlah potongan jawaban.
+func (p *OpenAIProvider) Stream(ctx context.Context, req Request, onDelta func(delta string) error) (Response, error) {
+	ctx, cancel := withTimeout(ctx, p.timeout)
+	defer cancel()
+
+	chatRequest := p.chatRequest(req)
+	chatRequest.Stream = true
+	stream, err := p.client.CreateChatCompletionStream(ctx, chatRequest)
+	if err != nil {
+		return Response{}, providerError(err)
+	}
+	defer stream.Close()
+
+	content := strings.Builder{}
+	res := Response{Model: p.model}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Response{}, providerError(err)
+		}
+		if chunk.Model != "" {
+			res.Model = chunk.Model
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := chunk.Choices[0].Delta.Content
+		content.WriteString(delta)
+		res.Usage.CompletionTokens++
+		if err := onDelta(delta); err != nil {
+			return Response{}, err
+		}
+	}
+	res.Content = content.String()
+	res.Usage.PromptTokens = countPromptTokens(req.Messages)
+	res.Usage.TotalTokens = res.Usage.PromptTokens + res.Usage.CompletionTokens
+	return res, nil
+}
+
+func (p *OpenAIProvider) chatRequest(req Request) openai.ChatCompletionRequest {
+	messages := make([]openai.ChatCompletionMessage, 0, len(req.Messages))
+	for _, msg := range req.Messages {
+		messages = append(messages, openai.ChatCompletionMessage{Role: msg.Role, Content: msg.Content})
+	}
+	temperature := p.temperature
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+	return openai.ChatCompletionRequest{
+		Model:       p.model,
+		Messages:    messages,
+		Temperature: temperature,
+	}
+}
+
+// providerError membungkus error dari API OpenAI dengan error ai yang sesuai,
+// error asli tetap bisa diperiksa dengan errors.Is dan errors.As
+func providerError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	status := 0
+	code := ""
+	apiErr := &openai.APIError{}
+	reqErr := &openai.RequestError{}
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.HTTPStatusCode
+		code, _ = apiErr.Code.(string)
+		if code == "" {
+			code = apiErr.Type
+		}
+	case errors.As(err, &reqErr):
+		status = reqErr.HTTPStatusCode
+	}
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	case status == http.StatusTooManyRequests && code == "insufficient_quota":
+		return fmt.Errorf("%w: %w", ErrQuotaExceeded, err)
+	case status == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %w", ErrRateLimited, err)
+	case status >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: %w", ErrUpstream, err)
+	}
+	return err
+}
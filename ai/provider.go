@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mytodo/config"
+	"strings"
+	"time"
+)
+
+// Role pesan yang dikirim ke model
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request adalah satu permintaan completion, Temperature kosong berarti
+// memakai temperature dari config provider. CacheScope memisahkan cache
+// jawaban, misalnya per user dan fitur. NoCache melewati jawaban dari cache
+// tetapi jawaban baru tetap boleh disimpan.
+type Request struct {
+	Messages    []Message
+	Temperature *float32
+	CacheScope  string
+	NoCache     bool `json:"-"`
+}
+
+// Usage adalah jumlah token yang dipakai satu permintaan
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Response dengan Cached true diambil dari cache, Usage-nya kosong karena
+// tidak ada token yang dipakai
+type Response struct {
+	Content string `json:"content"`
+	Model   string `json:"model"`
+	Usage   Usage  `json:"usage"`
+	Cached  bool   `json:"cached"`
+	// confirm diisi provider yang menyimpan cache
+	confirm func()
+}
+
+// Confirm dipanggil setelah jawaban lolos validasi. Hanya jawaban yang
+// dikonfirmasi yang disimpan ke cache, sehingga jawaban tidak valid tidak
+// dipakai ulang untuk prompt yang sama.
+func (r Response) Confirm() {
+	if r.confirm != nil {
+		r.confirm()
+	}
+}
+
+// Provider adalah model bahasa yang dipakai untuk fitur AI. Stream sama
+// seperti Complete tetapi memanggil onDelta untuk setiap potongan jawaban,
+// error dari onDelta menghentikan stream.
+type Provider interface {
+	Complete(ctx context.Context, req Request) (Response, error)
+	Stream(ctx context.Context, req Request, onDelta func(delta string) error) (Response, error)
+}
+
+// ErrDisabled dikembalikan fitur AI saat provider diset none
+var ErrDisabled = errors.New("ai: provider is disabled")
+
+// error dari provider yang dibedakan agar bisa dibalas dengan kode HTTP berbeda
+var (
+	ErrTimeout       = errors.New("ai: provider timed out")
+	ErrRateLimited   = errors.New("ai: provider rate limited")
+	ErrQuotaExceeded = errors.New("ai: provider quota exceeded")
+	ErrInvalidKey    = errors.New("ai: provider rejected api key")
+	ErrUpstream      = errors.New("ai: provider failed")
+	ErrCircuitOpen   = errors.New("ai: provider unavailable")
+)
+
+// NewProvider membuat provider sesuai config: openai, compatible (Ollama,
+// LocalAI, vLLM atau server lain yang meniru API OpenAI), fake dan none.
+// Provider openai dan compatible dibungkus ResilientProvider, provider none
+// mengembalikan nil, fitur AI dimatikan.
+func NewProvider(cfg config.ProgramConfig) (Provider, error) {
+	switch strings.TrimSpace(cfg.AIProvider) {
+	case "", "openai":
+		if cfg.ApiKey == "" {
+			return nil, errors.New("ai: openai provider needs APIKEY")
+		}
+		return NewResilientProvider(NewOpenAIProvider(cfg.ApiKey, "", cfg.AIModel, cfg.AITemperature, 0), resilientConfig(cfg)), nil
+	case "compatible":
+		if cfg.AIBaseURL == "" {
+			return nil, errors.New("ai: compatible provider needs AIBASEURL")
+		}
+		return NewResilientProvider(NewOpenAIProvider(cfg.ApiKey, cfg.AIBaseURL, cfg.AIModel, cfg.AITemperature, 0), resilientConfig(cfg)), nil
+	case "fake":
+		return &FakeProvider{}, nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("ai: unknown provider %q, allowed: openai, compatible, fake, none", cfg.AIProvider)
+	}
+}
+
+// countTokens memperkirakan token dengan menghitung kata
+func countTokens(s string) int {
+	return len(strings.Fields(s))
+}
+
+func countPromptTokens(messages []Message) int {
+	res := 0
+	for _, msg := range messages {
+		res += countTokens(msg.Content)
+	}
+	return res
+}
+
+// withTimeout membatasi waktu permintaan, timeout 0 berarti tanpa batas
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
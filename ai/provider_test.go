@@ -0,0 +1,186 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"mytodo/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProvider(t *testing.T) {
+	test := []struct {
+		name     string
+		cfg      config.ProgramConfig
+		expected Provider
+		hasError bool
+	}{
+		{
+			name:     "OpenAI",
+			cfg:      config.ProgramConfig{AIProvider: "openai", ApiKey: "sk-test"},
+			expected: &ResilientProvider{},
+		},
+		{
+			name:     "OpenAI without key",
+			cfg:      config.ProgramConfig{AIProvider: "openai"},
+			hasError: true,
+		},
+		{
+			name:     "Compatible",
+			cfg:      config.ProgramConfig{AIProvider: "compatible", AIBaseURL: "http://localhost:11434/v1", AIModel: "llama3"},
+			expected: &ResilientProvider{},
+		},
+		{
+			name:     "Compatible without base url",
+			cfg:      config.ProgramConfig{AIProvider: "compatible"},
+			hasError: true,
+		},
+		{
+			name:     "Fake",
+			cfg:      config.ProgramConfig{AIProvider: "fake"},
+			expected: &FakeProvider{},
+		},
+		{
+			name:     "None",
+			cfg:      config.ProgramConfig{AIProvider: "none"},
+			expected: nil,
+		},
+		{
+			name:     "Unknown",
+			cfg:      config.ProgramConfig{AIProvider: "skynet"},
+			hasError: true,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := NewProvider(tc.cfg)
+			if tc.hasError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.IsType(t, tc.expected, provider)
+		})
+	}
+}
+
+func TestOpenAIProvider_Compatible(t *testing.T) {
+	received := map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/chat/completions", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"llama3","choices":[{"message":{"role":"assistant","content":"Jogging pagi"}}],"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("", server.URL+"/v1", "llama3", 0.2, time.Second)
+	res, err := provider.Complete(context.Background(), Request{Messages: []Message{{Role: RoleUser, Content: "olahraga"}}})
+	require.NoError(t, err)
+	require.Equal(t, "Jogging pagi", res.Content)
+	require.Equal(t, 15, res.Usage.TotalTokens)
+	require.Equal(t, "llama3", received["model"])
+	require.InDelta(t, 0.2, received["temperature"], 0.001)
+
+	temperature := float32(0.9)
+	_, err = provider.Complete(context.Background(), Request{Messages: []Message{{Role: RoleUser, Content: "olahraga"}}, Temperature: &temperature})
+	require.NoError(t, err)
+	require.InDelta(t, 0.9, received["temperature"], 0.001)
+}
+
+func TestOpenAIProvider_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("", server.URL, "llama3", 0, 50*time.Millisecond)
+	_, err := provider.Complete(context.Background(), Request{Messages: []Message{{Role: RoleUser, Content: "olahraga"}}})
+	require.Error(t, err)
+}
+
+func TestFakeProvider(t *testing.T) {
+	provider := &FakeProvider{}
+	req := Request{Messages: []Message{{Role: RoleUser, Content: "olahraga sore"}}}
+
+	first, err := provider.Complete(context.Background(), req)
+	require.NoError(t, err)
+	second, err := provider.Complete(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.NotEmpty(t, first.Content)
+	require.Equal(t, 2, first.Usage.PromptTokens)
+
+	provider.Reply = func(req Request) (string, error) { return "ok", nil }
+	res, err := provider.Complete(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "ok", res.Content)
+}
+
+func TestFakeProvider_PromptExample(t *testing.T) {
+	provider := &FakeProvider{}
+	res, err := provider.Complete(context.Background(), Request{
+		Messages: []Message{
+			{Role: RoleSystem, Content: "Jawab dengan bentuk:\n{\"suggestions\":[{\"memo\":string}]}\nContoh:\n{\"suggestions\":[{\"memo\":\"Jogging\"}]}\nRingkasan: user suka olahraga"},
+			{Role: RoleUser, Content: "olahraga"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"suggestions":[{"memo":"Jogging"}]}`, res.Content)
+}
+
+func TestOpenAIProvider_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, true, body["stream"])
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range []string{"Jogging", " pagi"} {
+			chunk, _ := json.Marshal(map[string]any{"model": "llama3", "choices": []any{map[string]any{"delta": map[string]string{"content": delta}}}})
+			w.Write([]byte("data: " + string(chunk) + "\n\n"))
+			w.(http.Flusher).Flush()
+		}
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("", server.URL+"/v1", "llama3", 0.2, time.Second)
+	deltas := []string{}
+	res, err := provider.Stream(context.Background(), Request{Messages: []Message{{Role: RoleUser, Content: "olahraga pagi"}}}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Jogging", " pagi"}, deltas)
+	require.Equal(t, "Jogging pagi", res.Content)
+	require.Equal(t, Usage{PromptTokens: 2, CompletionTokens: 2, TotalTokens: 4}, res.Usage)
+
+	_, err = provider.Stream(context.Background(), Request{Messages: []Message{{Role: RoleUser, Content: "olahraga"}}}, func(delta string) error {
+		return context.Canceled
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFakeProvider_Stream(t *testing.T) {
+	provider := &FakeProvider{Reply: func(req Request) (string, error) { return "Rekomendasi: jogging", nil }}
+	deltas := []string{}
+	res, err := provider.Stream(context.Background(), Request{}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Rekomend", "asi: jog", "ging"}, deltas)
+	require.Equal(t, "Rekomendasi: jogging", res.Content)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = provider.Stream(ctx, Request{}, func(delta string) error { return nil })
+	require.ErrorIs(t, err, context.Canceled)
+}
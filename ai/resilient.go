@@ -0,0 +1,309 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"mytodo/config"
+	"net"
+	"sync"
+	"time"
+)
+
+// ResilientConfig mengatur ResilientProvider, nilai 0 mematikan timeout,
+// retry, circuit breaker atau cache
+type ResilientConfig struct {
+	Timeout          time.Duration
+	Retries          int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	CacheTTL         time.Duration
+	CacheSize        int
+}
+
+func resilientConfig(cfg config.ProgramConfig) ResilientConfig {
+	return ResilientConfig{
+		Timeout:          cfg.AITimeout,
+		Retries:          cfg.AIRetries,
+		BaseDelay:        500 * time.Millisecond,
+		MaxDelay:         5 * time.Second,
+		BreakerThreshold: cfg.AIBreakerThreshold,
+		BreakerCooldown:  cfg.AIBreakerCooldown,
+		CacheTTL:         cfg.AICacheTTL,
+		CacheSize:        256,
+	}
+}
+
+// ResilientProvider membungkus provider lain dengan batas waktu per
+// permintaan, retry dengan jittered backoff saat 429/5xx, circuit breaker
+// yang langsung gagal selama upstream bermasalah dan cache jawaban untuk
+// prompt dan CacheScope yang sama. Jawaban baru masuk cache setelah
+// Response.Confirm dipanggil.
+type ResilientProvider struct {
+	next Provider
+	cfg  ResilientConfig
+	now  func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+	cache     map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	res     Response
+	expires time.Time
+}
+
+func NewResilientProvider(next Provider, cfg ResilientConfig) *ResilientProvider {
+	return &ResilientProvider{
+		next:  next,
+		cfg:   cfg,
+		now:   time.Now,
+		cache: map[string]cacheEntry{},
+	}
+}
+
+func (p *ResilientProvider) Complete(ctx context.Context, req Request) (Response, error) {
+	key := cacheKey(req)
+	if res, found := p.cached(key); found && !req.NoCache {
+		return res, nil
+	}
+	var res Response
+	err := p.call(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		res, err = p.next.Complete(ctx, req)
+		return false, err
+	})
+	if err != nil {
+		return Response{}, err
+	}
+	return p.confirmable(key, res), nil
+}
+
+// Stream hanya diulang jika belum ada potongan jawaban yang terkirim, jawaban
+// dari cache dikirim sebagai satu potongan
+func (p *ResilientProvider) Stream(ctx context.Context, req Request, onDelta func(delta string) error) (Response, error) {
+	key := cacheKey(req)
+	if res, found := p.cached(key); found && !req.NoCache {
+		if err := onDelta(res.Content); err != nil {
+			return Response{}, err
+		}
+		return res, nil
+	}
+	var res Response
+	err := p.call(ctx, func(ctx context.Context) (bool, error) {
+		sent := false
+		var deltaErr error
+		var err error
+		res, err = p.next.Stream(ctx, req, func(delta string) error {
+			sent = true
+			deltaErr = onDelta(delta)
+			return deltaErr
+		})
+		if deltaErr != nil && errors.Is(err, deltaErr) {
+			err = clientError{err}
+		}
+		return sent, err
+	})
+	clientErr := clientError{}
+	if errors.As(err, &clientErr) {
+		return Response{}, clientErr.err
+	}
+	if err != nil {
+		return Response{}, err
+	}
+	return p.confirmable(key, res), nil
+}
+
+// confirmable menunda penyimpanan res ke cache sampai pemanggil memanggil Confirm
+func (p *ResilientProvider) confirmable(key string, res Response) Response {
+	if p.cfg.CacheTTL <= 0 {
+		return res
+	}
+	entry := res
+	res.confirm = func() { p.store(key, entry) }
+	return res
+}
+
+// clientError adalah error dari onDelta, bukan kegagalan upstream
+type clientError struct {
+	err error
+}
+
+func (e clientError) Error() string { return e.err.Error() }
+func (e clientError) Unwrap() error { return e.err }
+
+// call menjalankan fn dengan timeout, retry dan circuit breaker. fn
+// mengembalikan stop true jika permintaan tidak boleh diulang, misalnya
+// potongan stream sudah terkirim atau error berasal dari sisi client.
+func (p *ResilientProvider) call(ctx context.Context, fn func(ctx context.Context) (bool, error)) error {
+	if err := p.allow(); err != nil {
+		return err
+	}
+	if p.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.cfg.Timeout)
+		defer cancel()
+	}
+	for attempt := 0; ; attempt++ {
+		stop, err := fn(ctx)
+		err = contextError(ctx, err)
+		if err == nil || errors.Is(err, context.Canceled) || (stop && !breakerFailure(err)) {
+			p.done(err, false)
+			return err
+		}
+		if stop || !retryable(err) || attempt >= p.cfg.Retries {
+			p.done(err, breakerFailure(err))
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			err = contextError(ctx, ctx.Err())
+			p.done(err, breakerFailure(err))
+			return err
+		case <-time.After(p.backoff(attempt)):
+		}
+	}
+}
+
+// allow menolak permintaan selama breaker terbuka, setelah cooldown satu
+// permintaan diloloskan untuk menguji upstream
+func (p *ResilientProvider) allow() error {
+	if p.cfg.BreakerThreshold <= 0 {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.openUntil.IsZero() {
+		return nil
+	}
+	if p.now().Before(p.openUntil) || p.probing {
+		return ErrCircuitOpen
+	}
+	p.probing = true
+	return nil
+}
+
+// done mencatat hasil permintaan ke breaker, error yang bukan kegagalan
+// upstream (counted false) tidak mengubah jumlah kegagalan
+func (p *ResilientProvider) done(err error, counted bool) {
+	if p.cfg.BreakerThreshold <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case err == nil:
+		p.failures = 0
+		p.openUntil = time.Time{}
+	case counted:
+		p.failures++
+		if p.probing || p.failures >= p.cfg.BreakerThreshold {
+			p.openUntil = p.now().Add(p.cfg.BreakerCooldown)
+		}
+	}
+	p.probing = false
+}
+
+// backoff menghitung jeda sebelum percobaan berikutnya, antara setengah dan
+// penuh dari BaseDelay*2^attempt dengan batas MaxDelay
+func (p *ResilientProvider) backoff(attempt int) time.Duration {
+	delay := p.cfg.BaseDelay << attempt
+	if delay <= 0 || (p.cfg.MaxDelay > 0 && delay > p.cfg.MaxDelay) {
+		delay = p.cfg.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func (p *ResilientProvider) cached(key string) (Response, bool) {
+	if p.cfg.CacheTTL <= 0 {
+		return Response{}, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, found := p.cache[key]
+	if !found || !p.now().Before(entry.expires) {
+		return Response{}, false
+	}
+	res := entry.res
+	res.Usage = Usage{}
+	res.Cached = true
+	return res, true
+}
+
+// store menyimpan jawaban ke cache, jika penuh jawaban yang kedaluwarsa atau
+// yang paling cepat kedaluwarsa dibuang
+func (p *ResilientProvider) store(key string, res Response) {
+	if p.cfg.CacheTTL <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	if p.cfg.CacheSize > 0 && len(p.cache) >= p.cfg.CacheSize {
+		oldest := ""
+		for k, entry := range p.cache {
+			if !now.Before(entry.expires) {
+				delete(p.cache, k)
+			} else if oldest == "" || entry.expires.Before(p.cache[oldest].expires) {
+				oldest = k
+			}
+		}
+		if len(p.cache) >= p.cfg.CacheSize {
+			delete(p.cache, oldest)
+		}
+	}
+	p.cache[key] = cacheEntry{res: res, expires: now.Add(p.cfg.CacheTTL)}
+}
+
+func cacheKey(req Request) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// contextError membedakan batas waktu habis (ErrTimeout) dan permintaan yang
+// dibatalkan client (context.Canceled)
+func contextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		if errors.Is(err, ErrTimeout) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	case context.Canceled:
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", context.Canceled, err)
+	}
+	return err
+}
+
+// retryable hanya 429 karena rate limit dan error 5xx
+func retryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUpstream)
+}
+
+// breakerFailure menandai error yang menunjukkan upstream bermasalah
+func breakerFailure(err error) bool {
+	if errors.As(err, &clientError{}) {
+		return false
+	}
+	netErr := net.Error(nil)
+	return errors.Is(err, ErrUpstream) || errors.Is(err, ErrTimeout) || errors.Is(err, ErrRateLimited) || errors.As(err, &netErr)
+}
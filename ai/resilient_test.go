@@ -0,0 +1,290 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// stubProvider menjawab dengan replies secara berurutan, yang terakhir dipakai
+// untuk panggilan berikutnya
+type stubProvider struct {
+	calls   int32
+	replies []func(ctx context.Context) (Response, error)
+}
+
+func (s *stubProvider) Complete(ctx context.Context, req Request) (Response, error) {
+	n := int(atomic.AddInt32(&s.calls, 1)) - 1
+	return s.replies[min(n, len(s.replies)-1)](ctx)
+}
+
+func (s *stubProvider) Stream(ctx context.Context, req Request, onDelta func(delta string) error) (Response, error) {
+	res, err := s.Complete(ctx, req)
+	if err != nil {
+		return Response{}, err
+	}
+	if err := onDelta(res.Content); err != nil {
+		return Response{}, err
+	}
+	return res, nil
+}
+
+func reply(content string) func(ctx context.Context) (Response, error) {
+	return func(ctx context.Context) (Response, error) {
+		return Response{Content: content, Usage: Usage{TotalTokens: 10}}, nil
+	}
+}
+
+func fail(err error) func(ctx context.Context) (Response, error) {
+	return func(ctx context.Context) (Response, error) {
+		return Response{}, err
+	}
+}
+
+func TestResilientProvider_Retry(t *testing.T) {
+	test := []struct {
+		name     string
+		replies  []func(ctx context.Context) (Response, error)
+		retries  int
+		calls    int32
+		expected error
+	}{
+		{
+			name:    "Retry on 5xx and 429 until success",
+			replies: []func(ctx context.Context) (Response, error){fail(ErrUpstream), fail(ErrRateLimited), reply("ok")},
+			retries: 2,
+			calls:   3,
+		},
+		{
+			name:     "Give up after retries",
+			replies:  []func(ctx context.Context) (Response, error){fail(ErrUpstream)},
+			retries:  2,
+			calls:    3,
+			expected: ErrUpstream,
+		},
+		{
+			name:     "No retry on invalid key",
+			replies:  []func(ctx context.Context) (Response, error){fail(ErrInvalidKey)},
+			retries:  2,
+			calls:    1,
+			expected: ErrInvalidKey,
+		},
+		{
+			name:     "No retry on quota exceeded",
+			replies:  []func(ctx context.Context) (Response, error){fail(ErrQuotaExceeded)},
+			retries:  2,
+			calls:    1,
+			expected: ErrQuotaExceeded,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			stub := &stubProvider{replies: tc.replies}
+			provider := NewResilientProvider(stub, ResilientConfig{Retries: tc.retries, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+			res, err := provider.Complete(context.Background(), Request{})
+			require.Equal(t, tc.calls, stub.calls)
+			if tc.expected != nil {
+				require.ErrorIs(t, err, tc.expected)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "ok", res.Content)
+		})
+	}
+}
+
+func TestResilientProvider_Timeout(t *testing.T) {
+	slow := func(ctx context.Context) (Response, error) {
+		<-ctx.Done()
+		return Response{}, ctx.Err()
+	}
+	provider := NewResilientProvider(&stubProvider{replies: []func(ctx context.Context) (Response, error){slow}}, ResilientConfig{Timeout: 20 * time.Millisecond})
+	start := time.Now()
+	_, err := provider.Complete(context.Background(), Request{})
+	require.ErrorIs(t, err, ErrTimeout)
+	require.Less(t, time.Since(start), time.Second)
+
+	// request dibatalkan client bukan timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = provider.Complete(ctx, Request{})
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotErrorIs(t, err, ErrTimeout)
+}
+
+func TestResilientProvider_CircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	stub := &stubProvider{replies: []func(ctx context.Context) (Response, error){fail(ErrUpstream), fail(ErrUpstream), fail(ErrUpstream), reply("ok")}}
+	provider := NewResilientProvider(stub, ResilientConfig{BreakerThreshold: 2, BreakerCooldown: 30 * time.Second})
+	provider.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		_, err := provider.Complete(context.Background(), Request{})
+		require.ErrorIs(t, err, ErrUpstream)
+	}
+	_, err := provider.Complete(context.Background(), Request{})
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, int32(2), stub.calls)
+
+	// setelah cooldown satu permintaan menguji upstream, gagal membuka lagi
+	now = now.Add(31 * time.Second)
+	_, err = provider.Complete(context.Background(), Request{})
+	require.ErrorIs(t, err, ErrUpstream)
+	_, err = provider.Complete(context.Background(), Request{})
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	now = now.Add(31 * time.Second)
+	res, err := provider.Complete(context.Background(), Request{})
+	require.NoError(t, err)
+	require.Equal(t, "ok", res.Content)
+	_, err = provider.Complete(context.Background(), Request{Messages: []Message{{Role: RoleUser, Content: "lain"}}})
+	require.NoError(t, err)
+	require.Equal(t, int32(5), stub.calls)
+
+	// error dari sisi client tidak membuka breaker
+	stub = &stubProvider{replies: []func(ctx context.Context) (Response, error){reply("ok")}}
+	provider = NewResilientProvider(stub, ResilientConfig{BreakerThreshold: 1, BreakerCooldown: time.Minute})
+	for i := 0; i < 2; i++ {
+		_, err = provider.Stream(context.Background(), Request{}, func(delta string) error { return &writeError{} })
+		require.ErrorAs(t, err, new(*writeError))
+	}
+	require.Equal(t, int32(2), stub.calls)
+}
+
+// writeError meniru error jaringan saat menulis ke client
+type writeError struct{}
+
+func (e *writeError) Error() string   { return "write: broken pipe" }
+func (e *writeError) Timeout() bool   { return false }
+func (e *writeError) Temporary() bool { return false }
+
+func TestResilientProvider_Cache(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	stub := &stubProvider{replies: []func(ctx context.Context) (Response, error){reply("pertama"), reply("kedua")}}
+	provider := NewResilientProvider(stub, ResilientConfig{CacheTTL: time.Minute, CacheSize: 1})
+	provider.now = func() time.Time { return now }
+	req := Request{Messages: []Message{{Role: RoleUser, Content: "olahraga"}}}
+
+	first, err := provider.Complete(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, first.Cached)
+	require.Equal(t, 10, first.Usage.TotalTokens)
+	first.Confirm()
+
+	deltas := []string{}
+	second, err := provider.Stream(context.Background(), req, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, second.Cached)
+	require.Equal(t, "pertama", second.Content)
+	require.Equal(t, []string{"pertama"}, deltas)
+	require.Equal(t, Usage{}, second.Usage)
+	require.Equal(t, int32(1), stub.calls)
+
+	now = now.Add(time.Minute)
+	third, err := provider.Complete(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "kedua", third.Content)
+	require.Equal(t, int32(2), stub.calls)
+}
+
+func TestResilientProvider_CacheConfirm(t *testing.T) {
+	replies := []func(ctx context.Context) (Response, error){}
+	for _, content := range []string{"tidak valid", "valid", "user lain", "baru"} {
+		replies = append(replies, reply(content))
+	}
+	stub := &stubProvider{replies: replies}
+	provider := NewResilientProvider(stub, ResilientConfig{CacheTTL: time.Minute, CacheSize: 10})
+	req := Request{Messages: []Message{{Role: RoleUser, Content: "olahraga"}}, CacheScope: "1/suggest"}
+
+	// jawaban yang tidak dikonfirmasi tidak masuk cache
+	res, err := provider.Complete(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "tidak valid", res.Content)
+	res, err = provider.Complete(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "valid", res.Content)
+	res.Confirm()
+	res, err = provider.Complete(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, res.Cached)
+	require.Equal(t, "valid", res.Content)
+	require.Equal(t, int32(2), stub.calls)
+
+	// cache tidak dibagi antar scope
+	other := req
+	other.CacheScope = "2/suggest"
+	res, err = provider.Complete(context.Background(), other)
+	require.NoError(t, err)
+	require.Equal(t, "user lain", res.Content)
+
+	// NoCache meminta jawaban baru dan menggantikan isi cache setelah dikonfirmasi
+	fresh := req
+	fresh.NoCache = true
+	res, err = provider.Complete(context.Background(), fresh)
+	require.NoError(t, err)
+	require.False(t, res.Cached)
+	require.Equal(t, "baru", res.Content)
+	res.Confirm()
+	res, err = provider.Complete(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, res.Cached)
+	require.Equal(t, "baru", res.Content)
+	require.Equal(t, int32(4), stub.calls)
+}
+
+func TestOpenAIProvider_Errors(t *testing.T) {
+	test := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+	}{
+		{name: "Invalid key", status: 401, body: `{"error":{"message":"bad key","type":"invalid_request_error","code":"invalid_api_key"}}`, expected: ErrInvalidKey},
+		{name: "Quota exceeded", status: 429, body: `{"error":{"message":"quota","type":"insufficient_quota","code":"insufficient_quota"}}`, expected: ErrQuotaExceeded},
+		{name: "Rate limited", status: 429, body: `{"error":{"message":"slow down","type":"requests","code":"rate_limit_exceeded"}}`, expected: ErrRateLimited},
+		{name: "Upstream error", status: 503, body: `{"error":{"message":"overloaded","type":"server_error"}}`, expected: ErrUpstream},
+		{name: "Upstream error without json", status: 502, body: `bad gateway`, expected: ErrUpstream},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			provider := NewOpenAIProvider("", server.URL, "llama3", 0, time.Second)
+			_, err := provider.Complete(context.Background(), Request{Messages: []Message{{Role: RoleUser, Content: "olahraga"}}})
+			require.ErrorIs(t, err, tc.expected)
+			for _, other := range []error{ErrInvalidKey, ErrQuotaExceeded, ErrRateLimited, ErrUpstream} {
+				if !errors.Is(tc.expected, other) {
+					require.NotErrorIs(t, err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAIProvider_NoChoices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"llama3","choices":[]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("", server.URL, "llama3", 0, time.Second)
+	require.NotPanics(t, func() {
+		_, err := provider.Complete(context.Background(), Request{Messages: []Message{{Role: RoleUser, Content: "olahraga"}}})
+		require.Error(t, err)
+	})
+}
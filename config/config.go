@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
@@ -16,8 +17,46 @@ type ProgramConfig struct {
 	DBUser     string
 	DBPassword string
 	DBName     string
+	DBDriver   string
+	DBPath     string
+	DBSSLMode  string
 	Secret     string
 	ApiKey     string
+	BcryptCost int
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	// Reminder Config
+	ReminderInterval time.Duration
+	ReminderLease    time.Duration
+	ReminderNotifier string
+	SMTPHost         string
+	SMTPPort         int
+	SMTPUser         string
+	SMTPPassword     string
+	SMTPFrom         string
+	WebhookURL       string
+	// AI Config
+	AIProvider    string
+	AIBaseURL     string
+	AIModel       string
+	AITemperature float32
+	AITimeout     time.Duration
+	// Kuota token AI per user (0 berarti tanpa batas) dan harga per 1000 token
+	AIDailyQuota      int64
+	AIMonthlyQuota    int64
+	AIPromptPrice     float64
+	AICompletionPrice float64
+	// Ketahanan panggilan AI: jumlah ulang saat 429/5xx, circuit breaker
+	// terbuka setelah AIBreakerThreshold kegagalan berturut-turut selama
+	// AIBreakerCooldown, dan cache jawaban valid untuk prompt, user dan fitur
+	// yang sama selama AICacheTTL
+	AIRetries          int
+	AIBreakerThreshold int
+	AIBreakerCooldown  time.Duration
+	AICacheTTL         time.Duration
+	// AutoMigrate menjalankan migrasi yang tertinggal saat start, tanpa ini
+	// app menolak start jika skema database tertinggal
+	AutoMigrate bool
 }
 
 // Initial Config untuk Load Config diawal
@@ -34,6 +73,27 @@ func InitConfig() *ProgramConfig {
 // Load Config dari Env
 func loadConfig() *ProgramConfig {
 	var res = new(ProgramConfig)
+	res.DBDriver = "mysql"
+	res.DBPath = "mytodo.db"
+	res.DBSSLMode = "disable"
+	res.BcryptCost = 10
+	res.AccessTTL = 15 * time.Minute
+	res.RefreshTTL = 30 * 24 * time.Hour
+	res.ReminderInterval = 30 * time.Second
+	res.ReminderLease = 2 * time.Minute
+	res.ReminderNotifier = "log"
+	res.SMTPPort = 587
+	res.AIProvider = "openai"
+	res.AITemperature = 0.7
+	res.AITimeout = 30 * time.Second
+	res.AIDailyQuota = 50000
+	res.AIMonthlyQuota = 1000000
+	res.AIPromptPrice = 0.0015
+	res.AICompletionPrice = 0.002
+	res.AIRetries = 2
+	res.AIBreakerThreshold = 5
+	res.AIBreakerCooldown = 30 * time.Second
+	res.AICacheTTL = time.Minute
 
 	// Load Env
 	err := godotenv.Load()
@@ -80,6 +140,35 @@ func loadConfig() *ProgramConfig {
 		res.DBName = val
 	}
 
+	// Get DB Driver Value (mysql, postgres, sqlite)
+	if val, found := os.LookupEnv("DBDRIVER"); found {
+		switch val {
+		case "mysql", "postgres", "sqlite":
+			res.DBDriver = val
+		default:
+			logrus.Fatal("Config: Driver Database Tidak Valid")
+		}
+	}
+
+	// Get DB Path Value untuk sqlite, file database atau :memory:
+	if val, found := os.LookupEnv("DBPATH"); found {
+		res.DBPath = val
+	}
+
+	// Get DB SSL Mode Value untuk postgres (disable, require, verify-full)
+	if val, found := os.LookupEnv("DBSSLMODE"); found {
+		res.DBSSLMode = val
+	}
+
+	// Get Auto Migrate Value
+	if val, found := os.LookupEnv("AUTOMIGRATE"); found {
+		autoMigrate, err := strconv.ParseBool(val)
+		if err != nil {
+			logrus.Fatal("Config: Auto Migrate Tidak Valid")
+		}
+		res.AutoMigrate = autoMigrate
+	}
+
 	if val, found := os.LookupEnv("SECRET"); found {
 		res.Secret = val
 	}
@@ -88,5 +177,186 @@ func loadConfig() *ProgramConfig {
 		res.ApiKey = val
 	}
 
+	// Get Bcrypt Cost Value
+	if val, found := os.LookupEnv("BCRYPTCOST"); found {
+		cost, err := strconv.Atoi(val)
+		if err != nil || cost < 4 || cost > 31 {
+			logrus.Fatal("Config: Bcrypt Cost Tidak Valid")
+		}
+		res.BcryptCost = cost
+	}
+
+	// Get Access Token TTL Value (menit)
+	if val, found := os.LookupEnv("ACCESSTTL"); found {
+		minutes, err := strconv.Atoi(val)
+		if err != nil || minutes <= 0 {
+			logrus.Fatal("Config: Access Token TTL Tidak Valid")
+		}
+		res.AccessTTL = time.Duration(minutes) * time.Minute
+	}
+
+	// Get Refresh Token TTL Value (jam)
+	if val, found := os.LookupEnv("REFRESHTTL"); found {
+		hours, err := strconv.Atoi(val)
+		if err != nil || hours <= 0 {
+			logrus.Fatal("Config: Refresh Token TTL Tidak Valid")
+		}
+		res.RefreshTTL = time.Duration(hours) * time.Hour
+	}
+
+	// Get Reminder Poll Interval Value (detik)
+	if val, found := os.LookupEnv("REMINDERINTERVAL"); found {
+		seconds, err := strconv.Atoi(val)
+		if err != nil || seconds <= 0 {
+			logrus.Fatal("Config: Interval Reminder Tidak Valid")
+		}
+		res.ReminderInterval = time.Duration(seconds) * time.Second
+	}
+
+	// Get Reminder Lease Value (detik)
+	if val, found := os.LookupEnv("REMINDERLEASE"); found {
+		seconds, err := strconv.Atoi(val)
+		if err != nil || seconds <= 0 {
+			logrus.Fatal("Config: Lease Reminder Tidak Valid")
+		}
+		res.ReminderLease = time.Duration(seconds) * time.Second
+	}
+
+	// Get Reminder Notifier Value, dipisah koma (log, smtp, webhook)
+	if val, found := os.LookupEnv("REMINDERNOTIFIER"); found {
+		res.ReminderNotifier = val
+	}
+
+	// Get SMTP Value
+	if val, found := os.LookupEnv("SMTPHOST"); found {
+		res.SMTPHost = val
+	}
+
+	if val, found := os.LookupEnv("SMTPPORT"); found {
+		port, err := strconv.Atoi(val)
+		if err != nil {
+			logrus.Fatal("Config: Port SMTP Tidak Valid")
+		}
+		res.SMTPPort = port
+	}
+
+	if val, found := os.LookupEnv("SMTPUSER"); found {
+		res.SMTPUser = val
+	}
+
+	if val, found := os.LookupEnv("SMTPPASS"); found {
+		res.SMTPPassword = val
+	}
+
+	if val, found := os.LookupEnv("SMTPFROM"); found {
+		res.SMTPFrom = val
+	}
+
+	// Get Webhook URL Value
+	if val, found := os.LookupEnv("WEBHOOKURL"); found {
+		res.WebhookURL = val
+	}
+
+	// Get AI Provider Value (openai, compatible, fake, none)
+	if val, found := os.LookupEnv("AIPROVIDER"); found {
+		res.AIProvider = val
+	}
+
+	// Get AI Base URL Value untuk provider compatible, misal http://localhost:11434/v1
+	if val, found := os.LookupEnv("AIBASEURL"); found {
+		res.AIBaseURL = val
+	}
+
+	if val, found := os.LookupEnv("AIMODEL"); found {
+		res.AIModel = val
+	}
+
+	// Get AI Temperature Value
+	if val, found := os.LookupEnv("AITEMPERATURE"); found {
+		temperature, err := strconv.ParseFloat(val, 32)
+		if err != nil || temperature < 0 || temperature > 2 {
+			logrus.Fatal("Config: Temperature AI Tidak Valid")
+		}
+		res.AITemperature = float32(temperature)
+	}
+
+	// Get AI Timeout Value (detik)
+	if val, found := os.LookupEnv("AITIMEOUT"); found {
+		seconds, err := strconv.Atoi(val)
+		if err != nil || seconds <= 0 {
+			logrus.Fatal("Config: Timeout AI Tidak Valid")
+		}
+		res.AITimeout = time.Duration(seconds) * time.Second
+	}
+
+	// Get AI Token Quota Value
+	if val, found := os.LookupEnv("AIDAILYQUOTA"); found {
+		quota, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || quota < 0 {
+			logrus.Fatal("Config: Kuota Harian AI Tidak Valid")
+		}
+		res.AIDailyQuota = quota
+	}
+
+	if val, found := os.LookupEnv("AIMONTHLYQUOTA"); found {
+		quota, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || quota < 0 {
+			logrus.Fatal("Config: Kuota Bulanan AI Tidak Valid")
+		}
+		res.AIMonthlyQuota = quota
+	}
+
+	// Get AI Price Value (per 1000 token)
+	if val, found := os.LookupEnv("AIPROMPTPRICE"); found {
+		price, err := strconv.ParseFloat(val, 64)
+		if err != nil || price < 0 {
+			logrus.Fatal("Config: Harga Prompt AI Tidak Valid")
+		}
+		res.AIPromptPrice = price
+	}
+
+	if val, found := os.LookupEnv("AICOMPLETIONPRICE"); found {
+		price, err := strconv.ParseFloat(val, 64)
+		if err != nil || price < 0 {
+			logrus.Fatal("Config: Harga Completion AI Tidak Valid")
+		}
+		res.AICompletionPrice = price
+	}
+
+	// Get AI Resilience Value, 0 mematikan retry, circuit breaker atau cache
+	if val, found := os.LookupEnv("AIRETRIES"); found {
+		retries, err := strconv.Atoi(val)
+		if err != nil || retries < 0 {
+			logrus.Fatal("Config: Jumlah Retry AI Tidak Valid")
+		}
+		res.AIRetries = retries
+	}
+
+	if val, found := os.LookupEnv("AIBREAKERTHRESHOLD"); found {
+		threshold, err := strconv.Atoi(val)
+		if err != nil || threshold < 0 {
+			logrus.Fatal("Config: Threshold Circuit Breaker AI Tidak Valid")
+		}
+		res.AIBreakerThreshold = threshold
+	}
+
+	// Get AI Breaker Cooldown Value (detik)
+	if val, found := os.LookupEnv("AIBREAKERCOOLDOWN"); found {
+		seconds, err := strconv.Atoi(val)
+		if err != nil || seconds <= 0 {
+			logrus.Fatal("Config: Cooldown Circuit Breaker AI Tidak Valid")
+		}
+		res.AIBreakerCooldown = time.Duration(seconds) * time.Second
+	}
+
+	// Get AI Cache TTL Value (detik)
+	if val, found := os.LookupEnv("AICACHETTL"); found {
+		seconds, err := strconv.Atoi(val)
+		if err != nil || seconds < 0 {
+			logrus.Fatal("Config: TTL Cache AI Tidak Valid")
+		}
+		res.AICacheTTL = time.Duration(seconds) * time.Second
+	}
+
 	return res
 }
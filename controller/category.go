@@ -36,12 +36,14 @@ func (cc *CategoryController) AddCategory() echo.HandlerFunc {
 		data := model.Category{}
 		if err := c.Bind(&data); err != nil {
 			fmt.Println(err)
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data")
+		}
+		if err := c.Validate(&data); err != nil {
+			return fail("Create Category Failed", err)
 		}
 		data.UserID = uint(id)
-		res := cc.model.AddCategory(data)
-		if !res {
-			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Create Category Failed", nil))
+		if err := cc.model.AddCategory(data); err != nil {
+			return fail("Create Category Failed", err)
 		}
 		return c.JSON(http.StatusCreated, helper.FormatResponse("Create Category Succesfull", nil))
 	}
@@ -49,24 +51,18 @@ func (cc *CategoryController) AddCategory() echo.HandlerFunc {
 
 func (cc *CategoryController) GetCategories() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		categories := []model.Category{}
 		claims := helper.ExtractToken("user", c)
 		id := claims["id"].(float64)
-		pageString := c.QueryParam("page")
-		page, err := strconv.Atoi(pageString)
+		page, perPage, err := helper.PageParams(c.QueryParam("page"), c.QueryParam("content"))
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Get Page Value", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Get Page Or Content Value")
 		}
-		perPageString := c.QueryParam("content")
-		perPage, err := strconv.Atoi(perPageString)
+		categories, total, err := cc.model.GetCategories(page, perPage, uint(id))
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Get Content Value", nil))
-		}
-		categories = cc.model.GetCategories(page, perPage, uint(id))
-		if categories == nil {
-			return c.JSON(http.StatusNotFound, helper.FormatResponse("Data Not Found", nil))
+			return fail("Get Categories Failed", err)
 		}
-		return c.JSON(http.StatusOK, helper.FormatResponse("Success Get Categories Data", &categories))
+		pagination := helper.NewPagination(c.Request().URL, page, perPage, total)
+		return c.JSON(http.StatusOK, helper.FormatPaginatedResponse("Success Get Categories Data", categories, pagination))
 	}
 }
 
@@ -77,11 +73,11 @@ func (cc *CategoryController) GetCategory() echo.HandlerFunc {
 		idCategoryString := c.Param("id")
 		idCategory, err := strconv.Atoi(idCategoryString)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Category Format Wrong", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Id Category Format Wrong")
 		}
-		res := cc.model.GetCategory(idCategory, uint(idUser))
-		if res == nil {
-			return c.JSON(http.StatusNotFound, helper.FormatResponse("Data Not Found", nil))
+		res, err := cc.model.GetCategory(idCategory, uint(idUser))
+		if err != nil {
+			return fail("Get Category Failed", err)
 		}
 		return c.JSON(http.StatusOK, helper.FormatResponse("Get Category Successfull", res))
 	}
@@ -95,15 +91,17 @@ func (cc *CategoryController) UpdateCategory() echo.HandlerFunc {
 		category := model.Category{}
 		err := c.Bind(&category)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data")
+		}
+		if err := c.Validate(&category); err != nil {
+			return fail("Update Category Failed", err)
 		}
 		idCategory, err := strconv.Atoi(idCategoryString)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Category Format Wrong", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Id Category Format Wrong")
 		}
-		res := cc.model.UpdateCategory(category, idCategory, uint(idUser))
-		if !res {
-			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Update Category Failed", nil))
+		if err := cc.model.UpdateCategory(category, idCategory, uint(idUser)); err != nil {
+			return fail("Update Category Failed", err)
 		}
 		return c.JSON(http.StatusOK, helper.FormatResponse("Update Category Successfull", nil))
 	}
@@ -116,11 +114,10 @@ func (cc *CategoryController) DeleteCategory() echo.HandlerFunc {
 		idCategoryString := c.Param("id")
 		idCategory, err := strconv.Atoi(idCategoryString)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Category Format Wrong", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Id Category Format Wrong")
 		}
-		res := cc.model.DeleteCategory(idCategory, uint(idUser))
-		if !res {
-			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Delete Category Failed", nil))
+		if err := cc.model.DeleteCategory(idCategory, uint(idUser)); err != nil {
+			return fail("Delete Category Failed", err)
 		}
 		return c.JSON(http.StatusOK, helper.FormatResponse("Delete Category Successfull", nil))
 	}
@@ -3,8 +3,10 @@ package controller
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 
+	"mytodo/helper"
 	"mytodo/model"
 	"mytodo/model/mocks"
 	"net/http"
@@ -33,7 +35,7 @@ func TestCategoryController_AddCategory(t *testing.T) {
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("AddCategory", mock.Anything).Return(true)
+				m.On("AddCategory", mock.Anything).Return(nil)
 			},
 			expectedHttpCode: 201,
 			in:               mockRequest,
@@ -41,7 +43,7 @@ func TestCategoryController_AddCategory(t *testing.T) {
 		{
 			name: "Should be error, because unexpected return from category model",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("AddCategory", mock.Anything).Return(false)
+				m.On("AddCategory", mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 500,
 			in:               mockRequest,
@@ -49,13 +51,25 @@ func TestCategoryController_AddCategory(t *testing.T) {
 		{
 			name: "Should be error, because invalid parse body",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("AddCategory", mock.Anything).Return(false)
+				m.On("AddCategory", mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in: map[string]interface{}{
 				"category": 1234,
 			},
 		},
+		{
+			name:             "Should be error, because category empty",
+			mock:             func(m *mocks.CategoryInterface) {},
+			expectedHttpCode: 422,
+			in:               map[string]interface{}{"color": "#FFFFFF"},
+		},
+		{
+			name:             "Should be error, because color is not a hex color",
+			mock:             func(m *mocks.CategoryInterface) {},
+			expectedHttpCode: 422,
+			in:               map[string]interface{}{"category": "My Todo", "color": "merah"},
+		},
 	}
 	for _, tc := range test {
 		t.Run(tc.name, func(tt *testing.T) {
@@ -85,8 +99,7 @@ func TestCategoryController_AddCategory(t *testing.T) {
 			ctx := e.NewContext(req, res)
 			ctx.Set("user", jwtMockItf)
 
-			err = categoryController.AddCategory()(ctx)
-			require.NoError(t, err)
+			serve(ctx, categoryController.AddCategory())
 
 			w := res.Result()
 			_, err = io.ReadAll(w.Body)
@@ -117,7 +130,7 @@ func TestCategoryController_GetCategories(t *testing.T) {
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("GetCategories", mock.Anything, mock.Anything, mock.Anything).Return([]model.Category{})
+				m.On("GetCategories", mock.Anything, mock.Anything, mock.Anything).Return([]model.Category{}, int64(0), nil)
 			},
 			expectedHttpCode: 200,
 			in:               mockRequest,
@@ -127,17 +140,27 @@ func TestCategoryController_GetCategories(t *testing.T) {
 		{
 			name: "Should be error, because unexpected return from category model",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("GetCategories", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				m.On("GetCategories", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), errors.New("database is down"))
 			},
-			expectedHttpCode: 404,
+			expectedHttpCode: 500,
 			in:               mockRequest,
 			valuePage:        "1",
 			valueContent:     "5",
 		},
+		{
+			name: "Should be Success, default page and content",
+			mock: func(m *mocks.CategoryInterface) {
+				m.On("GetCategories", 1, helper.DefaultPageSize, uint(1)).Return([]model.Category{}, int64(0), nil)
+			},
+			expectedHttpCode: 200,
+			in:               mockRequest,
+			valuePage:        "",
+			valueContent:     "",
+		},
 		{
 			name: "Should be error, because page value format wrong",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("GetCategories", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				m.On("GetCategories", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in:               mockRequest,
@@ -147,7 +170,7 @@ func TestCategoryController_GetCategories(t *testing.T) {
 		{
 			name: "Should be error, because content value format wrong",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("GetCategories", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				m.On("GetCategories", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in:               mockRequest,
@@ -187,8 +210,7 @@ func TestCategoryController_GetCategories(t *testing.T) {
 			ctx := e.NewContext(req, res)
 			ctx.Set("user", jwtMockItf)
 
-			err = categoryController.GetCategories()(ctx)
-			require.NoError(t, err)
+			serve(ctx, categoryController.GetCategories())
 
 			w := res.Result()
 			_, err = io.ReadAll(w.Body)
@@ -213,7 +235,7 @@ func TestCategoryController_GetCategory(t *testing.T) {
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("GetCategory", mock.Anything, mock.Anything).Return(&model.Category{})
+				m.On("GetCategory", mock.Anything, mock.Anything).Return(&model.Category{}, nil)
 			},
 			expectedHttpCode: 200,
 			in:               mockRequest,
@@ -222,7 +244,7 @@ func TestCategoryController_GetCategory(t *testing.T) {
 		{
 			name: "Should be error, because unexpected return from category model",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("GetCategory", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetCategory", mock.Anything, mock.Anything).Return(nil, model.ErrNotFound)
 			},
 			expectedHttpCode: 404,
 			in:               mockRequest,
@@ -231,7 +253,7 @@ func TestCategoryController_GetCategory(t *testing.T) {
 		{
 			name: "Should be error, because id value format wrong",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("GetCategory", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetCategory", mock.Anything, mock.Anything).Return(nil, model.ErrNotFound)
 			},
 			expectedHttpCode: 400,
 			in:               mockRequest,
@@ -268,8 +290,7 @@ func TestCategoryController_GetCategory(t *testing.T) {
 			ctx.SetParamNames("id")
 			ctx.SetParamValues(tc.id)
 
-			err = categoryController.GetCategory()(ctx)
-			require.NoError(t, err)
+			serve(ctx, categoryController.GetCategory())
 
 			w := res.Result()
 			_, err = io.ReadAll(w.Body)
@@ -281,7 +302,7 @@ func TestCategoryController_GetCategory(t *testing.T) {
 
 }
 func TestCategoryController_UpdateCategory(t *testing.T) {
-	mockRequest := model.Category{}
+	mockRequest := model.Category{Category: "Kantor", Color: "#3f48cc"}
 	test := []struct {
 		name             string
 		mock             func(*mocks.CategoryInterface)
@@ -292,7 +313,7 @@ func TestCategoryController_UpdateCategory(t *testing.T) {
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("UpdateCategory", mock.Anything, mock.Anything, mock.Anything).Return(true)
+				m.On("UpdateCategory", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			},
 			expectedHttpCode: 200,
 			in:               mockRequest,
@@ -301,7 +322,7 @@ func TestCategoryController_UpdateCategory(t *testing.T) {
 		{
 			name: "Should be error, because unexpected return from category model",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("UpdateCategory", mock.Anything, mock.Anything, mock.Anything).Return(false)
+				m.On("UpdateCategory", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 500,
 			in:               mockRequest,
@@ -310,7 +331,7 @@ func TestCategoryController_UpdateCategory(t *testing.T) {
 		{
 			name: "Should be error, because id value format wrong",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("UpdateCategory", mock.Anything, mock.Anything, mock.Anything).Return(false)
+				m.On("UpdateCategory", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in:               mockRequest,
@@ -319,7 +340,7 @@ func TestCategoryController_UpdateCategory(t *testing.T) {
 		{
 			name: "Should be error, because bind data error",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("UpdateCategory", mock.Anything, mock.Anything, mock.Anything).Return(false)
+				m.On("UpdateCategory", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in: map[string]any{
@@ -358,8 +379,7 @@ func TestCategoryController_UpdateCategory(t *testing.T) {
 			ctx.SetParamNames("id")
 			ctx.SetParamValues(tc.id)
 
-			err = categoryController.UpdateCategory()(ctx)
-			require.NoError(t, err)
+			serve(ctx, categoryController.UpdateCategory())
 
 			w := res.Result()
 			_, err = io.ReadAll(w.Body)
@@ -382,7 +402,7 @@ func TestCategoryController_DeleteCategory(t *testing.T) {
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("DeleteCategory", mock.Anything, mock.Anything).Return(true)
+				m.On("DeleteCategory", mock.Anything, mock.Anything).Return(nil)
 			},
 			expectedHttpCode: 200,
 			in:               mockRequest,
@@ -391,7 +411,7 @@ func TestCategoryController_DeleteCategory(t *testing.T) {
 		{
 			name: "Should be error, because unexpected return from category model",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("DeleteCategory", mock.Anything, mock.Anything).Return(false)
+				m.On("DeleteCategory", mock.Anything, mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 500,
 			in:               mockRequest,
@@ -400,7 +420,7 @@ func TestCategoryController_DeleteCategory(t *testing.T) {
 		{
 			name: "Should be error, because id value format wrong",
 			mock: func(m *mocks.CategoryInterface) {
-				m.On("DeleteCategory", mock.Anything, mock.Anything).Return(false)
+				m.On("DeleteCategory", mock.Anything, mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in:               mockRequest,
@@ -437,8 +457,7 @@ func TestCategoryController_DeleteCategory(t *testing.T) {
 			ctx.SetParamNames("id")
 			ctx.SetParamValues(tc.id)
 
-			err = categoryController.DeleteCategory()(ctx)
-			require.NoError(t, err)
+			serve(ctx, categoryController.DeleteCategory())
 
 			w := res.Result()
 			_, err = io.ReadAll(w.Body)
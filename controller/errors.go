@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"errors"
+	"mytodo/helper"
+	"mytodo/model"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// apiError membawa pesan response controller beserta error penyebabnya,
+// status HTTP ditentukan HTTPErrorHandler dari jenis error tersebut
+type apiError struct {
+	message string
+	err     error
+}
+
+func (e *apiError) Error() string { return e.message + ": " + e.err.Error() }
+func (e *apiError) Unwrap() error { return e.err }
+
+// fail dikembalikan handler saat model gagal, message dipakai sebagai pesan response
+func fail(message string, err error) error {
+	return &apiError{message: message, err: err}
+}
+
+// errorKinds memetakan jenis error domain ke status HTTP dan code response
+var errorKinds = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{kind: model.ErrNotFound, status: http.StatusNotFound, code: "not_found"},
+	{kind: model.ErrConflict, status: http.StatusConflict, code: "conflict"},
+	{kind: model.ErrValidation, status: http.StatusUnprocessableEntity, code: "validation_failed"},
+	{kind: model.ErrForbidden, status: http.StatusForbidden, code: "forbidden"},
+}
+
+// HTTPErrorHandler menulis semua error yang dikembalikan handler maupun
+// middleware dengan bentuk {"message": ..., "error": {"code": ..., "detail": ...}}.
+// Error validasi request ditambah error.fields berisi pesan per field.
+// Detail error server tidak dikirim ke client, hanya dicatat di log.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	status, code, message, detail := http.StatusInternalServerError, "internal_error", "Internal Server Error", ""
+	var he *echo.HTTPError
+	var ae *apiError
+	fields := fieldErrors(c, err)
+	switch {
+	case fields != nil:
+		status, code, message = http.StatusUnprocessableEntity, "validation_failed", "Validation Failed"
+		if errors.As(err, &ae) {
+			message = ae.message
+		}
+	case errors.As(err, &he):
+		status, code, message = he.Code, statusCode(he.Code), http.StatusText(he.Code)
+		if msg, ok := he.Message.(string); ok {
+			message = msg
+		}
+	case errors.As(err, &ae):
+		message = ae.message
+		for _, kind := range errorKinds {
+			if errors.Is(err, kind.kind) {
+				status, code, detail = kind.status, kind.code, ae.err.Error()
+				break
+			}
+		}
+	}
+	if status >= http.StatusInternalServerError {
+		logrus.Error("Controller: ", c.Request().Method, " ", c.Request().URL.Path, " ", err.Error())
+	}
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, helper.FormatErrorResponse(message, code, detail, fields))
+	}
+	if err != nil {
+		logrus.Error("Controller: Error Menulis Response Error ", err.Error())
+	}
+}
+
+// fieldErrors menerjemahkan error dari c.Validate sesuai bahasa request,
+// nil jika err bukan error validasi
+func fieldErrors(c echo.Context, err error) []helper.FieldError {
+	v, ok := c.Echo().Validator.(*helper.Validator)
+	if !ok {
+		return nil
+	}
+	return v.FieldErrors(err, validationLang(c))
+}
+
+// validationLang memilih bahasa pesan validasi dari query lang atau header
+// Accept-Language, selain id memakai bahasa Inggris
+func validationLang(c echo.Context) string {
+	lang := c.QueryParam("lang")
+	if lang == "" {
+		lang = c.Request().Header.Get("Accept-Language")
+	}
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(lang)), helper.ValidationLangID) {
+		return helper.ValidationLangID
+	}
+	return helper.ValidationLangDefault
+}
+
+// statusCode mengubah status HTTP menjadi code response, misal 400 menjadi bad_request
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(text, "-", "_"), " ", "_"))
+}
@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mytodo/helper"
+	"mytodo/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// serve menjalankan handler seperti echo yang disusun newServer, request
+// divalidasi helper.Validator dan error yang dikembalikan ditulis oleh
+// HTTPErrorHandler
+func serve(c echo.Context, h echo.HandlerFunc) {
+	if c.Echo().Validator == nil {
+		c.Echo().Validator = helper.NewValidator()
+	}
+	if err := h(c); err != nil {
+		HTTPErrorHandler(err, c)
+	}
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	test := []struct {
+		name             string
+		err              error
+		expectedHttpCode int
+		message          string
+		code             string
+		detail           string
+	}{
+		{
+			name:             "Not found from model",
+			err:              fail("Get Todo Failed", fmt.Errorf("get todo 7: %w", model.ErrNotFound)),
+			expectedHttpCode: 404,
+			message:          "Get Todo Failed",
+			code:             "not_found",
+			detail:           "get todo 7: not found",
+		},
+		{
+			name:             "Conflict from model",
+			err:              fail("Update Todo Status Failed", model.ErrOpenItems),
+			expectedHttpCode: 409,
+			message:          "Update Todo Status Failed",
+			code:             "conflict",
+			detail:           "todo still has open checklist items",
+		},
+		{
+			name:             "Illegal transition is a conflict",
+			err:              fail("Update Todo Status Failed", fmt.Errorf("%w from Done to Blocked", model.ErrIllegalTransition)),
+			expectedHttpCode: 409,
+			message:          "Update Todo Status Failed",
+			code:             "conflict",
+			detail:           "illegal status transition from Done to Blocked",
+		},
+		{
+			name:             "Validation from model",
+			err:              fail("Create Todo Failed", model.ErrValidation),
+			expectedHttpCode: 422,
+			message:          "Create Todo Failed",
+			code:             "validation_failed",
+			detail:           "validation failed",
+		},
+		{
+			name:             "Forbidden from model",
+			err:              fail("Create Todo Failed", model.ErrForbidden),
+			expectedHttpCode: 403,
+			message:          "Create Todo Failed",
+			code:             "forbidden",
+			detail:           "forbidden",
+		},
+		{
+			name:             "Database error is hidden",
+			err:              fail("Get Todo Failed", errors.New("dial tcp 127.0.0.1:3306: connection refused")),
+			expectedHttpCode: 500,
+			message:          "Get Todo Failed",
+			code:             "internal_error",
+		},
+		{
+			name:             "Bad request from handler",
+			err:              echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data"),
+			expectedHttpCode: 400,
+			message:          "Error Bind Data",
+			code:             "bad_request",
+		},
+		{
+			name:             "Error from echo",
+			err:              echo.ErrMethodNotAllowed,
+			expectedHttpCode: 405,
+			message:          "Method Not Allowed",
+			code:             "method_not_allowed",
+		},
+		{
+			name:             "Unknown error",
+			err:              errors.New("boom"),
+			expectedHttpCode: 500,
+			message:          "Internal Server Error",
+			code:             "internal_error",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/todo/7", nil)
+			res := httptest.NewRecorder()
+			ctx := e.NewContext(req, res)
+
+			HTTPErrorHandler(tc.err, ctx)
+
+			require.Equal(t, tc.expectedHttpCode, res.Code)
+			body := struct {
+				Message string `json:"message"`
+				Error   struct {
+					Code   string `json:"code"`
+					Detail string `json:"detail"`
+				} `json:"error"`
+			}{}
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+			require.Equal(t, tc.message, body.Message)
+			require.Equal(t, tc.code, body.Error.Code)
+			require.Equal(t, tc.detail, body.Error.Detail)
+		})
+	}
+}
+
+func TestHTTPErrorHandler_Validation(t *testing.T) {
+	test := []struct {
+		name     string
+		lang     string
+		header   string
+		expected []helper.FieldError
+	}{
+		{
+			name: "English by default",
+			expected: []helper.FieldError{
+				{Field: "memo", Rule: "required", Message: "memo is a required field"},
+				{Field: "duration", Rule: "gte", Message: "duration must be 0 or greater"},
+			},
+		},
+		{
+			name:   "Indonesian from Accept-Language",
+			header: "id-ID,id;q=0.9,en;q=0.8",
+			expected: []helper.FieldError{
+				{Field: "memo", Rule: "required", Message: "memo wajib diisi"},
+				{Field: "duration", Rule: "gte", Message: "duration harus 0 atau lebih besar"},
+			},
+		},
+		{
+			name:   "English from lang query",
+			lang:   "en",
+			header: "id-ID",
+			expected: []helper.FieldError{
+				{Field: "memo", Rule: "required", Message: "memo is a required field"},
+				{Field: "duration", Rule: "gte", Message: "duration must be 0 or greater"},
+			},
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = helper.NewValidator()
+			req := httptest.NewRequest(http.MethodPost, "/todo?lang="+tc.lang, nil)
+			if tc.header != "" {
+				req.Header.Set("Accept-Language", tc.header)
+			}
+			res := httptest.NewRecorder()
+			ctx := e.NewContext(req, res)
+
+			err := ctx.Validate(&model.Todo{Duration: -1})
+			require.Error(t, err)
+			HTTPErrorHandler(fail("Create Todo Failed", err), ctx)
+
+			require.Equal(t, http.StatusUnprocessableEntity, res.Code)
+			body := struct {
+				Message string `json:"message"`
+				Error   struct {
+					Code   string              `json:"code"`
+					Fields []helper.FieldError `json:"fields"`
+				} `json:"error"`
+			}{}
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+			require.Equal(t, "Create Todo Failed", body.Message)
+			require.Equal(t, "validation_failed", body.Error.Code)
+			require.Equal(t, tc.expected, body.Error.Fields)
+		})
+	}
+}
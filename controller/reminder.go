@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"mytodo/helper"
+	"mytodo/model"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ReminderControllerInterface interface {
+	AddReminder() echo.HandlerFunc
+	GetReminders() echo.HandlerFunc
+	DeleteReminder() echo.HandlerFunc
+}
+
+type ReminderController struct {
+	model model.ReminderInterface
+}
+
+func NewReminderControllerInterface(m model.ReminderInterface) ReminderControllerInterface {
+	return &ReminderController{
+		model: m,
+	}
+}
+
+func (rc *ReminderController) AddReminder() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Format Id Todo Wrong", nil))
+		}
+		data := model.Reminder{}
+		if err := c.Bind(&data); err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
+		}
+		if (data.OffsetMinutes == nil) == data.RemindAt.IsZero() {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Reminder Needs Either offset_minutes Or remind_at", nil))
+		}
+		if data.OffsetMinutes != nil && *data.OffsetMinutes < 0 {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Offset Minutes Cannot Be Negative", nil))
+		}
+		reminder := model.Reminder{
+			TodoID:        uint(idTodo),
+			OffsetMinutes: data.OffsetMinutes,
+			RemindAt:      data.RemindAt,
+		}
+		res := rc.model.AddReminder(reminder, uint(id))
+		if res == nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Create Reminder Failed", nil))
+		}
+		return c.JSON(http.StatusCreated, helper.FormatResponse("Create Reminder Successfull", res))
+	}
+}
+
+func (rc *ReminderController) GetReminders() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Format Id Todo Wrong", nil))
+		}
+		res := rc.model.GetReminders(idTodo, uint(id))
+		if res == nil {
+			return c.JSON(http.StatusNotFound, helper.FormatResponse("Data Not Found", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Get Reminders Successfull", res))
+	}
+}
+
+func (rc *ReminderController) DeleteReminder() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Format Id Todo Wrong", nil))
+		}
+		idReminder, err := strconv.Atoi(c.Param("reminderId"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Format Id Reminder Wrong", nil))
+		}
+		res := rc.model.DeleteReminder(idReminder, idTodo, uint(id))
+		if !res {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Delete Reminder Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Delete Reminder Successfull", nil))
+	}
+}
@@ -0,0 +1,259 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mytodo/model"
+	"mytodo/model/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderController_AddReminder(t *testing.T) {
+	offset := 15
+	test := []struct {
+		name             string
+		mock             func(*mocks.ReminderInterface)
+		expectedHttpCode int
+		in               any
+		id               string
+	}{
+		{
+			name: "Should be Success, offset before todo",
+			mock: func(m *mocks.ReminderInterface) {
+				m.On("AddReminder", mock.MatchedBy(func(r model.Reminder) bool {
+					return r.TodoID == 1 && r.OffsetMinutes != nil && *r.OffsetMinutes == 15
+				}), uint(1)).Return(&model.Reminder{TodoID: 1, OffsetMinutes: &offset})
+			},
+			expectedHttpCode: 201,
+			in:               map[string]any{"offset_minutes": 15},
+			id:               "1",
+		},
+		{
+			name: "Should be Success, absolute time",
+			mock: func(m *mocks.ReminderInterface) {
+				m.On("AddReminder", mock.Anything, uint(1)).Return(&model.Reminder{TodoID: 1})
+			},
+			expectedHttpCode: 201,
+			in:               map[string]any{"remind_at": time.Date(2023, 11, 6, 8, 0, 0, 0, time.UTC)},
+			id:               "1",
+		},
+		{
+			name: "Should be error, because unexpected return from reminder model",
+			mock: func(m *mocks.ReminderInterface) {
+				m.On("AddReminder", mock.Anything, uint(1)).Return(nil)
+			},
+			expectedHttpCode: 500,
+			in:               map[string]any{"offset_minutes": 15},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because offset and remind_at both empty",
+			mock:             func(m *mocks.ReminderInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]any{},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because offset and remind_at both set",
+			mock:             func(m *mocks.ReminderInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]any{"offset_minutes": 15, "remind_at": time.Date(2023, 11, 6, 8, 0, 0, 0, time.UTC)},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because offset negative",
+			mock:             func(m *mocks.ReminderInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]any{"offset_minutes": -5},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.ReminderInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]any{"offset_minutes": 15},
+			id:               "!",
+		},
+		{
+			name:             "Should be error, because invalid parse body",
+			mock:             func(m *mocks.ReminderInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]any{"offset_minutes": "soon"},
+			id:               "1",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			reminderMockModel := new(mocks.ReminderInterface)
+
+			tc.mock(reminderMockModel)
+
+			reminderController := NewReminderControllerInterface(reminderMockModel)
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/todo/:id/reminders", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.id)
+
+			err = reminderController.AddReminder()(ctx)
+			require.NoError(t, err)
+
+			w := res.Result()
+			_, err = io.ReadAll(w.Body)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, w.StatusCode)
+		})
+	}
+}
+
+func TestReminderController_GetReminders(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.ReminderInterface)
+		expectedHttpCode int
+		id               string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.ReminderInterface) {
+				m.On("GetReminders", 1, uint(1)).Return([]model.Reminder{})
+			},
+			expectedHttpCode: 200,
+			id:               "1",
+		},
+		{
+			name: "Should be error, because unexpected return from reminder model",
+			mock: func(m *mocks.ReminderInterface) {
+				m.On("GetReminders", 1, uint(1)).Return(nil)
+			},
+			expectedHttpCode: 404,
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.ReminderInterface) {},
+			expectedHttpCode: 400,
+			id:               "!",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			reminderMockModel := new(mocks.ReminderInterface)
+
+			tc.mock(reminderMockModel)
+
+			reminderController := NewReminderControllerInterface(reminderMockModel)
+
+			req := httptest.NewRequest(http.MethodGet, "/todo/:id/reminders", nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.id)
+
+			err := reminderController.GetReminders()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestReminderController_DeleteReminder(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.ReminderInterface)
+		expectedHttpCode int
+		id               string
+		reminderId       string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.ReminderInterface) {
+				m.On("DeleteReminder", 2, 1, uint(1)).Return(true)
+			},
+			expectedHttpCode: 200,
+			id:               "1",
+			reminderId:       "2",
+		},
+		{
+			name: "Should be error, because unexpected return from reminder model",
+			mock: func(m *mocks.ReminderInterface) {
+				m.On("DeleteReminder", 2, 1, uint(1)).Return(false)
+			},
+			expectedHttpCode: 500,
+			id:               "1",
+			reminderId:       "2",
+		},
+		{
+			name:             "Should be error, because reminder id value format wrong",
+			mock:             func(m *mocks.ReminderInterface) {},
+			expectedHttpCode: 400,
+			id:               "1",
+			reminderId:       "!",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			reminderMockModel := new(mocks.ReminderInterface)
+
+			tc.mock(reminderMockModel)
+
+			reminderController := NewReminderControllerInterface(reminderMockModel)
+
+			req := httptest.NewRequest(http.MethodDelete, "/todo/:id/reminders/:reminderId", nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id", "reminderId")
+			ctx.SetParamValues(tc.id, tc.reminderId)
+
+			err := reminderController.DeleteReminder()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
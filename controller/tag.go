@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"mytodo/helper"
+	"mytodo/model"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TagControllerInterface interface {
+	AddTag() echo.HandlerFunc
+	GetTags() echo.HandlerFunc
+	GetTag() echo.HandlerFunc
+	UpdateTag() echo.HandlerFunc
+	DeleteTag() echo.HandlerFunc
+}
+
+type TagController struct {
+	model model.TagInterface
+}
+
+func NewTagControllerInterface(m model.TagInterface) TagControllerInterface {
+	return &TagController{
+		model: m,
+	}
+}
+
+func (tc *TagController) AddTag() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		data := model.Tag{}
+		if err := c.Bind(&data); err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
+		}
+		if model.NormalizeTagName(data.Name) == "" {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Tag Name Is Required", nil))
+		}
+		data.UserID = uint(id)
+		if err := tc.model.AddTag(data); err != nil {
+			return fail("Create Tag Failed", err)
+		}
+		return c.JSON(http.StatusCreated, helper.FormatResponse("Create Tag Successfull", nil))
+	}
+}
+
+func (tc *TagController) GetTags() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		page, perPage, err := helper.PageParams(c.QueryParam("page"), c.QueryParam("content"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Get Page Or Content Value", nil))
+		}
+		tags, total := tc.model.GetTags(page, perPage, uint(id))
+		if tags == nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Get Tags Failed", nil))
+		}
+		pagination := helper.NewPagination(c.Request().URL, page, perPage, total)
+		return c.JSON(http.StatusOK, helper.FormatPaginatedResponse("Success Get Tags Data", tags, pagination))
+	}
+}
+
+func (tc *TagController) GetTag() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		idUser := claims["id"].(float64)
+		idTag, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Tag Format Wrong", nil))
+		}
+		res := tc.model.GetTag(idTag, uint(idUser))
+		if res == nil {
+			return c.JSON(http.StatusNotFound, helper.FormatResponse("Data Not Found", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Get Tag Successfull", res))
+	}
+}
+
+func (tc *TagController) UpdateTag() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		idUser := claims["id"].(float64)
+		tag := model.Tag{}
+		if err := c.Bind(&tag); err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
+		}
+		idTag, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Tag Format Wrong", nil))
+		}
+		if err := tc.model.UpdateTag(tag, idTag, uint(idUser)); err != nil {
+			return fail("Update Tag Failed", err)
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Update Tag Successfull", nil))
+	}
+}
+
+func (tc *TagController) DeleteTag() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		idUser := claims["id"].(float64)
+		idTag, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Tag Format Wrong", nil))
+		}
+		res := tc.model.DeleteTag(idTag, uint(idUser))
+		if !res {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Delete Tag Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Delete Tag Successfull", nil))
+	}
+}
@@ -0,0 +1,413 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"mytodo/helper"
+	"mytodo/model"
+	"mytodo/model/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagController_AddTag(t *testing.T) {
+	mockRequest := model.Tag{
+		Name:  "Urgent",
+		Color: "#FF0000",
+	}
+	test := []struct {
+		name             string
+		mock             func(*mocks.TagInterface)
+		expectedHttpCode int
+		in               any
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TagInterface) {
+				m.On("AddTag", mock.MatchedBy(func(tag model.Tag) bool { return tag.UserID == 1 })).Return(nil)
+			},
+			expectedHttpCode: 201,
+			in:               mockRequest,
+		},
+		{
+			name: "Should be error, because unexpected return from tag model",
+			mock: func(m *mocks.TagInterface) {
+				m.On("AddTag", mock.Anything).Return(errors.New("db down"))
+			},
+			expectedHttpCode: 500,
+			in:               mockRequest,
+		},
+		{
+			name: "Should be error, because tag name already exists",
+			mock: func(m *mocks.TagInterface) {
+				m.On("AddTag", mock.Anything).Return(fmt.Errorf("tag urgent: %w", model.ErrConflict))
+			},
+			expectedHttpCode: 409,
+			in:               mockRequest,
+		},
+		{
+			name:             "Should be error, because tag name empty",
+			mock:             func(m *mocks.TagInterface) {},
+			expectedHttpCode: 400,
+			in:               model.Tag{Name: "  "},
+		},
+		{
+			name:             "Should be error, because invalid parse body",
+			mock:             func(m *mocks.TagInterface) {},
+			expectedHttpCode: 400,
+			in: map[string]any{
+				"name": 1234,
+			},
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			tagMockModel := new(mocks.TagInterface)
+
+			tc.mock(tagMockModel)
+
+			tagController := NewTagControllerInterface(tagMockModel)
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/tag", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			serve(ctx, tagController.AddTag())
+
+			w := res.Result()
+			_, err = io.ReadAll(w.Body)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, w.StatusCode)
+		})
+	}
+}
+
+func TestTagController_GetTags(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.TagInterface)
+		expectedHttpCode int
+		valuePage        string
+		valueContent     string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TagInterface) {
+				m.On("GetTags", 1, 5, uint(1)).Return([]model.Tag{}, int64(0))
+			},
+			expectedHttpCode: 200,
+			valuePage:        "1",
+			valueContent:     "5",
+		},
+		{
+			name: "Should be Success, page and content are clamped",
+			mock: func(m *mocks.TagInterface) {
+				m.On("GetTags", 1, helper.MaxPageSize, uint(1)).Return([]model.Tag{}, int64(0))
+			},
+			expectedHttpCode: 200,
+			valuePage:        "0",
+			valueContent:     "1000000",
+		},
+		{
+			name: "Should be error, because unexpected return from tag model",
+			mock: func(m *mocks.TagInterface) {
+				m.On("GetTags", 1, 5, uint(1)).Return(nil, int64(0))
+			},
+			expectedHttpCode: 500,
+			valuePage:        "1",
+			valueContent:     "5",
+		},
+		{
+			name:             "Should be error, because page value format wrong",
+			mock:             func(m *mocks.TagInterface) {},
+			expectedHttpCode: 400,
+			valuePage:        "abc",
+			valueContent:     "5",
+		},
+		{
+			name:             "Should be error, because content value format wrong",
+			mock:             func(m *mocks.TagInterface) {},
+			expectedHttpCode: 400,
+			valuePage:        "1",
+			valueContent:     "abc",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			tagMockModel := new(mocks.TagInterface)
+
+			tc.mock(tagMockModel)
+
+			tagController := NewTagControllerInterface(tagMockModel)
+
+			req := httptest.NewRequest(http.MethodGet, "/tag", nil)
+			q := req.URL.Query()
+			q.Add("page", tc.valuePage)
+			q.Add("content", tc.valueContent)
+			req.URL.RawQuery = q.Encode()
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			err := tagController.GetTags()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTagController_GetTag(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.TagInterface)
+		expectedHttpCode int
+		id               string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TagInterface) {
+				m.On("GetTag", 1, uint(1)).Return(&model.Tag{Name: "urgent"})
+			},
+			expectedHttpCode: 200,
+			id:               "1",
+		},
+		{
+			name: "Should be error, because unexpected return from tag model",
+			mock: func(m *mocks.TagInterface) {
+				m.On("GetTag", 1, uint(1)).Return(nil)
+			},
+			expectedHttpCode: 404,
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.TagInterface) {},
+			expectedHttpCode: 400,
+			id:               "!",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			tagMockModel := new(mocks.TagInterface)
+
+			tc.mock(tagMockModel)
+
+			tagController := NewTagControllerInterface(tagMockModel)
+
+			req := httptest.NewRequest(http.MethodGet, "/tag/:id", nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.id)
+
+			err := tagController.GetTag()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTagController_UpdateTag(t *testing.T) {
+	mockRequest := model.Tag{
+		Name: "client-x",
+	}
+	test := []struct {
+		name             string
+		mock             func(*mocks.TagInterface)
+		expectedHttpCode int
+		in               any
+		id               string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TagInterface) {
+				m.On("UpdateTag", mock.Anything, 1, uint(1)).Return(nil)
+			},
+			expectedHttpCode: 200,
+			in:               mockRequest,
+			id:               "1",
+		},
+		{
+			name: "Should be error, because unexpected return from tag model",
+			mock: func(m *mocks.TagInterface) {
+				m.On("UpdateTag", mock.Anything, 1, uint(1)).Return(errors.New("db down"))
+			},
+			expectedHttpCode: 500,
+			in:               mockRequest,
+			id:               "1",
+		},
+		{
+			name: "Should be error, because tag name already exists",
+			mock: func(m *mocks.TagInterface) {
+				m.On("UpdateTag", mock.Anything, 1, uint(1)).Return(fmt.Errorf("tag client-x: %w", model.ErrConflict))
+			},
+			expectedHttpCode: 409,
+			in:               mockRequest,
+			id:               "1",
+		},
+		{
+			name: "Should be error, because tag not found",
+			mock: func(m *mocks.TagInterface) {
+				m.On("UpdateTag", mock.Anything, 1, uint(1)).Return(fmt.Errorf("tag 1: %w", model.ErrNotFound))
+			},
+			expectedHttpCode: 404,
+			in:               mockRequest,
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.TagInterface) {},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+			id:               "!",
+		},
+		{
+			name:             "Should be error, because invalid parse body",
+			mock:             func(m *mocks.TagInterface) {},
+			expectedHttpCode: 400,
+			in: map[string]any{
+				"name": 1234,
+			},
+			id: "1",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			tagMockModel := new(mocks.TagInterface)
+
+			tc.mock(tagMockModel)
+
+			tagController := NewTagControllerInterface(tagMockModel)
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, "/tag/:id", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.id)
+
+			serve(ctx, tagController.UpdateTag())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTagController_DeleteTag(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.TagInterface)
+		expectedHttpCode int
+		id               string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TagInterface) {
+				m.On("DeleteTag", 1, uint(1)).Return(true)
+			},
+			expectedHttpCode: 200,
+			id:               "1",
+		},
+		{
+			name: "Should be error, because unexpected return from tag model",
+			mock: func(m *mocks.TagInterface) {
+				m.On("DeleteTag", 1, uint(1)).Return(false)
+			},
+			expectedHttpCode: 500,
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.TagInterface) {},
+			expectedHttpCode: 400,
+			id:               "!",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			tagMockModel := new(mocks.TagInterface)
+
+			tc.mock(tagMockModel)
+
+			tagController := NewTagControllerInterface(tagMockModel)
+
+			req := httptest.NewRequest(http.MethodDelete, "/tag/:id", nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.id)
+
+			err := tagController.DeleteTag()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"mytodo/helper"
 	"mytodo/model"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -14,17 +18,28 @@ type TodoControllerInterface interface {
 	GetTodos() echo.HandlerFunc
 	GetTodo() echo.HandlerFunc
 	UpdateTodo() echo.HandlerFunc
+	UpdateTodoCategory() echo.HandlerFunc
+	BreakdownTodo() echo.HandlerFunc
+	AcceptBreakdown() echo.HandlerFunc
 	UpdateTodoStatus() echo.HandlerFunc
 	DeleteTodo() echo.HandlerFunc
+	UpdateOccurrence() echo.HandlerFunc
+	SkipOccurrence() echo.HandlerFunc
+	GetStatusHistory() echo.HandlerFunc
+	SearchTodos() echo.HandlerFunc
 }
 
 type TodoController struct {
-	model model.TodoInterface
+	model      model.TodoInterface
+	aiModel    model.TodoAIInterface
+	usageModel model.AIUsageInterface
 }
 
-func NewTodoControllerInterface(m model.TodoInterface) TodoControllerInterface {
+func NewTodoControllerInterface(m model.TodoInterface, am model.TodoAIInterface, um model.AIUsageInterface) TodoControllerInterface {
 	return &TodoController{
-		model: m,
+		model:      m,
+		aiModel:    am,
+		usageModel: um,
 	}
 }
 
@@ -34,40 +49,71 @@ func (tc *TodoController) AddTodo() echo.HandlerFunc {
 		id := claims["id"].(float64)
 		data := model.Todo{}
 		if err := c.Bind(&data); err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data")
 		}
-		data.Status = "OnGoing"
+		if err := c.Validate(&data); err != nil {
+			return fail("Create Todo Failed", err)
+		}
+		if !normalizeRRule(&data) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid Recurrence Rule")
+		}
+		data.Status = model.StatusTodo
+		data.StartedAt = nil
+		data.FinishedAt = nil
 		data.UserID = uint(id)
-		res := tc.model.AddTodo(data)
-		if !res {
-			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Create Todo Failed", nil))
+		data.CategorySource = model.CategorySourceManual
+		data.CategoryConfidence = 0
+		if (data.CategoryID == nil || *data.CategoryID == 0) && data.AutoCategorize {
+			// AI hanya dipakai selama kuota masih ada, selain itu classifier lokal
+			useAI := tc.usageModel.CheckQuota(uint(id)) == nil
+			prediction, err := tc.aiModel.CategorizeTodo(c.Request().Context(), data.Memo, useAI, uint(id))
+			switch {
+			case errors.Is(err, model.ErrNoCategory):
+				// user tanpa category tetap bisa membuat todo tanpa category
+			case err != nil:
+				return fail("Auto Categorize Todo Failed", err)
+			default:
+				data.CategoryID = &prediction.CategoryID
+				data.CategorySource = prediction.Source
+				data.CategoryConfidence = prediction.Confidence
+			}
 		}
-		return c.JSON(http.StatusCreated, helper.FormatResponse("Create Todo Successfull", nil))
+		res, err := tc.model.AddTodo(data)
+		if err != nil {
+			return fail("Create Todo Failed", err)
+		}
+		return c.JSON(http.StatusCreated, helper.FormatResponse("Create Todo Successfull", res))
 	}
 }
 
 func (tc *TodoController) GetTodos() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		// todo := []model.Todo{}
 		claims := helper.ExtractToken("user", c)
 		id := claims["id"].(float64)
-		pageString := c.QueryParam("page")
-		page, err := strconv.Atoi(pageString)
+		page, content, err := helper.PageParams(c.QueryParam("page"), c.QueryParam("content"))
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Get Page Value", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Get Page Or Content Value")
+		}
+		filter, msg := parseTodoFilter(c)
+		if msg != "" {
+			return echo.NewHTTPError(http.StatusBadRequest, msg)
 		}
-		perPageString := c.QueryParam("content")
-		content, err := strconv.Atoi(perPageString)
+		if filter.Cursor {
+			page = 1
+		}
+		todo, total, err := tc.model.GetTodos(page, content, uint(id), filter)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Get Content Value", nil))
+			return fail("Get Todo Failed", err)
 		}
-		status := c.QueryParam("status")
-		date := c.QueryParam("date")
-		todo := tc.model.GetTodos(page, content, uint(id), status, date)
-		if todo == nil {
-			return c.JSON(http.StatusNotFound, helper.FormatResponse("Data Not Found", nil))
+		pagination := helper.NewPagination(c.Request().URL, page, content, total)
+		if filter.Cursor {
+			var nextID uint
+			if len(todo) == content && todo[len(todo)-1].ID > 1 {
+				nextID = todo[len(todo)-1].ID
+			}
+			pagination = helper.NewCursorPagination(c.Request().URL, content, total, nextID)
 		}
-		return c.JSON(http.StatusOK, helper.FormatResponse("Get Todo Successfull", todo))
+		return c.JSON(http.StatusOK, helper.FormatPaginatedResponse("Get Todo Successfull", todo, pagination))
 	}
 }
 
@@ -78,11 +124,11 @@ func (tc *TodoController) GetTodo() echo.HandlerFunc {
 		idTodoString := c.Param("id")
 		idTodo, err := strconv.Atoi(idTodoString)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Category Format Wrong", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Id Category Format Wrong")
 		}
-		res := tc.model.GetTodo(idTodo, uint(id))
-		if res == nil {
-			return c.JSON(http.StatusNotFound, helper.FormatResponse("Data Not Found", nil))
+		res, err := tc.model.GetTodo(idTodo, uint(id))
+		if err != nil {
+			return fail("Get Todo Failed", err)
 		}
 		return c.JSON(http.StatusOK, helper.FormatResponse("Get Todo Successfull", res))
 	}
@@ -95,19 +141,132 @@ func (tc *TodoController) UpdateTodo() echo.HandlerFunc {
 		idTodoString := c.Param("id")
 		idTodo, err := strconv.Atoi(idTodoString)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Format Id", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Format Id")
 		}
 		todo := model.Todo{}
 		if err := c.Bind(&todo); err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data")
+		}
+		if err := c.Validate(&todo); err != nil {
+			return fail("Update Todo Failed", err)
+		}
+		if !normalizeRRule(&todo) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid Recurrence Rule")
 		}
-		res := tc.model.UpdateTodo(idTodo, uint(id), todo)
-		if !res {
-			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Update Todo Failed", nil))
+		if err := tc.model.UpdateTodo(idTodo, uint(id), todo); err != nil {
+			return fail("Update Todo Failed", err)
 		}
 		return c.JSON(http.StatusOK, helper.FormatResponse("Update Todo Successfull", nil))
 	}
 }
+
+// UpdateTodoCategory mengoreksi category todo, misalnya hasil auto categorize yang salah
+func (tc *TodoController) UpdateTodoCategory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Format Id")
+		}
+		data := struct {
+			CategoryID uint `json:"category_id" form:"category_id"`
+		}{}
+		if err := c.Bind(&data); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data")
+		}
+		if data.CategoryID == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Category Id Is Required")
+		}
+		if err := tc.model.UpdateTodoCategory(idTodo, uint(id), data.CategoryID); err != nil {
+			return fail("Update Category Todo Failed", err)
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Update Category Todo Successfull", nil))
+	}
+}
+
+func (tc *TodoController) BreakdownTodo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Format Id")
+		}
+		data := struct {
+			Lang string `json:"lang" form:"lang" query:"lang"`
+		}{}
+		if err := c.Bind(&data); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data")
+		}
+		if data.Lang == "" {
+			data.Lang = model.LangID
+		}
+		if !model.ValidLang(data.Lang) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Lang Must Be id Or en")
+		}
+		todo, err := tc.model.GetTodo(idTodo, uint(id))
+		if err != nil {
+			return fail("Breakdown Todo Failed", err)
+		}
+		if err := tc.usageModel.CheckQuota(uint(id)); err != nil {
+			return quotaResponse(c, err)
+		}
+		res, err := tc.aiModel.BreakdownTodo(c.Request().Context(), *todo, data.Lang, uint(id))
+		if errors.Is(err, model.ErrInvalidAIOutput) {
+			return c.JSON(http.StatusBadGateway, helper.FormatResponse("AI Returned Invalid Breakdown", nil))
+		}
+		if status, msg, found := aiError(err); found {
+			return c.JSON(status, helper.FormatResponse(msg, nil))
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Breakdown Todo Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Breakdown Todo Successfull", res))
+	}
+}
+
+func (tc *TodoController) AcceptBreakdown() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Format Id")
+		}
+		data := struct {
+			Mode  string           `json:"mode"`
+			Steps []model.TodoStep `json:"steps"`
+		}{}
+		if err := c.Bind(&data); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data")
+		}
+		if data.Mode == "" {
+			data.Mode = model.BreakdownModeItems
+		}
+		if data.Mode != model.BreakdownModeItems && data.Mode != model.BreakdownModeTodos {
+			return echo.NewHTTPError(http.StatusBadRequest, "Mode Must Be items Or todos")
+		}
+		if len(data.Steps) == 0 || len(data.Steps) > model.MaxBreakdownSteps {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Steps Must Contain 1 To %d Items", model.MaxBreakdownSteps))
+		}
+		for _, step := range data.Steps {
+			if err := step.Validate(); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Invalid Step: "+err.Error())
+			}
+		}
+		todo, err := tc.model.GetTodo(idTodo, uint(id))
+		if err != nil {
+			return fail("Accept Breakdown Failed", err)
+		}
+		res := tc.aiModel.AcceptBreakdown(*todo, data.Steps, data.Mode, uint(id))
+		if res == nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Accept Breakdown Failed", nil))
+		}
+		return c.JSON(http.StatusCreated, helper.FormatResponse("Accept Breakdown Successfull", res))
+	}
+}
+
 func (tc *TodoController) UpdateTodoStatus() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		claims := helper.ExtractToken("user", c)
@@ -115,11 +274,20 @@ func (tc *TodoController) UpdateTodoStatus() echo.HandlerFunc {
 		idTodoString := c.Param("id")
 		idTodo, err := strconv.Atoi(idTodoString)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Format Id Wrong", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Id Wrong")
+		}
+		data := model.TodoStatusRequest{}
+		if err := c.Bind(&data); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data")
 		}
-		res := tc.model.UpdateTodoStatus(idTodo, uint(id), "Done")
-		if !res {
-			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Update Todo Status Failed", nil))
+		if data.Status == "" {
+			data.Status = model.StatusDone
+		}
+		if !model.IsTodoStatus(data.Status) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Unknown Status, Allowed Status: "+strings.Join(model.TodoStatuses, ", "))
+		}
+		if err := tc.model.UpdateTodoStatus(idTodo, uint(id), data.Status); err != nil {
+			return fail("Update Todo Status Failed", err)
 		}
 		return c.JSON(http.StatusOK, helper.FormatResponse("Update Todo Status Successfull", nil))
 	}
@@ -132,12 +300,219 @@ func (tc *TodoController) DeleteTodo() echo.HandlerFunc {
 		idTodoString := c.Param("id")
 		idTodo, err := strconv.Atoi(idTodoString)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Format Id Wrong", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Id Wrong")
 		}
-		res := tc.model.DeleteTodo(idTodo, uint(id))
-		if !res {
-			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Delete Todo Failed", nil))
+		if err := tc.model.DeleteTodo(idTodo, uint(id)); err != nil {
+			return fail("Delete Todo Failed", err)
 		}
 		return c.JSON(http.StatusOK, helper.FormatResponse("Delete Todo Successfull", nil))
 	}
 }
+
+func (tc *TodoController) UpdateOccurrence() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Id Wrong")
+		}
+		date := c.Param("date")
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Date Wrong")
+		}
+		todo := model.Todo{}
+		if err := c.Bind(&todo); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data")
+		}
+		if err := tc.model.UpdateOccurrence(idTodo, uint(id), date, todo); err != nil {
+			return fail("Update Todo Occurrence Failed", err)
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Update Todo Occurrence Successfull", nil))
+	}
+}
+
+func (tc *TodoController) SkipOccurrence() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Id Wrong")
+		}
+		date := c.Param("date")
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Date Wrong")
+		}
+		if err := tc.model.SkipOccurrence(idTodo, uint(id), date); err != nil {
+			return fail("Skip Todo Occurrence Failed", err)
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Skip Todo Occurrence Successfull", nil))
+	}
+}
+
+func (tc *TodoController) GetStatusHistory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Id Wrong")
+		}
+		res, err := tc.model.GetStatusHistory(idTodo, uint(id))
+		if err != nil {
+			return fail("Get Todo Status History Failed", err)
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Get Todo Status History Successfull", res))
+	}
+}
+
+func (tc *TodoController) SearchTodos() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		query := strings.TrimSpace(c.QueryParam("q"))
+		if query == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Search Query Is Required")
+		}
+		page, content, err := helper.PageParams(c.QueryParam("page"), c.QueryParam("content"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Get Page Or Content Value")
+		}
+		res, total, err := tc.model.SearchTodos(query, page, content, uint(id))
+		if err != nil {
+			return fail("Search Todo Failed", err)
+		}
+		pagination := helper.NewPagination(c.Request().URL, page, content, total)
+		return c.JSON(http.StatusOK, helper.FormatPaginatedResponse("Search Todo Successfull", res, pagination))
+	}
+}
+
+// todoFilterParams adalah query parameter yang dikenali oleh GET /todo,
+// lang bukan filter tetapi bahasa pesan validasi
+var todoFilterParams = []string{"page", "content", "cursor", "status", "category_id", "date", "from", "to", "overdue", "tag", "tag_mode", "text", "sort", "lang"}
+
+// parseTodoFilter membaca filter GET /todo, pesan error dikembalikan jika ada parameter yang tidak valid
+func parseTodoFilter(c echo.Context) (model.TodoFilter, string) {
+	filter := model.TodoFilter{}
+	for key := range c.QueryParams() {
+		known := false
+		for _, param := range todoFilterParams {
+			known = known || param == key
+		}
+		if !known {
+			return filter, fmt.Sprintf("Unknown Filter Field %s, Allowed: %s", key, strings.Join(todoFilterParams, ", "))
+		}
+	}
+	if status := c.QueryParam("status"); status != "" {
+		if !model.IsTodoStatus(status) {
+			return filter, "Unknown Status, Allowed: " + strings.Join(model.TodoStatuses, ", ")
+		}
+		filter.Status = status
+	}
+	if categoryID := c.QueryParam("category_id"); categoryID != "" {
+		id, err := strconv.ParseUint(categoryID, 10, 64)
+		if err != nil {
+			return filter, "Error Get Category Id Value"
+		}
+		filter.CategoryID = uint(id)
+	}
+	if date := c.QueryParam("date"); date != "" {
+		if c.QueryParam("from") != "" || c.QueryParam("to") != "" {
+			return filter, "Date Cannot Be Combined With From Or To"
+		}
+		day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			return filter, "Error Get Date Value, Format YYYY-MM-DD"
+		}
+		end := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		filter.From, filter.To = &day, &end
+	}
+	for _, bound := range []struct {
+		param string
+		label string
+		dest  **time.Time
+	}{{param: "from", label: "From", dest: &filter.From}, {param: "to", label: "To", dest: &filter.To}} {
+		value := c.QueryParam(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := parseFilterTime(value, bound.param == "to")
+		if err != nil {
+			return filter, fmt.Sprintf("Error Get %s Value, Format YYYY-MM-DD Or RFC3339", bound.label)
+		}
+		*bound.dest = &t
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return filter, "From Must Be Before To"
+	}
+	if overdue := c.QueryParam("overdue"); overdue != "" {
+		value, err := strconv.ParseBool(overdue)
+		if err != nil {
+			return filter, "Error Get Overdue Value"
+		}
+		filter.Overdue = value
+	}
+	for _, tag := range strings.Split(c.QueryParam("tag"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	switch c.QueryParam("tag_mode") {
+	case "", "any":
+	case "all":
+		filter.TagMatchAll = true
+	default:
+		return filter, "Tag Mode Must Be any or all"
+	}
+	filter.Text = strings.TrimSpace(c.QueryParam("text"))
+	sortFields, err := model.ParseTodoSort(c.QueryParam("sort"))
+	if err != nil {
+		return filter, "Invalid Sort Value, " + err.Error()
+	}
+	filter.Sort = sortFields
+	if c.QueryParams().Has("cursor") {
+		if c.QueryParam("page") != "" || len(filter.Sort) > 0 {
+			return filter, "Cursor Cannot Be Combined With Page Or Sort"
+		}
+		if filter.From != nil && filter.To != nil {
+			return filter, "Cursor Cannot Be Combined With A Date Range"
+		}
+		beforeID, err := helper.DecodeCursor(c.QueryParam("cursor"))
+		if err != nil {
+			return filter, "Invalid Cursor"
+		}
+		filter.Cursor = true
+		filter.BeforeID = beforeID
+	}
+	return filter, ""
+}
+
+// parseFilterTime menerima tanggal (YYYY-MM-DD) atau RFC3339. Tanggal pada
+// batas akhir dianggap sampai akhir hari tersebut.
+func parseFilterTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return day, nil
+}
+
+// normalizeRRule memvalidasi aturan pengulangan dan menyimpannya dalam bentuk normal
+func normalizeRRule(todo *model.Todo) bool {
+	if todo.RRule == "" {
+		return true
+	}
+	rule, err := helper.ParseRRule(todo.RRule)
+	if err != nil {
+		return false
+	}
+	todo.RRule = rule.String()
+	return true
+}
@@ -3,9 +3,13 @@ package controller
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"time"
 
+	"mytodo/ai"
+	"mytodo/helper"
 	"mytodo/model"
 	"mytodo/model/mocks"
 	"net/http"
@@ -19,22 +23,28 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func categoryID(id uint) *uint {
+	return &id
+}
+
 func TestTodoController_AddTodo(t *testing.T) {
 	mockRequest := model.Todo{
 		Memo:       "Kelas Live Session Golang",
 		DateTime:   time.Date(2023, 11, 03, 16, 0, 0, 0, time.Local),
-		CategoryID: 1,
+		CategoryID: categoryID(1),
 	}
 	test := []struct {
 		name             string
 		mock             func(*mocks.TodoInterface)
+		aiMock           func(*mocks.TodoAIInterface)
+		quota            error
 		expectedHttpCode int
 		in               any
 	}{
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("AddTodo", mock.Anything).Return(true)
+				m.On("AddTodo", mock.Anything).Return(&model.Todo{}, nil)
 			},
 			expectedHttpCode: 201,
 			in:               mockRequest,
@@ -42,7 +52,7 @@ func TestTodoController_AddTodo(t *testing.T) {
 		{
 			name: "Should be error, because unexpected return from todo model",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("AddTodo", mock.Anything).Return(false)
+				m.On("AddTodo", mock.Anything).Return(nil, errors.New("database is down"))
 			},
 			expectedHttpCode: 500,
 			in:               mockRequest,
@@ -50,23 +60,111 @@ func TestTodoController_AddTodo(t *testing.T) {
 		{
 			name: "Should be error, because invalid parse body",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("AddTodo", mock.Anything).Return(false)
+				m.On("AddTodo", mock.Anything).Return(nil, errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in: map[string]interface{}{
 				"memo": 1234,
 			},
 		},
+		{
+			name: "Should be error, because invalid recurrence rule",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("AddTodo", mock.Anything).Return(&model.Todo{}, nil)
+			},
+			expectedHttpCode: 400,
+			in: map[string]interface{}{
+				"memo":  "Standup",
+				"rrule": "FREQ=HOURLY",
+			},
+		},
+		{
+			name:             "Should be error, because memo empty",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 422,
+			in:               map[string]interface{}{"memo": "", "category_id": 1},
+		},
+		{
+			name:             "Should be error, because duration negative",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 422,
+			in:               map[string]interface{}{"memo": "Standup", "duration": -5},
+		},
+		{
+			name: "Should be Success, with auto categorize",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("AddTodo", mock.MatchedBy(func(todo model.Todo) bool {
+					return todo.CategoryID != nil && *todo.CategoryID == 2 && todo.CategorySource == model.CategorySourceAI && todo.CategoryConfidence == 0.9
+				})).Return(&model.Todo{CategoryID: categoryID(2), CategorySource: model.CategorySourceAI, CategoryConfidence: 0.9}, nil)
+			},
+			aiMock: func(m *mocks.TodoAIInterface) {
+				m.On("CategorizeTodo", mock.Anything, "Belanja sayur", true, uint(1)).Return(&model.CategoryPrediction{CategoryID: 2, Category: "Belanja", Confidence: 0.9, Source: model.CategorySourceAI}, nil)
+			},
+			expectedHttpCode: 201,
+			in:               map[string]interface{}{"memo": "Belanja sayur", "auto_categorize": true},
+		},
+		{
+			name: "Should be Success, with local classifier because quota exceeded",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("AddTodo", mock.MatchedBy(func(todo model.Todo) bool {
+					return todo.CategoryID != nil && *todo.CategoryID == 3 && todo.CategorySource == model.CategorySourceLocal
+				})).Return(&model.Todo{CategoryID: categoryID(3)}, nil)
+			},
+			aiMock: func(m *mocks.TodoAIInterface) {
+				m.On("CategorizeTodo", mock.Anything, "Belanja sayur", false, uint(1)).Return(&model.CategoryPrediction{CategoryID: 3, Category: "Belanja", Confidence: 0.6, Source: model.CategorySourceLocal}, nil)
+			},
+			quota:            &model.QuotaExceededError{Period: "daily"},
+			expectedHttpCode: 201,
+			in:               map[string]interface{}{"memo": "Belanja sayur", "auto_categorize": true},
+		},
+		{
+			name: "Should be Success, without category because user has no category",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("AddTodo", mock.MatchedBy(func(todo model.Todo) bool {
+					return todo.CategoryID == nil && todo.CategorySource == model.CategorySourceManual
+				})).Return(&model.Todo{}, nil)
+			},
+			aiMock: func(m *mocks.TodoAIInterface) {
+				m.On("CategorizeTodo", mock.Anything, "Belanja sayur", true, uint(1)).Return(nil, model.ErrNoCategory)
+			},
+			expectedHttpCode: 201,
+			in:               map[string]interface{}{"memo": "Belanja sayur", "auto_categorize": true},
+		},
+		{
+			name: "Should be error, because auto categorize failed",
+			mock: func(m *mocks.TodoInterface) {},
+			aiMock: func(m *mocks.TodoAIInterface) {
+				m.On("CategorizeTodo", mock.Anything, "Belanja sayur", true, uint(1)).Return(nil, errors.New("database is down"))
+			},
+			expectedHttpCode: 500,
+			in:               map[string]interface{}{"memo": "Belanja sayur", "auto_categorize": true},
+		},
+		{
+			name: "Should be Success, auto categorize ignored because category chosen",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("AddTodo", mock.MatchedBy(func(todo model.Todo) bool {
+					return todo.CategoryID != nil && *todo.CategoryID == 1 && todo.CategorySource == model.CategorySourceManual
+				})).Return(&model.Todo{CategoryID: categoryID(1)}, nil)
+			},
+			expectedHttpCode: 201,
+			in:               map[string]interface{}{"memo": "Belanja sayur", "category_id": 1, "auto_categorize": true},
+		},
 	}
 	for _, tc := range test {
 		t.Run(tc.name, func(tt *testing.T) {
 			e := echo.New()
 
 			todoMockModel := new(mocks.TodoInterface)
+			todoAiMockModel := new(mocks.TodoAIInterface)
+			usageMockModel := new(mocks.AIUsageInterface)
+			usageMockModel.On("CheckQuota", uint(1)).Return(tc.quota).Maybe()
 
 			tc.mock(todoMockModel)
+			if tc.aiMock != nil {
+				tc.aiMock(todoAiMockModel)
+			}
 
-			todoController := NewTodoControllerInterface(todoMockModel)
+			todoController := NewTodoControllerInterface(todoMockModel, todoAiMockModel, usageMockModel)
 
 			buf := new(bytes.Buffer)
 			err := json.NewEncoder(buf).Encode(tc.in)
@@ -86,8 +184,7 @@ func TestTodoController_AddTodo(t *testing.T) {
 			ctx := e.NewContext(req, res)
 			ctx.Set("user", jwtMockItf)
 
-			err = todoController.AddTodo()(ctx)
-			require.NoError(t, err)
+			serve(ctx, todoController.AddTodo())
 
 			w := res.Result()
 			_, err = io.ReadAll(w.Body)
@@ -110,11 +207,16 @@ func TestTodoController_GetTodos(t *testing.T) {
 		valueContent     string
 		status           string
 		date             string
+		tag              string
+		tagMode          string
+		sort             string
+		extra            map[string]string
+		contains         string
 	}{
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("GetTodos", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Todo{})
+				m.On("GetTodos", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Todo{}, int64(0), nil)
 			},
 			expectedHttpCode: 200,
 			in:               mockRequest,
@@ -123,22 +225,185 @@ func TestTodoController_GetTodos(t *testing.T) {
 			status:           "",
 			date:             "",
 		},
+		{
+			name: "Should be Success, filter with all tags",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodos", 1, 5, uint(1), model.TodoFilter{Tags: []string{"urgent", "client-x"}, TagMatchAll: true}).Return([]model.Todo{}, int64(0), nil)
+			},
+			expectedHttpCode: 200,
+			in:               mockRequest,
+			valuePage:        "1",
+			valueContent:     "5",
+			tag:              "urgent, client-x",
+			tagMode:          "all",
+		},
+		{
+			name: "Should be Success, with validation language",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodos", 1, 5, uint(1), model.TodoFilter{}).Return([]model.Todo{}, int64(0), nil)
+			},
+			expectedHttpCode: 200,
+			in:               mockRequest,
+			valuePage:        "1",
+			valueContent:     "5",
+			extra:            map[string]string{"lang": "id"},
+		},
+		{
+			name: "Should be error, because tag mode unknown",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodos", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Todo{}, int64(0), nil)
+			},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+			valuePage:        "1",
+			valueContent:     "5",
+			tag:              "urgent",
+			tagMode:          "some",
+		},
+		{
+			name: "Should be Success, combined filter and sort",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodos", 1, 5, uint(1), model.TodoFilter{
+					Status:     model.StatusInProgress,
+					CategoryID: 2,
+					Overdue:    true,
+					Text:       "rapat",
+					Sort:       []string{"-date_time", "memo"},
+				}).Return([]model.Todo{}, int64(0), nil)
+			},
+			expectedHttpCode: 200,
+			in:               mockRequest,
+			valuePage:        "1",
+			valueContent:     "5",
+			status:           model.StatusInProgress,
+			sort:             "-date_time,memo",
+			extra:            map[string]string{"category_id": "2", "overdue": "true", "text": "rapat"},
+		},
+		{
+			name: "Should be Success, date range",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodos", 1, 5, uint(1), mock.MatchedBy(func(filter model.TodoFilter) bool {
+					return filter.From != nil && filter.To != nil && filter.To.Format("2006-01-02") == "2023-11-30"
+				})).Return([]model.Todo{}, int64(0), nil)
+			},
+			expectedHttpCode: 200,
+			in:               mockRequest,
+			valuePage:        "1",
+			valueContent:     "5",
+			extra:            map[string]string{"from": "2023-11-01", "to": "2023-11-30"},
+		},
+		{
+			name:             "Should be error, because filter field unknown",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+			valuePage:        "1",
+			valueContent:     "5",
+			extra:            map[string]string{"priority": "high"},
+		},
+		{
+			name:             "Should be error, because sort field unknown",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+			valuePage:        "1",
+			valueContent:     "5",
+			sort:             "date_time,-priority",
+		},
+		{
+			name:             "Should be error, because status unknown",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+			valuePage:        "1",
+			valueContent:     "5",
+			status:           "OnGoing",
+		},
+		{
+			name:             "Should be error, because date combined with from",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+			valuePage:        "1",
+			valueContent:     "5",
+			date:             "2023-11-01",
+			extra:            map[string]string{"from": "2023-11-01"},
+		},
+		{
+			name:             "Should be error, because to before from",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+			valuePage:        "1",
+			valueContent:     "5",
+			extra:            map[string]string{"from": "2023-11-30", "to": "2023-11-01"},
+		},
 		{
 			name: "Should be error, because unexpected return from todo model",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("GetTodos", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				m.On("GetTodos", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), errors.New("database is down"))
 			},
-			expectedHttpCode: 404,
+			expectedHttpCode: 500,
 			in:               mockRequest,
 			valuePage:        "1",
 			valueContent:     "5",
 			status:           "",
 			date:             "",
 		},
+		{
+			name: "Should be Success, default page and content with empty result",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodos", 1, helper.DefaultPageSize, uint(1), mock.Anything).Return([]model.Todo{}, int64(0), nil)
+			},
+			expectedHttpCode: 200,
+			in:               mockRequest,
+			contains:         `"data":[]`,
+		},
+		{
+			name: "Should be Success, page envelope",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodos", 2, 5, uint(1), mock.Anything).Return([]model.Todo{{Memo: "a"}}, int64(12), nil)
+			},
+			expectedHttpCode: 200,
+			in:               mockRequest,
+			valuePage:        "2",
+			valueContent:     "5",
+			contains:         `"total":12,"page":2,"per_page":5,"total_pages":3`,
+		},
+		{
+			name: "Should be Success, cursor mode",
+			mock: func(m *mocks.TodoInterface) {
+				todo := []model.Todo{{}, {}}
+				todo[0].ID, todo[1].ID = 9, 7
+				m.On("GetTodos", 1, 2, uint(1), mock.MatchedBy(func(filter model.TodoFilter) bool {
+					return filter.Cursor && filter.BeforeID == 10
+				})).Return(todo, int64(8), nil)
+			},
+			expectedHttpCode: 200,
+			in:               mockRequest,
+			valueContent:     "2",
+			extra:            map[string]string{"cursor": helper.EncodeCursor(10)},
+			contains:         `"next_cursor":"` + helper.EncodeCursor(7) + `"`,
+		},
+		{
+			name:             "Should be error, because cursor invalid",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+			extra:            map[string]string{"cursor": "abc"},
+		},
+		{
+			name:             "Should be error, because cursor combined with page",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+			valuePage:        "2",
+			extra:            map[string]string{"cursor": ""},
+		},
 		{
 			name: "Should be error, because page value format wrong",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("GetTodos", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				m.On("GetTodos", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in:               mockRequest,
@@ -150,7 +415,7 @@ func TestTodoController_GetTodos(t *testing.T) {
 		{
 			name: "Should be error, because content value format wrong",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("GetTodos", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				m.On("GetTodos", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in:               mockRequest,
@@ -168,7 +433,7 @@ func TestTodoController_GetTodos(t *testing.T) {
 
 			tc.mock(todoMockModel)
 
-			TodoController := NewTodoControllerInterface(todoMockModel)
+			TodoController := NewTodoControllerInterface(todoMockModel, new(mocks.TodoAIInterface), new(mocks.AIUsageInterface))
 
 			buf := new(bytes.Buffer)
 			err := json.NewEncoder(buf).Encode(tc.in)
@@ -181,6 +446,12 @@ func TestTodoController_GetTodos(t *testing.T) {
 			q.Add("content", tc.valueContent)
 			q.Add("status", tc.status)
 			q.Add("date", tc.date)
+			q.Add("tag", tc.tag)
+			q.Add("tag_mode", tc.tagMode)
+			q.Add("sort", tc.sort)
+			for key, value := range tc.extra {
+				q.Add(key, value)
+			}
 			req.URL.RawQuery = q.Encode()
 			res := httptest.NewRecorder()
 
@@ -194,14 +465,16 @@ func TestTodoController_GetTodos(t *testing.T) {
 			ctx := e.NewContext(req, res)
 			ctx.Set("user", jwtMockItf)
 
-			err = TodoController.GetTodos()(ctx)
-			require.NoError(t, err)
+			serve(ctx, TodoController.GetTodos())
 
 			w := res.Result()
-			_, err = io.ReadAll(w.Body)
+			body, err := io.ReadAll(w.Body)
 			require.NoError(t, err)
 
 			require.Equal(t, tc.expectedHttpCode, w.StatusCode)
+			if tc.contains != "" {
+				require.Contains(t, string(body), tc.contains)
+			}
 		})
 	}
 
@@ -219,7 +492,7 @@ func TestTodoController_GetTodo(t *testing.T) {
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("GetTodo", mock.Anything, mock.Anything).Return(&model.Todo{})
+				m.On("GetTodo", mock.Anything, mock.Anything).Return(&model.Todo{}, nil)
 			},
 			expectedHttpCode: 200,
 			in:               mockRequest,
@@ -228,16 +501,25 @@ func TestTodoController_GetTodo(t *testing.T) {
 		{
 			name: "Should be error, because unexpected return from todo model",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("GetTodo", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetTodo", mock.Anything, mock.Anything).Return(nil, model.ErrNotFound)
 			},
 			expectedHttpCode: 404,
 			in:               mockRequest,
 			id:               "1",
 		},
+		{
+			name: "Should be error, because database is down",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodo", mock.Anything, mock.Anything).Return(nil, errors.New("database is down"))
+			},
+			expectedHttpCode: 500,
+			in:               mockRequest,
+			id:               "1",
+		},
 		{
 			name: "Should be error, because id value format wrong",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("GetTodo", mock.Anything, mock.Anything).Return(nil)
+				m.On("GetTodo", mock.Anything, mock.Anything).Return(nil, model.ErrNotFound)
 			},
 			expectedHttpCode: 400,
 			in:               mockRequest,
@@ -252,7 +534,7 @@ func TestTodoController_GetTodo(t *testing.T) {
 
 			tc.mock(todoMockModel)
 
-			todoController := NewTodoControllerInterface(todoMockModel)
+			todoController := NewTodoControllerInterface(todoMockModel, new(mocks.TodoAIInterface), new(mocks.AIUsageInterface))
 
 			buf := new(bytes.Buffer)
 			err := json.NewEncoder(buf).Encode(tc.in)
@@ -274,8 +556,7 @@ func TestTodoController_GetTodo(t *testing.T) {
 			ctx.SetParamNames("id")
 			ctx.SetParamValues(tc.id)
 
-			err = todoController.GetTodo()(ctx)
-			require.NoError(t, err)
+			serve(ctx, todoController.GetTodo())
 
 			w := res.Result()
 			_, err = io.ReadAll(w.Body)
@@ -288,7 +569,7 @@ func TestTodoController_GetTodo(t *testing.T) {
 }
 
 func TestTodoController_UpdateTodo(t *testing.T) {
-	mockRequest := model.Todo{}
+	mockRequest := model.Todo{Memo: "Kelas Live Session Golang"}
 	test := []struct {
 		name             string
 		mock             func(*mocks.TodoInterface)
@@ -299,7 +580,7 @@ func TestTodoController_UpdateTodo(t *testing.T) {
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("UpdateTodo", mock.Anything, mock.Anything, mock.Anything).Return(true)
+				m.On("UpdateTodo", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			},
 			expectedHttpCode: 200,
 			in:               mockRequest,
@@ -308,16 +589,34 @@ func TestTodoController_UpdateTodo(t *testing.T) {
 		{
 			name: "Should be error, because unexpected return from todo model",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("UpdateTodo", mock.Anything, mock.Anything, mock.Anything).Return(false)
+				m.On("UpdateTodo", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 500,
 			in:               mockRequest,
 			id:               "1",
 		},
+		{
+			name: "Should be error, because todo not found",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("UpdateTodo", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("todo 1: %w", model.ErrNotFound))
+			},
+			expectedHttpCode: 404,
+			in:               mockRequest,
+			id:               "1",
+		},
+		{
+			name: "Should be error, because category belongs to another user",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("UpdateTodo", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("category 2: %w", model.ErrForbidden))
+			},
+			expectedHttpCode: 403,
+			in:               mockRequest,
+			id:               "1",
+		},
 		{
 			name: "Should be error, because id value format wrong",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("UpdateTodo", mock.Anything, mock.Anything, mock.Anything).Return(false)
+				m.On("UpdateTodo", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in:               mockRequest,
@@ -326,7 +625,7 @@ func TestTodoController_UpdateTodo(t *testing.T) {
 		{
 			name: "Should be error, because bind data error",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("UpdateTodo", mock.Anything, mock.Anything, mock.Anything).Return(false)
+				m.On("UpdateTodo", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in: map[string]any{
@@ -343,7 +642,7 @@ func TestTodoController_UpdateTodo(t *testing.T) {
 
 			tc.mock(todoMockModel)
 
-			todoController := NewTodoControllerInterface(todoMockModel)
+			todoController := NewTodoControllerInterface(todoMockModel, new(mocks.TodoAIInterface), new(mocks.AIUsageInterface))
 
 			buf := new(bytes.Buffer)
 			err := json.NewEncoder(buf).Encode(tc.in)
@@ -365,8 +664,7 @@ func TestTodoController_UpdateTodo(t *testing.T) {
 			ctx.SetParamNames("id")
 			ctx.SetParamValues(tc.id)
 
-			err = todoController.UpdateTodo()(ctx)
-			require.NoError(t, err)
+			serve(ctx, todoController.UpdateTodo())
 
 			w := res.Result()
 			_, err = io.ReadAll(w.Body)
@@ -389,7 +687,7 @@ func TestTodoController_UpdateTodoStatus(t *testing.T) {
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("UpdateTodoStatus", mock.Anything, mock.Anything, mock.Anything).Return(true)
+				m.On("UpdateTodoStatus", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			},
 			expectedHttpCode: 200,
 			in:               mockRequest,
@@ -398,16 +696,41 @@ func TestTodoController_UpdateTodoStatus(t *testing.T) {
 		{
 			name: "Should be error, because unexpected return from todo model",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("UpdateTodoStatus", mock.Anything, mock.Anything, mock.Anything).Return(false)
+				m.On("UpdateTodoStatus", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Something error"))
 			},
 			expectedHttpCode: 500,
 			in:               mockRequest,
 			id:               "1",
 		},
+		{
+			name: "Should be error, because illegal status transition",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("UpdateTodoStatus", mock.Anything, mock.Anything, "Todo").Return(fmt.Errorf("%w from Cancelled to Todo", model.ErrIllegalTransition))
+			},
+			expectedHttpCode: 409,
+			in:               model.TodoStatusRequest{Status: "Todo"},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because unknown status",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               model.TodoStatusRequest{Status: "OnGoing"},
+			id:               "1",
+		},
+		{
+			name: "Should be error, because checklist items still open",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("UpdateTodoStatus", mock.Anything, mock.Anything, mock.Anything).Return(model.ErrOpenItems)
+			},
+			expectedHttpCode: 409,
+			in:               mockRequest,
+			id:               "1",
+		},
 		{
 			name: "Should be error, because id value format wrong",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("UpdateTodoStatus", mock.Anything, mock.Anything, mock.Anything).Return(false)
+				m.On("UpdateTodoStatus", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("Something error"))
 			},
 			expectedHttpCode: 400,
 			in:               mockRequest,
@@ -422,7 +745,7 @@ func TestTodoController_UpdateTodoStatus(t *testing.T) {
 
 			tc.mock(todoMockModel)
 
-			todoController := NewTodoControllerInterface(todoMockModel)
+			todoController := NewTodoControllerInterface(todoMockModel, new(mocks.TodoAIInterface), new(mocks.AIUsageInterface))
 
 			buf := new(bytes.Buffer)
 			err := json.NewEncoder(buf).Encode(tc.in)
@@ -444,8 +767,7 @@ func TestTodoController_UpdateTodoStatus(t *testing.T) {
 			ctx.SetParamNames("id")
 			ctx.SetParamValues(tc.id)
 
-			err = todoController.UpdateTodoStatus()(ctx)
-			require.NoError(t, err)
+			serve(ctx, todoController.UpdateTodoStatus())
 
 			w := res.Result()
 			_, err = io.ReadAll(w.Body)
@@ -469,7 +791,7 @@ func TestTodoController_DeleteTodo(t *testing.T) {
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("DeleteTodo", mock.Anything, mock.Anything).Return(true)
+				m.On("DeleteTodo", mock.Anything, mock.Anything).Return(nil)
 			},
 			expectedHttpCode: 200,
 			in:               mockRequest,
@@ -478,7 +800,7 @@ func TestTodoController_DeleteTodo(t *testing.T) {
 		{
 			name: "Should be error, because unexpected return from todo model",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("DeleteTodo", mock.Anything, mock.Anything).Return(false)
+				m.On("DeleteTodo", mock.Anything, mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 500,
 			in:               mockRequest,
@@ -487,7 +809,7 @@ func TestTodoController_DeleteTodo(t *testing.T) {
 		{
 			name: "Should be error, because id value format wrong",
 			mock: func(m *mocks.TodoInterface) {
-				m.On("DeleteTodo", mock.Anything, mock.Anything).Return(false)
+				m.On("DeleteTodo", mock.Anything, mock.Anything).Return(errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in:               mockRequest,
@@ -502,7 +824,7 @@ func TestTodoController_DeleteTodo(t *testing.T) {
 
 			tc.mock(todoMockModel)
 
-			todoController := NewTodoControllerInterface(todoMockModel)
+			todoController := NewTodoControllerInterface(todoMockModel, new(mocks.TodoAIInterface), new(mocks.AIUsageInterface))
 
 			buf := new(bytes.Buffer)
 			err := json.NewEncoder(buf).Encode(tc.in)
@@ -524,8 +846,7 @@ func TestTodoController_DeleteTodo(t *testing.T) {
 			ctx.SetParamNames("id")
 			ctx.SetParamValues(tc.id)
 
-			err = todoController.DeleteTodo()(ctx)
-			require.NoError(t, err)
+			serve(ctx, todoController.DeleteTodo())
 
 			w := res.Result()
 			_, err = io.ReadAll(w.Body)
@@ -536,3 +857,615 @@ func TestTodoController_DeleteTodo(t *testing.T) {
 	}
 
 }
+
+func TestTodoController_UpdateTodoCategory(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoInterface)
+		expectedHttpCode int
+		in               any
+		id               string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("UpdateTodoCategory", 1, uint(1), uint(2)).Return(nil)
+			},
+			expectedHttpCode: 200,
+			in:               map[string]interface{}{"category_id": 2},
+			id:               "1",
+		},
+		{
+			name: "Should be error, because unexpected return from todo model",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("UpdateTodoCategory", 1, uint(1), uint(9)).Return(errors.New("database is down"))
+			},
+			expectedHttpCode: 500,
+			in:               map[string]interface{}{"category_id": 9},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because category id empty",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]interface{}{},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]interface{}{"category_id": 2},
+			id:               "!",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoMockModel := new(mocks.TodoInterface)
+
+			tc.mock(todoMockModel)
+
+			todoController := NewTodoControllerInterface(todoMockModel, new(mocks.TodoAIInterface), new(mocks.AIUsageInterface))
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, "/todo/category/:id", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			var jwtMockItf interface{} = jwtMock
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMockItf)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.id)
+
+			serve(ctx, todoController.UpdateTodoCategory())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+			todoMockModel.AssertExpectations(tt)
+		})
+	}
+}
+
+func TestTodoController_BreakdownTodo(t *testing.T) {
+	todo := &model.Todo{Memo: "Prepare sprint demo", Duration: 120}
+	breakdown := &model.TodoBreakdown{TodoID: 1, Lang: model.LangEN, Steps: []model.TodoStep{{Order: 1, Memo: "Write script", Duration: 60}}, TotalDuration: 60}
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoInterface)
+		aiMock           func(*mocks.TodoAIInterface)
+		quota            error
+		expectedHttpCode int
+		in               any
+		id               string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodo", 1, uint(1)).Return(todo, nil)
+			},
+			aiMock: func(m *mocks.TodoAIInterface) {
+				m.On("BreakdownTodo", mock.Anything, *todo, model.LangEN, uint(1)).Return(breakdown, nil)
+			},
+			expectedHttpCode: 200,
+			in:               map[string]interface{}{"lang": "en"},
+			id:               "1",
+		},
+		{
+			name: "Should be Success, default language is Indonesian",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodo", 1, uint(1)).Return(todo, nil)
+			},
+			aiMock: func(m *mocks.TodoAIInterface) {
+				m.On("BreakdownTodo", mock.Anything, *todo, model.LangID, uint(1)).Return(breakdown, nil)
+			},
+			expectedHttpCode: 200,
+			in:               map[string]interface{}{},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because language not supported",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]interface{}{"lang": "fr"},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]interface{}{"lang": "en"},
+			id:               "!",
+		},
+		{
+			name: "Should be error, because todo not found",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodo", 2, uint(1)).Return(nil, model.ErrNotFound)
+			},
+			expectedHttpCode: 404,
+			in:               map[string]interface{}{"lang": "en"},
+			id:               "2",
+		},
+		{
+			name: "Should be error, because quota exceeded",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodo", 1, uint(1)).Return(todo, nil)
+			},
+			quota:            &model.QuotaExceededError{Period: "daily", Limit: 100, Used: 120, ResetAt: time.Now().Add(time.Hour)},
+			expectedHttpCode: 429,
+			in:               map[string]interface{}{"lang": "en"},
+			id:               "1",
+		},
+		{
+			name: "Should be error, because AI returned invalid breakdown",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodo", 1, uint(1)).Return(todo, nil)
+			},
+			aiMock: func(m *mocks.TodoAIInterface) {
+				m.On("BreakdownTodo", mock.Anything, *todo, model.LangEN, uint(1)).Return(nil, model.ErrInvalidAIOutput)
+			},
+			expectedHttpCode: 502,
+			in:               map[string]interface{}{"lang": "en"},
+			id:               "1",
+		},
+		{
+			name: "Should be error, because AI disabled",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodo", 1, uint(1)).Return(todo, nil)
+			},
+			aiMock: func(m *mocks.TodoAIInterface) {
+				m.On("BreakdownTodo", mock.Anything, *todo, model.LangEN, uint(1)).Return(nil, ai.ErrDisabled)
+			},
+			expectedHttpCode: 503,
+			in:               map[string]interface{}{"lang": "en"},
+			id:               "1",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoMockModel := new(mocks.TodoInterface)
+			todoAiMockModel := new(mocks.TodoAIInterface)
+			usageMockModel := new(mocks.AIUsageInterface)
+			usageMockModel.On("CheckQuota", uint(1)).Return(tc.quota).Maybe()
+
+			tc.mock(todoMockModel)
+			if tc.aiMock != nil {
+				tc.aiMock(todoAiMockModel)
+			}
+
+			todoController := NewTodoControllerInterface(todoMockModel, todoAiMockModel, usageMockModel)
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/todo/:id/breakdown", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			var jwtMockItf interface{} = jwtMock
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMockItf)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.id)
+
+			serve(ctx, todoController.BreakdownTodo())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+			todoMockModel.AssertExpectations(tt)
+			todoAiMockModel.AssertExpectations(tt)
+		})
+	}
+}
+
+func TestTodoController_AcceptBreakdown(t *testing.T) {
+	todo := &model.Todo{Memo: "Prepare sprint demo", Duration: 120}
+	steps := []model.TodoStep{{Order: 1, Memo: "Write script", Duration: 60}, {Order: 2, Memo: "Rehearse", Duration: 30}}
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoInterface)
+		aiMock           func(*mocks.TodoAIInterface)
+		expectedHttpCode int
+		in               any
+		id               string
+	}{
+		{
+			name: "Should be Success, as checklist items",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodo", 1, uint(1)).Return(todo, nil)
+			},
+			aiMock: func(m *mocks.TodoAIInterface) {
+				m.On("AcceptBreakdown", *todo, steps, model.BreakdownModeItems, uint(1)).Return(&model.BreakdownResult{Mode: model.BreakdownModeItems})
+			},
+			expectedHttpCode: 201,
+			in:               map[string]interface{}{"steps": steps},
+			id:               "1",
+		},
+		{
+			name: "Should be Success, as child todos",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodo", 1, uint(1)).Return(todo, nil)
+			},
+			aiMock: func(m *mocks.TodoAIInterface) {
+				m.On("AcceptBreakdown", *todo, steps, model.BreakdownModeTodos, uint(1)).Return(&model.BreakdownResult{Mode: model.BreakdownModeTodos})
+			},
+			expectedHttpCode: 201,
+			in:               map[string]interface{}{"mode": "todos", "steps": steps},
+			id:               "1",
+		},
+		{
+			name: "Should be error, because unexpected return from todo ai model",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodo", 1, uint(1)).Return(todo, nil)
+			},
+			aiMock: func(m *mocks.TodoAIInterface) {
+				m.On("AcceptBreakdown", *todo, steps, model.BreakdownModeItems, uint(1)).Return(nil)
+			},
+			expectedHttpCode: 500,
+			in:               map[string]interface{}{"steps": steps},
+			id:               "1",
+		},
+		{
+			name: "Should be error, because todo not found",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetTodo", 2, uint(1)).Return(nil, model.ErrNotFound)
+			},
+			expectedHttpCode: 404,
+			in:               map[string]interface{}{"steps": steps},
+			id:               "2",
+		},
+		{
+			name:             "Should be error, because mode unknown",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]interface{}{"mode": "tasks", "steps": steps},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because steps empty",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]interface{}{"steps": []model.TodoStep{}},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because step duration invalid",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]interface{}{"steps": []model.TodoStep{{Order: 1, Memo: "Write script", Duration: 0}}},
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]interface{}{"steps": steps},
+			id:               "!",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoMockModel := new(mocks.TodoInterface)
+			todoAiMockModel := new(mocks.TodoAIInterface)
+
+			tc.mock(todoMockModel)
+			if tc.aiMock != nil {
+				tc.aiMock(todoAiMockModel)
+			}
+
+			todoController := NewTodoControllerInterface(todoMockModel, todoAiMockModel, new(mocks.AIUsageInterface))
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/todo/:id/breakdown/accept", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			var jwtMockItf interface{} = jwtMock
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMockItf)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.id)
+
+			serve(ctx, todoController.AcceptBreakdown())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+			todoMockModel.AssertExpectations(tt)
+			todoAiMockModel.AssertExpectations(tt)
+		})
+	}
+}
+
+func TestTodoController_Occurrence(t *testing.T) {
+	mockRequest := model.Todo{
+		Memo:     "Standup Dipindah",
+		DateTime: time.Date(2023, 11, 06, 10, 0, 0, 0, time.Local),
+	}
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoInterface)
+		handler          func(TodoControllerInterface) echo.HandlerFunc
+		expectedHttpCode int
+		id               string
+		date             string
+	}{
+		{
+			name: "Should be Success update occurrence",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("UpdateOccurrence", 1, uint(1), "2023-11-06", mock.Anything).Return(nil)
+			},
+			handler:          TodoControllerInterface.UpdateOccurrence,
+			expectedHttpCode: 200,
+			id:               "1",
+			date:             "2023-11-06",
+		},
+		{
+			name: "Should be error, because unexpected return from todo model on update occurrence",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("UpdateOccurrence", 1, uint(1), "2023-11-06", mock.Anything).Return(errors.New("database is down"))
+			},
+			handler:          TodoControllerInterface.UpdateOccurrence,
+			expectedHttpCode: 500,
+			id:               "1",
+			date:             "2023-11-06",
+		},
+		{
+			name: "Should be Success skip occurrence",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("SkipOccurrence", 1, uint(1), "2023-11-06").Return(nil)
+			},
+			handler:          TodoControllerInterface.SkipOccurrence,
+			expectedHttpCode: 200,
+			id:               "1",
+			date:             "2023-11-06",
+		},
+		{
+			name: "Should be error, because unexpected return from todo model on skip occurrence",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("SkipOccurrence", 1, uint(1), "2023-11-06").Return(errors.New("database is down"))
+			},
+			handler:          TodoControllerInterface.SkipOccurrence,
+			expectedHttpCode: 500,
+			id:               "1",
+			date:             "2023-11-06",
+		},
+		{
+			name:             "Should be error, because date format wrong",
+			mock:             func(m *mocks.TodoInterface) {},
+			handler:          TodoControllerInterface.SkipOccurrence,
+			expectedHttpCode: 400,
+			id:               "1",
+			date:             "06-11-2023",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.TodoInterface) {},
+			handler:          TodoControllerInterface.UpdateOccurrence,
+			expectedHttpCode: 400,
+			id:               "!",
+			date:             "2023-11-06",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoMockModel := new(mocks.TodoInterface)
+
+			tc.mock(todoMockModel)
+
+			todoController := NewTodoControllerInterface(todoMockModel, new(mocks.TodoAIInterface), new(mocks.AIUsageInterface))
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(mockRequest)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, "/todo/:id/occurrences/:date", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id", "date")
+			ctx.SetParamValues(tc.id, tc.date)
+
+			serve(ctx, tc.handler(todoController))
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoController_GetStatusHistory(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoInterface)
+		expectedHttpCode int
+		id               string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetStatusHistory", 1, uint(1)).Return([]model.TodoStatusHistory{
+					{TodoID: 1, FromStatus: "Todo", ToStatus: "InProgress", ChangedAt: time.Now()},
+				}, nil)
+			},
+			expectedHttpCode: 200,
+			id:               "1",
+		},
+		{
+			name: "Should be error, because unexpected return from todo model",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("GetStatusHistory", 1, uint(1)).Return(nil, model.ErrNotFound)
+			},
+			expectedHttpCode: 404,
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			id:               "!",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoMockModel := new(mocks.TodoInterface)
+
+			tc.mock(todoMockModel)
+
+			todoController := NewTodoControllerInterface(todoMockModel, new(mocks.TodoAIInterface), new(mocks.AIUsageInterface))
+
+			req := httptest.NewRequest(http.MethodGet, "/todo/:id/history", nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.id)
+
+			serve(ctx, todoController.GetStatusHistory())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoController_SearchTodos(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoInterface)
+		expectedHttpCode int
+		query            string
+		valuePage        string
+		valueContent     string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("SearchTodos", "rapat klien", 1, 5, uint(1)).Return([]model.TodoSearchResult{
+					{Todo: model.Todo{Memo: "Rapat klien"}, Score: 1.5, Snippet: "<mark>Rapat</mark> <mark>klien</mark>"},
+				}, int64(1), nil)
+			},
+			expectedHttpCode: 200,
+			query:            "rapat klien",
+			valuePage:        "1",
+			valueContent:     "5",
+		},
+		{
+			name: "Should be Success, page and content are clamped",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("SearchTodos", "rapat", 1, helper.MaxPageSize, uint(1)).Return([]model.TodoSearchResult{}, int64(0), nil)
+			},
+			expectedHttpCode: 200,
+			query:            "rapat",
+			valuePage:        "0",
+			valueContent:     "1000000",
+		},
+		{
+			name: "Should be error, because unexpected return from todo model",
+			mock: func(m *mocks.TodoInterface) {
+				m.On("SearchTodos", "rapat", 1, 5, uint(1)).Return(nil, int64(0), errors.New("database is down"))
+			},
+			expectedHttpCode: 500,
+			query:            "rapat",
+			valuePage:        "1",
+			valueContent:     "5",
+		},
+		{
+			name:             "Should be error, because query empty",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			query:            " ",
+			valuePage:        "1",
+			valueContent:     "5",
+		},
+		{
+			name:             "Should be error, because page value format wrong",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			query:            "rapat",
+			valuePage:        "abc",
+			valueContent:     "5",
+		},
+		{
+			name:             "Should be error, because content value format wrong",
+			mock:             func(m *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+			query:            "rapat",
+			valuePage:        "1",
+			valueContent:     "abc",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoMockModel := new(mocks.TodoInterface)
+
+			tc.mock(todoMockModel)
+
+			todoController := NewTodoControllerInterface(todoMockModel, new(mocks.TodoAIInterface), new(mocks.AIUsageInterface))
+
+			req := httptest.NewRequest(http.MethodGet, "/todo/search", nil)
+			q := req.URL.Query()
+			q.Add("q", tc.query)
+			q.Add("page", tc.valuePage)
+			q.Add("content", tc.valueContent)
+			req.URL.RawQuery = q.Encode()
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			serve(ctx, todoController.SearchTodos())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
@@ -1,46 +1,440 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"mytodo/ai"
 	"mytodo/config"
 	"mytodo/helper"
 	"mytodo/model"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
-	"github.com/sashabaranov/go-openai"
 )
 
 type TodoAIControllerInterface interface {
 	TodoAI() echo.HandlerFunc
+	StreamTodoAI() echo.HandlerFunc
+	AcceptSuggestions() echo.HandlerFunc
+	PlanTodos() echo.HandlerFunc
+	GetWeeklyReview() echo.HandlerFunc
+	GetUsage() echo.HandlerFunc
+	GetUsageAggregate() echo.HandlerFunc
+	AddThread() echo.HandlerFunc
+	GetThreads() echo.HandlerFunc
+	GetThread() echo.HandlerFunc
+	DeleteThread() echo.HandlerFunc
+	SendMessage() echo.HandlerFunc
 }
 
 type TodoAIController struct {
-	model model.TodoAIInterface
-	cfg   config.ProgramConfig
+	model         model.TodoAIInterface
+	todoModel     model.TodoInterface
+	categoryModel model.CategoryInterface
+	usageModel    model.AIUsageInterface
+	cfg           config.ProgramConfig
 }
 
-func NewTodoAIControllerInterface(m model.TodoAIInterface, cf config.ProgramConfig) TodoAIControllerInterface {
+func NewTodoAIControllerInterface(m model.TodoAIInterface, tm model.TodoInterface, cm model.CategoryInterface, um model.AIUsageInterface, cf config.ProgramConfig) TodoAIControllerInterface {
 	return &TodoAIController{
-		model: m,
-		cfg:   cf,
+		model:         m,
+		todoModel:     tm,
+		categoryModel: cm,
+		usageModel:    um,
+		cfg:           cf,
 	}
 }
 
+// rentang todo yang dipakai sebagai konteks planning
+const planHorizon = 7 * 24 * time.Hour
+
 func (tc *TodoAIController) TodoAI() echo.HandlerFunc {
 	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
 		todoai := model.TodoAI{}
 		err := c.Bind(&todoai)
 		if err != nil {
 			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Get Response Error Because Bind Data Error", nil))
 		}
-		key := tc.cfg.ApiKey
-		res, err := tc.model.GetResponseAPI(c, key, todoai)
+		if err := c.Validate(&todoai); err != nil {
+			return fail("Get Response Error Because Invalid Data", err)
+		}
+		if err := tc.usageModel.CheckQuota(uint(id)); err != nil {
+			return quotaResponse(c, err)
+		}
+		res, err := tc.model.SuggestTodos(c.Request().Context(), todoai, uint(id))
+		if errors.Is(err, model.ErrInvalidAIOutput) {
+			return c.JSON(http.StatusBadGateway, helper.FormatResponse("AI Returned Invalid Suggestions", nil))
+		}
+		if status, msg, found := aiError(err); found {
+			return c.JSON(status, helper.FormatResponse(msg, nil))
+		}
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Get Recomendation Todo Failed", nil))
 		}
-		resp := openai.ChatCompletionMessage{
-			Content: res.Choices[0].Message.Content,
+		return c.JSON(http.StatusCreated, helper.FormatResponse("Get Recomendation Todo Successfull", res))
+	}
+}
+
+// StreamTodoAI sama seperti TodoAI tetapi jawaban AI dikirim sebagai
+// Server-Sent Events: delta untuk setiap potongan, retry saat jawaban diminta
+// ulang, lalu done atau error berisi response yang sama dengan TodoAI
+func (tc *TodoAIController) StreamTodoAI() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		todoai := model.TodoAI{}
+		err := c.Bind(&todoai)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Get Response Error Because Bind Data Error", nil))
+		}
+		if err := c.Validate(&todoai); err != nil {
+			return fail("Get Response Error Because Invalid Data", err)
+		}
+		// kuota diperiksa sebelum header stream terkirim agar masih bisa membalas 429
+		if err := tc.usageModel.CheckQuota(uint(id)); err != nil {
+			return quotaResponse(c, err)
+		}
+		ctx := c.Request().Context()
+		w := c.Response()
+		w.Header().Set(echo.HeaderContentType, "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		current := 1
+		res, err := tc.model.SuggestTodosStream(ctx, todoai, uint(id), func(attempt int, delta string) error {
+			if attempt != current {
+				current = attempt
+				if err := helper.WriteSSE(w, "retry", map[string]int{"attempt": attempt}); err != nil {
+					return err
+				}
+			}
+			return helper.WriteSSE(w, "delta", map[string]string{"content": delta})
+		})
+		if ctx.Err() != nil {
+			// client sudah menutup koneksi, request ke provider ikut dibatalkan
+			return nil
+		}
+		if errors.Is(err, model.ErrInvalidAIOutput) {
+			return helper.WriteSSE(w, "error", helper.FormatResponse("AI Returned Invalid Suggestions", nil))
+		}
+		if _, msg, found := aiError(err); found {
+			return helper.WriteSSE(w, "error", helper.FormatResponse(msg, nil))
+		}
+		if err != nil {
+			return helper.WriteSSE(w, "error", helper.FormatResponse("Get Recomendation Todo Failed", nil))
+		}
+		return helper.WriteSSE(w, "done", helper.FormatResponse("Get Recomendation Todo Successfull", res))
+	}
+}
+
+func (tc *TodoAIController) AcceptSuggestions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		data := struct {
+			Suggestions []model.TodoSuggestion `json:"suggestions"`
+		}{}
+		if err := c.Bind(&data); err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
+		}
+		if len(data.Suggestions) == 0 || len(data.Suggestions) > model.MaxTodoSuggestions {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse(fmt.Sprintf("Suggestions Must Contain 1 To %d Items", model.MaxTodoSuggestions), nil))
+		}
+		for i, suggestion := range data.Suggestions {
+			if err := suggestion.Validate(); err != nil {
+				return c.JSON(http.StatusBadRequest, helper.FormatResponse(fmt.Sprintf("Invalid Suggestion %d, %s", i, err.Error()), nil))
+			}
+		}
+		res := tc.model.AcceptSuggestions(data.Suggestions, uint(id))
+		if res == nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Accept Suggestions Failed", nil))
+		}
+		return c.JSON(http.StatusCreated, helper.FormatResponse("Accept Suggestions Successfull", res))
+	}
+}
+
+func (tc *TodoAIController) PlanTodos() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		todoai := model.TodoAI{}
+		if err := c.Bind(&todoai); err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
+		}
+		if err := c.Validate(&todoai); err != nil {
+			return fail("Plan Todo Failed", err)
+		}
+		if err := tc.usageModel.CheckQuota(uint(id)); err != nil {
+			return quotaResponse(c, err)
+		}
+		from := time.Now()
+		to := from.Add(planHorizon)
+		if todoai.Time.Add(24 * time.Hour).After(to) {
+			to = todoai.Time.Add(24 * time.Hour)
+		}
+		upcoming, _, err := tc.todoModel.GetTodos(1, helper.MaxPageSize, uint(id), model.TodoFilter{From: &from, To: &to})
+		if err != nil {
+			return fail("Get Todo Failed", err)
+		}
+		categories, _, err := tc.categoryModel.GetCategories(1, helper.MaxPageSize, uint(id))
+		if err != nil {
+			return fail("Get Categories Failed", err)
+		}
+		res, err := tc.model.PlanTodos(c.Request().Context(), todoai, uint(id), upcoming, categories)
+		if errors.Is(err, model.ErrInvalidAIOutput) {
+			return c.JSON(http.StatusBadGateway, helper.FormatResponse("AI Returned Invalid Plan", nil))
+		}
+		if status, msg, found := aiError(err); found {
+			return c.JSON(status, helper.FormatResponse(msg, nil))
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Plan Todo Failed", nil))
+		}
+		return c.JSON(http.StatusCreated, helper.FormatResponse("Plan Todo Successfull", res))
+	}
+}
+
+// GetWeeklyReview merangkum todo satu minggu ISO (week=2026-W42, default
+// minggu ini). Jika kuota AI habis narasi memakai template lokal.
+func (tc *TodoAIController) GetWeeklyReview() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		start := helper.StartOfISOWeek(time.Now())
+		if val := c.QueryParam("week"); val != "" {
+			week, err := helper.ParseISOWeek(val, time.Local)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, helper.FormatResponse("Invalid Week Value, Use YYYY-Www", nil))
+			}
+			start = week
+		}
+		from := start
+		to := start.AddDate(0, 0, 7).Add(-time.Second)
+		todos := []model.Todo{}
+		for page := 1; ; page++ {
+			res, total, err := tc.todoModel.GetTodos(page, helper.MaxPageSize, uint(id), model.TodoFilter{From: &from, To: &to})
+			if err != nil {
+				return fail("Get Todo Failed", err)
+			}
+			todos = append(todos, res...)
+			if len(res) == 0 || int64(len(todos)) >= total {
+				break
+			}
+		}
+		useAI := tc.usageModel.CheckQuota(uint(id)) == nil
+		refresh := c.QueryParam("refresh") == "true"
+		res, err := tc.model.ReviewWeek(c.Request().Context(), start, todos, useAI, refresh, uint(id))
+		if errors.Is(err, model.ErrInvalidAIOutput) {
+			return c.JSON(http.StatusBadGateway, helper.FormatResponse("AI Returned Invalid Review", nil))
+		}
+		if status, msg, found := aiError(err); found {
+			return c.JSON(status, helper.FormatResponse(msg, nil))
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Get Weekly Review Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Get Weekly Review Successfull", res))
+	}
+}
+
+func (tc *TodoAIController) AddThread() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		data := model.TodoAIThread{}
+		if err := c.Bind(&data); err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
 		}
-		return c.JSON(http.StatusCreated, helper.FormatResponse("Get Recomendation Todo Successfull", resp))
+		if utf8.RuneCountInString(data.Title) > 255 {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Title Must Be At Most 255 Characters", nil))
+		}
+		res := tc.model.AddThread(data, uint(id))
+		if res == nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Create Thread Failed", nil))
+		}
+		return c.JSON(http.StatusCreated, helper.FormatResponse("Create Thread Successfull", res))
+	}
+}
+
+func (tc *TodoAIController) GetThreads() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		page, perPage, err := helper.PageParams(c.QueryParam("page"), c.QueryParam("content"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Get Page Or Content Value", nil))
+		}
+		threads, total := tc.model.GetThreads(page, perPage, uint(id))
+		if threads == nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Get Threads Failed", nil))
+		}
+		pagination := helper.NewPagination(c.Request().URL, page, perPage, total)
+		return c.JSON(http.StatusOK, helper.FormatPaginatedResponse("Get Threads Successfull", threads, pagination))
+	}
+}
+
+func (tc *TodoAIController) GetThread() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		idUser := claims["id"].(float64)
+		idThread, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Thread Format Wrong", nil))
+		}
+		res := tc.model.GetThread(idThread, uint(idUser))
+		if res == nil {
+			return c.JSON(http.StatusNotFound, helper.FormatResponse("Data Not Found", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Get Thread Successfull", res))
+	}
+}
+
+func (tc *TodoAIController) DeleteThread() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		idUser := claims["id"].(float64)
+		idThread, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Thread Format Wrong", nil))
+		}
+		err = tc.model.DeleteThread(idThread, uint(idUser))
+		if errors.Is(err, model.ErrThreadNotFound) {
+			return c.JSON(http.StatusNotFound, helper.FormatResponse("Data Not Found", nil))
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Delete Thread Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Delete Thread Successfull", nil))
+	}
+}
+
+// panjang maksimal satu pesan user di thread
+const maxThreadMessageLength = 2000
+
+// SendMessage mengirim pesan ke thread dan mengembalikan jawaban AI beserta
+// saran todo terbaru yang bisa disimpan lewat /todoai/accept
+func (tc *TodoAIController) SendMessage() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		idUser := claims["id"].(float64)
+		idThread, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Thread Format Wrong", nil))
+		}
+		data := struct {
+			Content string `json:"content" form:"content"`
+		}{}
+		if err := c.Bind(&data); err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
+		}
+		content := strings.TrimSpace(data.Content)
+		if content == "" {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Content Cannot Be Empty", nil))
+		}
+		if len([]rune(content)) > maxThreadMessageLength {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse(fmt.Sprintf("Content Must Be At Most %d Characters", maxThreadMessageLength), nil))
+		}
+		if err := tc.usageModel.CheckQuota(uint(idUser)); err != nil {
+			return quotaResponse(c, err)
+		}
+		res, err := tc.model.SendMessage(c.Request().Context(), idThread, uint(idUser), content)
+		if errors.Is(err, model.ErrThreadNotFound) {
+			return c.JSON(http.StatusNotFound, helper.FormatResponse("Data Not Found", nil))
+		}
+		if errors.Is(err, model.ErrInvalidAIOutput) {
+			return c.JSON(http.StatusBadGateway, helper.FormatResponse("AI Returned Invalid Reply", nil))
+		}
+		if status, msg, found := aiError(err); found {
+			return c.JSON(status, helper.FormatResponse(msg, nil))
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Send Message Failed", nil))
+		}
+		return c.JSON(http.StatusCreated, helper.FormatResponse("Send Message Successfull", res))
+	}
+}
+
+func (tc *TodoAIController) GetUsage() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		res := tc.usageModel.GetUsageReport(uint(id))
+		if res == nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Get AI Usage Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Get AI Usage Successfull", res))
+	}
+}
+
+// GetUsageAggregate menampilkan pemakaian AI semua user untuk admin, from dan
+// to berformat YYYY-MM-DD (inklusif), default bulan berjalan
+func (tc *TodoAIController) GetUsageAggregate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		now := time.Now()
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		to := from.AddDate(0, 1, 0)
+		if val := c.QueryParam("from"); val != "" {
+			date, err := time.ParseInLocation("2006-01-02", val, time.Local)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, helper.FormatResponse("Invalid From Value, Use YYYY-MM-DD", nil))
+			}
+			from = date
+		}
+		if val := c.QueryParam("to"); val != "" {
+			date, err := time.ParseInLocation("2006-01-02", val, time.Local)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, helper.FormatResponse("Invalid To Value, Use YYYY-MM-DD", nil))
+			}
+			to = date.AddDate(0, 0, 1)
+		}
+		if !to.After(from) {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("To Cannot Be Before From", nil))
+		}
+		res := tc.usageModel.GetUsageAggregate(from, to)
+		if res == nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Get AI Usage Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Get AI Usage Successfull", res))
+	}
+}
+
+// aiError memetakan error provider AI ke kode HTTP dan pesan, found false
+// berarti err bukan error dari provider
+func aiError(err error) (int, string, bool) {
+	switch {
+	case errors.Is(err, ai.ErrDisabled):
+		return http.StatusServiceUnavailable, "AI Is Disabled", true
+	case errors.Is(err, ai.ErrCircuitOpen):
+		return http.StatusServiceUnavailable, "AI Provider Unavailable", true
+	case errors.Is(err, ai.ErrTimeout):
+		return http.StatusGatewayTimeout, "AI Provider Timeout", true
+	case errors.Is(err, ai.ErrQuotaExceeded):
+		return http.StatusTooManyRequests, "AI Provider Quota Exceeded", true
+	case errors.Is(err, ai.ErrRateLimited):
+		return http.StatusTooManyRequests, "AI Provider Rate Limited", true
+	case errors.Is(err, ai.ErrInvalidKey):
+		return http.StatusBadGateway, "AI Provider Key Invalid", true
+	case errors.Is(err, ai.ErrUpstream):
+		return http.StatusBadGateway, "AI Provider Error", true
+	}
+	return 0, "", false
+}
+
+// quotaResponse membalas 429 beserta waktu reset jika kuota AI user habis
+func quotaResponse(c echo.Context, err error) error {
+	quotaErr := &model.QuotaExceededError{}
+	if !errors.As(err, &quotaErr) {
+		return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Check AI Quota Failed", nil))
 	}
+	retryAfter := max(int(time.Until(quotaErr.ResetAt).Seconds()), 1)
+	c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	return c.JSON(http.StatusTooManyRequests, helper.FormatResponse("AI Quota Exceeded", quotaErr))
 }
@@ -2,11 +2,14 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"time"
 
+	"mytodo/ai"
 	"mytodo/config"
 	"mytodo/model"
 	"mytodo/model/mocks"
@@ -17,7 +20,6 @@ import (
 
 	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
-	"github.com/sashabaranov/go-openai"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -31,37 +33,106 @@ func TestTodoController_TodoAI(t *testing.T) {
 		name             string
 		mock             func(*mocks.TodoAIInterface)
 		expectedHttpCode int
+		quota            error
 		in               any
 	}{
 		{
 			name: "Should be Success",
 			mock: func(m *mocks.TodoAIInterface) {
-				m.On("GetResponseAPI", mock.Anything, mock.Anything, mock.Anything).Return(openai.ChatCompletionResponse{
-					Choices: []openai.ChatCompletionChoice{
-						{
-							Message: openai.ChatCompletionMessage{
-								Role:    "system",
-								Content: "lorem ipsum",
-							},
-						},
+				m.On("SuggestTodos", mock.Anything, mock.Anything, uint(1)).Return([]model.TodoSuggestion{
+					{
+						Memo:     "Jogging di taman",
+						DateTime: time.Date(2023, 11, 03, 14, 0, 0, 0, time.Local),
+						Duration: 60,
+						Category: "Olahraga",
 					},
 				}, nil)
 			},
 			expectedHttpCode: 201,
 			in:               mockRequest,
 		},
+		{
+			name: "Should be Success, with time only",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SuggestTodos", mock.Anything, mock.MatchedBy(func(todo model.TodoAI) bool {
+					return todo.Todo == "" && todo.Time.Equal(mockRequest.Time)
+				}), uint(1)).Return([]model.TodoSuggestion{
+					{
+						Memo:     "Jogging di taman",
+						DateTime: mockRequest.Time,
+						Duration: 60,
+						Category: "Olahraga",
+					},
+				}, nil)
+			},
+			expectedHttpCode: 201,
+			in:               map[string]any{"time": mockRequest.Time},
+		},
+		{
+			name:             "Should be error, because todo too long",
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 422,
+			in:               map[string]any{"todo": strings.Repeat("a", 1001)},
+		},
 		{
 			name: "Should be error, because unexpected return from TodoAI model",
 			mock: func(m *mocks.TodoAIInterface) {
-				m.On("GetResponseAPI", mock.Anything, mock.Anything, mock.Anything).Return(openai.ChatCompletionResponse{}, errors.New("Something error"))
+				m.On("SuggestTodos", mock.Anything, mock.Anything, uint(1)).Return(nil, errors.New("Something error"))
 			},
 			expectedHttpCode: 500,
 			in:               mockRequest,
 		},
+		{
+			name: "Should be error, because AI keeps returning invalid suggestions",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SuggestTodos", mock.Anything, mock.Anything, uint(1)).Return(nil, model.ErrInvalidAIOutput)
+			},
+			expectedHttpCode: 502,
+			in:               mockRequest,
+		},
+		{
+			name: "Should be error, because AI provider timed out",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SuggestTodos", mock.Anything, mock.Anything, uint(1)).Return(nil, fmt.Errorf("%w: upstream", ai.ErrTimeout))
+			},
+			expectedHttpCode: 504,
+			in:               mockRequest,
+		},
+		{
+			name: "Should be error, because AI provider quota exceeded",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SuggestTodos", mock.Anything, mock.Anything, uint(1)).Return(nil, fmt.Errorf("%w: upstream", ai.ErrQuotaExceeded))
+			},
+			expectedHttpCode: 429,
+			in:               mockRequest,
+		},
+		{
+			name: "Should be error, because AI provider rejected api key",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SuggestTodos", mock.Anything, mock.Anything, uint(1)).Return(nil, fmt.Errorf("%w: upstream", ai.ErrInvalidKey))
+			},
+			expectedHttpCode: 502,
+			in:               mockRequest,
+		},
+		{
+			name: "Should be error, because AI provider circuit open",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SuggestTodos", mock.Anything, mock.Anything, uint(1)).Return(nil, fmt.Errorf("%w: upstream", ai.ErrCircuitOpen))
+			},
+			expectedHttpCode: 503,
+			in:               mockRequest,
+		},
+		{
+			name:             "Should be error, because AI quota exceeded",
+			mock:             func(m *mocks.TodoAIInterface) {},
+			quota:            &model.QuotaExceededError{Period: "daily", Limit: 100, Used: 120, ResetAt: time.Now().Add(time.Hour)},
+			expectedHttpCode: 429,
+			in:               mockRequest,
+		},
 		{
 			name: "Should be error, because invalid parse body",
 			mock: func(m *mocks.TodoAIInterface) {
-				m.On("GetResponseAPI", mock.Anything, mock.Anything, mock.Anything).Return(false)
+				m.On("SuggestTodos", mock.Anything, mock.Anything, uint(1)).Return(false)
 			},
 			expectedHttpCode: 400,
 			in: map[string]interface{}{
@@ -76,9 +147,12 @@ func TestTodoController_TodoAI(t *testing.T) {
 			todoAiMockModel := new(mocks.TodoAIInterface)
 			config := config.ProgramConfig{}
 
+			usageMockModel := new(mocks.AIUsageInterface)
+			usageMockModel.On("CheckQuota", uint(1)).Return(tc.quota).Maybe()
+
 			tc.mock(todoAiMockModel)
 
-			TodoAIController := NewTodoAIControllerInterface(todoAiMockModel, config)
+			TodoAIController := NewTodoAIControllerInterface(todoAiMockModel, new(mocks.TodoInterface), new(mocks.CategoryInterface), usageMockModel, config)
 
 			buf := new(bytes.Buffer)
 			err := json.NewEncoder(buf).Encode(tc.in)
@@ -97,8 +171,7 @@ func TestTodoController_TodoAI(t *testing.T) {
 			ctx := e.NewContext(req, res)
 			ctx.Set("user", jwtMockItf)
 
-			err = TodoAIController.TodoAI()(ctx)
-			require.NoError(t, err)
+			serve(ctx, TodoAIController.TodoAI())
 
 			w := res.Result()
 			_, err = io.ReadAll(w.Body)
@@ -109,3 +182,973 @@ func TestTodoController_TodoAI(t *testing.T) {
 	}
 
 }
+
+func TestTodoController_AcceptSuggestions(t *testing.T) {
+	suggestion := map[string]any{
+		"memo":      "Jogging di taman",
+		"date_time": time.Date(2023, 11, 03, 14, 0, 0, 0, time.UTC),
+		"duration":  60,
+		"category":  "Olahraga",
+	}
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoAIInterface)
+		expectedHttpCode int
+		in               any
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("AcceptSuggestions", mock.MatchedBy(func(s []model.TodoSuggestion) bool {
+					return len(s) == 2 && s[0].Category == "Olahraga"
+				}), uint(1)).Return([]model.Todo{{Memo: "Jogging di taman"}, {Memo: "Jogging di taman"}})
+			},
+			expectedHttpCode: 201,
+			in:               map[string]any{"suggestions": []any{suggestion, suggestion}},
+		},
+		{
+			name: "Should be error, because unexpected return from TodoAI model",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("AcceptSuggestions", mock.Anything, uint(1)).Return(nil)
+			},
+			expectedHttpCode: 500,
+			in:               map[string]any{"suggestions": []any{suggestion}},
+		},
+		{
+			name:             "Should be error, because suggestions empty",
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]any{"suggestions": []any{}},
+		},
+		{
+			name:             "Should be error, because suggestion without category",
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 400,
+			in: map[string]any{"suggestions": []any{map[string]any{
+				"memo":      "Jogging di taman",
+				"date_time": time.Date(2023, 11, 03, 14, 0, 0, 0, time.UTC),
+			}}},
+		},
+		{
+			name:             "Should be error, because invalid parse body",
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]any{"suggestions": "semua"},
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoAiMockModel := new(mocks.TodoAIInterface)
+
+			tc.mock(todoAiMockModel)
+
+			TodoAIController := NewTodoAIControllerInterface(todoAiMockModel, new(mocks.TodoInterface), new(mocks.CategoryInterface), new(mocks.AIUsageInterface), config.ProgramConfig{})
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/todoai/accept", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			err = TodoAIController.AcceptSuggestions()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoController_PlanTodos(t *testing.T) {
+	upcoming := []model.Todo{{Memo: "Rapat", DateTime: time.Date(2023, 11, 03, 14, 0, 0, 0, time.UTC)}}
+	categories := []model.Category{{Category: "Olahraga"}}
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoAIInterface, *mocks.TodoInterface, *mocks.CategoryInterface)
+		expectedHttpCode int
+		quota            error
+		in               any
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface, cm *mocks.CategoryInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.MatchedBy(func(f model.TodoFilter) bool {
+					return f.From != nil && f.To != nil && f.To.Sub(*f.From) == 7*24*time.Hour
+				})).Return(upcoming, int64(1), nil)
+				cm.On("GetCategories", 1, 100, uint(1)).Return(categories, int64(1), nil)
+				m.On("PlanTodos", mock.Anything, mock.Anything, uint(1), upcoming, categories).Return(&model.TodoPlan{
+					Suggestions: []model.TodoSuggestion{{Memo: "Jogging", DateTime: time.Date(2023, 11, 03, 15, 0, 0, 0, time.UTC), Duration: 60, Category: "Olahraga"}},
+					Conflicts:   []model.PlanConflict{{Memo: "Rapat", Reason: "Bertabrakan"}},
+				}, nil)
+			},
+			expectedHttpCode: 201,
+			in:               map[string]any{"todo": "Jogging"},
+		},
+		{
+			name: "Should be Success, with time only",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface, cm *mocks.CategoryInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.Anything).Return(upcoming, int64(1), nil)
+				cm.On("GetCategories", 1, 100, uint(1)).Return(categories, int64(1), nil)
+				m.On("PlanTodos", mock.Anything, mock.MatchedBy(func(todo model.TodoAI) bool { return todo.Todo == "" }), uint(1), upcoming, categories).Return(&model.TodoPlan{}, nil)
+			},
+			expectedHttpCode: 201,
+			in:               map[string]any{"time": time.Date(2023, 11, 03, 15, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "Should be error, because AI keeps returning overlapping plan",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface, cm *mocks.CategoryInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.Anything).Return(upcoming, int64(1), nil)
+				cm.On("GetCategories", 1, 100, uint(1)).Return(categories, int64(1), nil)
+				m.On("PlanTodos", mock.Anything, mock.Anything, uint(1), upcoming, categories).Return(nil, model.ErrInvalidAIOutput)
+			},
+			expectedHttpCode: 502,
+			in:               map[string]any{"todo": "Jogging"},
+		},
+		{
+			name: "Should be error, because unexpected return from TodoAI model",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface, cm *mocks.CategoryInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.Anything).Return(upcoming, int64(1), nil)
+				cm.On("GetCategories", 1, 100, uint(1)).Return(categories, int64(1), nil)
+				m.On("PlanTodos", mock.Anything, mock.Anything, uint(1), upcoming, categories).Return(nil, errors.New("Something error"))
+			},
+			expectedHttpCode: 500,
+			in:               map[string]any{"todo": "Jogging"},
+		},
+		{
+			name: "Should be error, because unexpected return from todo model",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface, cm *mocks.CategoryInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.Anything).Return(nil, int64(0), errors.New("database is down"))
+			},
+			expectedHttpCode: 500,
+			in:               map[string]any{"todo": "Jogging"},
+		},
+		{
+			name: "Should be error, because unexpected return from category model",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface, cm *mocks.CategoryInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.Anything).Return(upcoming, int64(1), nil)
+				cm.On("GetCategories", 1, 100, uint(1)).Return(nil, int64(0), errors.New("database is down"))
+			},
+			expectedHttpCode: 500,
+			in:               map[string]any{"todo": "Jogging"},
+		},
+		{
+			name:             "Should be error, because AI quota exceeded",
+			mock:             func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface, cm *mocks.CategoryInterface) {},
+			quota:            &model.QuotaExceededError{Period: "daily", Limit: 100, Used: 120, ResetAt: time.Now().Add(time.Hour)},
+			expectedHttpCode: 429,
+			in:               map[string]any{"todo": "Jogging"},
+		},
+		{
+			name:             "Should be error, because invalid parse body",
+			mock:             func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface, cm *mocks.CategoryInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]any{"todo": 1234},
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoAiMockModel := new(mocks.TodoAIInterface)
+			todoMockModel := new(mocks.TodoInterface)
+			categoryMockModel := new(mocks.CategoryInterface)
+
+			usageMockModel := new(mocks.AIUsageInterface)
+			usageMockModel.On("CheckQuota", uint(1)).Return(tc.quota).Maybe()
+
+			tc.mock(todoAiMockModel, todoMockModel, categoryMockModel)
+
+			TodoAIController := NewTodoAIControllerInterface(todoAiMockModel, todoMockModel, categoryMockModel, usageMockModel, config.ProgramConfig{})
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/todoai/plan", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			serve(ctx, TodoAIController.PlanTodos())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoController_StreamTodoAI(t *testing.T) {
+	suggestions := []model.TodoSuggestion{{Memo: "Jogging", DateTime: time.Date(2023, 11, 03, 15, 0, 0, 0, time.UTC), Duration: 60, Category: "Olahraga"}}
+	stream := func(deltas ...[2]any) func(args mock.Arguments) {
+		return func(args mock.Arguments) {
+			onDelta := args.Get(3).(model.StreamFunc)
+			for _, d := range deltas {
+				require.NoError(t, onDelta(d[0].(int), d[1].(string)))
+			}
+		}
+	}
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoAIInterface)
+		method           string
+		target           string
+		in               any
+		cancel           bool
+		expectedHttpCode int
+		quota            error
+		expectedBody     []string
+		notExpected      string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SuggestTodosStream", mock.Anything, mock.MatchedBy(func(todo model.TodoAI) bool {
+					return todo.Todo == "Olahraga"
+				}), uint(1), mock.Anything).Run(stream([2]any{1, "{\"sugg"}, [2]any{1, "estions\""})).Return(suggestions, nil)
+			},
+			method:           http.MethodPost,
+			target:           "/todoai/stream",
+			in:               map[string]any{"todo": "Olahraga"},
+			expectedHttpCode: 200,
+			expectedBody: []string{
+				"event: delta\ndata: {\"content\":\"{\\\"sugg\"}\n\n",
+				"event: done\ndata: {\"data\":[{\"memo\":\"Jogging\"",
+			},
+		},
+		{
+			name: "Should be Success, with query params",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SuggestTodosStream", mock.Anything, mock.MatchedBy(func(todo model.TodoAI) bool {
+					return todo.Todo == "Olahraga" && todo.Time.Equal(time.Date(2023, 11, 03, 14, 0, 0, 0, time.UTC))
+				}), uint(1), mock.Anything).Run(stream([2]any{1, "x"})).Return(suggestions, nil)
+			},
+			method:           http.MethodGet,
+			target:           "/todoai/stream?todo=Olahraga&time=2023-11-03T14:00:00Z",
+			expectedHttpCode: 200,
+			expectedBody:     []string{"event: done\n"},
+		},
+		{
+			name: "Should send retry event when AI answer is requested again",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SuggestTodosStream", mock.Anything, mock.Anything, uint(1), mock.Anything).Run(stream([2]any{1, "oops"}, [2]any{2, "{}"})).Return(suggestions, nil)
+			},
+			method:           http.MethodPost,
+			target:           "/todoai/stream",
+			in:               map[string]any{"todo": "Olahraga"},
+			expectedHttpCode: 200,
+			expectedBody:     []string{"event: retry\ndata: {\"attempt\":2}\n\nevent: delta\ndata: {\"content\":\"{}\"}\n\n", "event: done\n"},
+		},
+		{
+			name: "Should send error event, because AI keeps returning invalid suggestions",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SuggestTodosStream", mock.Anything, mock.Anything, uint(1), mock.Anything).Return(nil, model.ErrInvalidAIOutput)
+			},
+			method:           http.MethodPost,
+			target:           "/todoai/stream",
+			in:               map[string]any{"todo": "Olahraga"},
+			expectedHttpCode: 200,
+			expectedBody:     []string{"event: error\ndata: {\"message\":\"AI Returned Invalid Suggestions\"}\n\n"},
+			notExpected:      "event: done",
+		},
+		{
+			name: "Should stop without event, because client disconnected",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SuggestTodosStream", mock.MatchedBy(func(ctx context.Context) bool {
+					return ctx.Err() != nil
+				}), mock.Anything, uint(1), mock.Anything).Return(nil, context.Canceled)
+			},
+			method:           http.MethodPost,
+			target:           "/todoai/stream",
+			in:               map[string]any{"todo": "Olahraga"},
+			cancel:           true,
+			expectedHttpCode: 200,
+			notExpected:      "event:",
+		},
+		{
+			name:             "Should be error, because AI quota exceeded",
+			mock:             func(m *mocks.TodoAIInterface) {},
+			quota:            &model.QuotaExceededError{Period: "daily", Limit: 100, Used: 120, ResetAt: time.Now().Add(time.Hour)},
+			expectedHttpCode: 429,
+			method:           http.MethodPost,
+			target:           "/todoai/stream",
+			in:               map[string]any{"todo": "Olahraga"},
+			notExpected:      "event:",
+		},
+		{
+			name:             "Should be error, because invalid parse body",
+			mock:             func(m *mocks.TodoAIInterface) {},
+			method:           http.MethodPost,
+			target:           "/todoai/stream",
+			in:               map[string]any{"todo": 1234},
+			expectedHttpCode: 400,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoAiMockModel := new(mocks.TodoAIInterface)
+
+			usageMockModel := new(mocks.AIUsageInterface)
+			usageMockModel.On("CheckQuota", uint(1)).Return(tc.quota).Maybe()
+
+			tc.mock(todoAiMockModel)
+
+			TodoAIController := NewTodoAIControllerInterface(todoAiMockModel, new(mocks.TodoInterface), new(mocks.CategoryInterface), usageMockModel, config.ProgramConfig{})
+
+			var req *http.Request
+			if tc.in != nil {
+				buf := new(bytes.Buffer)
+				err := json.NewEncoder(buf).Encode(tc.in)
+				require.NoError(t, err)
+				req = httptest.NewRequest(tc.method, tc.target, strings.NewReader(buf.String()))
+				req.Header.Set("Content-Type", "application/json")
+			} else {
+				req = httptest.NewRequest(tc.method, tc.target, nil)
+			}
+			if tc.cancel {
+				ctx, cancel := context.WithCancel(req.Context())
+				cancel()
+				req = req.WithContext(ctx)
+			}
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			serve(ctx, TodoAIController.StreamTodoAI())
+
+			require.Equal(t, tc.expectedHttpCode, res.Code)
+			for _, body := range tc.expectedBody {
+				require.Contains(t, res.Body.String(), body)
+			}
+			if tc.notExpected != "" {
+				require.NotContains(t, res.Body.String(), tc.notExpected)
+			}
+			todoAiMockModel.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTodoController_GetUsage(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.AIUsageInterface)
+		expectedHttpCode int
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.AIUsageInterface) {
+				m.On("GetUsageReport", uint(1)).Return(&model.UsageReport{
+					Daily: model.UsagePeriod{TotalTokens: 120, Limit: 1000, Remaining: 880},
+				})
+			},
+			expectedHttpCode: 200,
+		},
+		{
+			name: "Should be error, because unexpected return from usage model",
+			mock: func(m *mocks.AIUsageInterface) {
+				m.On("GetUsageReport", uint(1)).Return(nil)
+			},
+			expectedHttpCode: 500,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			usageMockModel := new(mocks.AIUsageInterface)
+
+			tc.mock(usageMockModel)
+
+			TodoAIController := NewTodoAIControllerInterface(new(mocks.TodoAIInterface), new(mocks.TodoInterface), new(mocks.CategoryInterface), usageMockModel, config.ProgramConfig{})
+
+			req := httptest.NewRequest(http.MethodGet, "/todoai/usage", nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			err := TodoAIController.GetUsage()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoController_GetUsageAggregate(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.AIUsageInterface)
+		query            string
+		expectedHttpCode int
+	}{
+		{
+			name: "Should be Success, default current month",
+			mock: func(m *mocks.AIUsageInterface) {
+				m.On("GetUsageAggregate", mock.MatchedBy(func(from time.Time) bool {
+					return from.Day() == 1 && from.Hour() == 0
+				}), mock.Anything).Return([]model.UsageAggregate{{UserID: 1, TotalTokens: 120}})
+			},
+			expectedHttpCode: 200,
+		},
+		{
+			name: "Should be Success, with range",
+			mock: func(m *mocks.AIUsageInterface) {
+				m.On("GetUsageAggregate", time.Date(2023, 11, 1, 0, 0, 0, 0, time.Local), time.Date(2023, 11, 8, 0, 0, 0, 0, time.Local)).Return([]model.UsageAggregate{})
+			},
+			query:            "?from=2023-11-01&to=2023-11-07",
+			expectedHttpCode: 200,
+		},
+		{
+			name: "Should be error, because unexpected return from usage model",
+			mock: func(m *mocks.AIUsageInterface) {
+				m.On("GetUsageAggregate", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedHttpCode: 500,
+		},
+		{
+			name:             "Should be error, because from format wrong",
+			mock:             func(m *mocks.AIUsageInterface) {},
+			query:            "?from=kemarin",
+			expectedHttpCode: 400,
+		},
+		{
+			name:             "Should be error, because to before from",
+			mock:             func(m *mocks.AIUsageInterface) {},
+			query:            "?from=2023-11-07&to=2023-11-01",
+			expectedHttpCode: 400,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			usageMockModel := new(mocks.AIUsageInterface)
+
+			tc.mock(usageMockModel)
+
+			TodoAIController := NewTodoAIControllerInterface(new(mocks.TodoAIInterface), new(mocks.TodoInterface), new(mocks.CategoryInterface), usageMockModel, config.ProgramConfig{})
+
+			req := httptest.NewRequest(http.MethodGet, "/todoai/usage/admin"+tc.query, nil)
+			res := httptest.NewRecorder()
+
+			ctx := e.NewContext(req, res)
+
+			err := TodoAIController.GetUsageAggregate()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoController_AddThread(t *testing.T) {
+	test := []struct {
+		name             string
+		body             string
+		mock             func(*mocks.TodoAIInterface)
+		expectedHttpCode int
+	}{
+		{
+			name: "Should be Success",
+			body: `{"title":"Rencana minggu ini"}`,
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("AddThread", model.TodoAIThread{Title: "Rencana minggu ini"}, uint(1)).Return(&model.TodoAIThread{Title: "Rencana minggu ini", UserID: 1})
+			},
+			expectedHttpCode: 201,
+		},
+		{
+			name: "Should be error, because unexpected return from model",
+			body: `{}`,
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("AddThread", model.TodoAIThread{}, uint(1)).Return(nil)
+			},
+			expectedHttpCode: 500,
+		},
+		{
+			name: "Should be Success, title limit counted by character",
+			body: `{"title":"` + strings.Repeat("é", 255) + `"}`,
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("AddThread", model.TodoAIThread{Title: strings.Repeat("é", 255)}, uint(1)).Return(&model.TodoAIThread{Title: strings.Repeat("é", 255)})
+			},
+			expectedHttpCode: 201,
+		},
+		{
+			name:             "Should be error, because title too long",
+			body:             `{"title":"` + strings.Repeat("a", 256) + `"}`,
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 400,
+		},
+		{
+			name:             "Should be error, because bind data error",
+			body:             `{"title":1}`,
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 400,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoAiMockModel := new(mocks.TodoAIInterface)
+
+			tc.mock(todoAiMockModel)
+
+			TodoAIController := NewTodoAIControllerInterface(todoAiMockModel, new(mocks.TodoInterface), new(mocks.CategoryInterface), new(mocks.AIUsageInterface), config.ProgramConfig{})
+
+			req := httptest.NewRequest(http.MethodPost, "/todoai/threads", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			err := TodoAIController.AddThread()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoController_GetThreads(t *testing.T) {
+	test := []struct {
+		name             string
+		query            string
+		mock             func(*mocks.TodoAIInterface)
+		expectedHttpCode int
+	}{
+		{
+			name:  "Should be Success",
+			query: "?page=2&content=5",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("GetThreads", 2, 5, uint(1)).Return([]model.TodoAIThread{{Title: "Rencana"}}, int64(6))
+			},
+			expectedHttpCode: 200,
+		},
+		{
+			name: "Should be error, because unexpected return from model",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("GetThreads", 1, 10, uint(1)).Return(nil, int64(0))
+			},
+			expectedHttpCode: 500,
+		},
+		{
+			name:             "Should be error, because page format wrong",
+			query:            "?page=satu",
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 400,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoAiMockModel := new(mocks.TodoAIInterface)
+
+			tc.mock(todoAiMockModel)
+
+			TodoAIController := NewTodoAIControllerInterface(todoAiMockModel, new(mocks.TodoInterface), new(mocks.CategoryInterface), new(mocks.AIUsageInterface), config.ProgramConfig{})
+
+			req := httptest.NewRequest(http.MethodGet, "/todoai/threads"+tc.query, nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			err := TodoAIController.GetThreads()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoController_GetThread(t *testing.T) {
+	test := []struct {
+		name             string
+		param            string
+		mock             func(*mocks.TodoAIInterface)
+		expectedHttpCode int
+	}{
+		{
+			name:  "Should be Success",
+			param: "1",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("GetThread", 1, uint(1)).Return(&model.TodoAIThread{Messages: []model.TodoAIMessage{{Role: "user", Content: "halo"}}})
+			},
+			expectedHttpCode: 200,
+		},
+		{
+			name:  "Should be error, because thread not found",
+			param: "2",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("GetThread", 2, uint(1)).Return(nil)
+			},
+			expectedHttpCode: 404,
+		},
+		{
+			name:             "Should be error, because id format wrong",
+			param:            "satu",
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 400,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoAiMockModel := new(mocks.TodoAIInterface)
+
+			tc.mock(todoAiMockModel)
+
+			TodoAIController := NewTodoAIControllerInterface(todoAiMockModel, new(mocks.TodoInterface), new(mocks.CategoryInterface), new(mocks.AIUsageInterface), config.ProgramConfig{})
+
+			req := httptest.NewRequest(http.MethodGet, "/todoai/threads/"+tc.param, nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.param)
+
+			err := TodoAIController.GetThread()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoController_DeleteThread(t *testing.T) {
+	test := []struct {
+		name             string
+		param            string
+		mock             func(*mocks.TodoAIInterface)
+		expectedHttpCode int
+	}{
+		{
+			name:  "Should be Success",
+			param: "1",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("DeleteThread", 1, uint(1)).Return(nil)
+			},
+			expectedHttpCode: 200,
+		},
+		{
+			name:  "Should be error, because thread not found",
+			param: "3",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("DeleteThread", 3, uint(1)).Return(model.ErrThreadNotFound)
+			},
+			expectedHttpCode: 404,
+		},
+		{
+			name:  "Should be error, because unexpected return from model",
+			param: "2",
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("DeleteThread", 2, uint(1)).Return(errors.New("database is down"))
+			},
+			expectedHttpCode: 500,
+		},
+		{
+			name:             "Should be error, because id format wrong",
+			param:            "satu",
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 400,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoAiMockModel := new(mocks.TodoAIInterface)
+
+			tc.mock(todoAiMockModel)
+
+			TodoAIController := NewTodoAIControllerInterface(todoAiMockModel, new(mocks.TodoInterface), new(mocks.CategoryInterface), new(mocks.AIUsageInterface), config.ProgramConfig{})
+
+			req := httptest.NewRequest(http.MethodDelete, "/todoai/threads/"+tc.param, nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.param)
+
+			err := TodoAIController.DeleteThread()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoController_SendMessage(t *testing.T) {
+	test := []struct {
+		name             string
+		param            string
+		body             string
+		mock             func(*mocks.TodoAIInterface)
+		quota            error
+		expectedHttpCode int
+	}{
+		{
+			name:  "Should be Success",
+			param: "1",
+			body:  `{"content":"pindahkan ke sore"}`,
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SendMessage", mock.Anything, 1, uint(1), "pindahkan ke sore").Return(&model.TodoAIMessage{
+					Role:        "assistant",
+					Content:     "Sudah dipindah ke sore.",
+					Suggestions: []model.TodoSuggestion{{Memo: "Olahraga", DateTime: time.Now(), Duration: 60, Category: "Umum"}},
+				}, nil)
+			},
+			expectedHttpCode: 201,
+		},
+		{
+			name:  "Should be error, because thread not found",
+			param: "2",
+			body:  `{"content":"halo"}`,
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SendMessage", mock.Anything, 2, uint(1), "halo").Return(nil, model.ErrThreadNotFound)
+			},
+			expectedHttpCode: 404,
+		},
+		{
+			name:  "Should be error, because ai returned invalid reply",
+			param: "1",
+			body:  `{"content":"halo"}`,
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SendMessage", mock.Anything, 1, uint(1), "halo").Return(nil, model.ErrInvalidAIOutput)
+			},
+			expectedHttpCode: 502,
+		},
+		{
+			name:  "Should be error, because provider error",
+			param: "1",
+			body:  `{"content":"halo"}`,
+			mock: func(m *mocks.TodoAIInterface) {
+				m.On("SendMessage", mock.Anything, 1, uint(1), "halo").Return(nil, errors.New("provider down"))
+			},
+			expectedHttpCode: 500,
+		},
+		{
+			name:             "Should be error, because quota exceeded",
+			param:            "1",
+			body:             `{"content":"halo"}`,
+			mock:             func(m *mocks.TodoAIInterface) {},
+			quota:            &model.QuotaExceededError{Period: "daily", Limit: 100, Used: 120, ResetAt: time.Now().Add(time.Hour)},
+			expectedHttpCode: 429,
+		},
+		{
+			name:             "Should be error, because content empty",
+			param:            "1",
+			body:             `{"content":"   "}`,
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 400,
+		},
+		{
+			name:             "Should be error, because content too long",
+			param:            "1",
+			body:             `{"content":"` + strings.Repeat("a", 2001) + `"}`,
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 400,
+		},
+		{
+			name:             "Should be error, because id format wrong",
+			param:            "satu",
+			body:             `{"content":"halo"}`,
+			mock:             func(m *mocks.TodoAIInterface) {},
+			expectedHttpCode: 400,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoAiMockModel := new(mocks.TodoAIInterface)
+			usageMockModel := new(mocks.AIUsageInterface)
+			usageMockModel.On("CheckQuota", uint(1)).Return(tc.quota).Maybe()
+
+			tc.mock(todoAiMockModel)
+
+			TodoAIController := NewTodoAIControllerInterface(todoAiMockModel, new(mocks.TodoInterface), new(mocks.CategoryInterface), usageMockModel, config.ProgramConfig{})
+
+			req := httptest.NewRequest(http.MethodPost, "/todoai/threads/"+tc.param+"/messages", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.param)
+
+			err := TodoAIController.SendMessage()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+			todoAiMockModel.AssertExpectations(tt)
+		})
+	}
+}
+
+func TestTodoController_GetWeeklyReview(t *testing.T) {
+	week := time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)
+	review := &model.WeeklyReview{Week: "2026-W42", Stats: model.ReviewStats{Total: 1}, NarrativeSource: model.NarrativeSourceAI}
+	test := []struct {
+		name             string
+		query            string
+		mock             func(*mocks.TodoAIInterface, *mocks.TodoInterface)
+		quota            error
+		expectedHttpCode int
+	}{
+		{
+			name:  "Should be Success",
+			query: "?week=2026-W42",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.MatchedBy(func(filter model.TodoFilter) bool {
+					return filter.From.Equal(week) && filter.To.Equal(week.AddDate(0, 0, 7).Add(-time.Second))
+				})).Return([]model.Todo{{Memo: "Rapat"}}, int64(1), nil)
+				m.On("ReviewWeek", mock.Anything, week, []model.Todo{{Memo: "Rapat"}}, true, false, uint(1)).Return(review, nil)
+			},
+			expectedHttpCode: 200,
+		},
+		{
+			name:  "Should be Success, with all pages and refresh",
+			query: "?week=2026-W42&refresh=true",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.Anything).Return(make([]model.Todo, 100), int64(150), nil)
+				tm.On("GetTodos", 2, 100, uint(1), mock.Anything).Return(make([]model.Todo, 50), int64(150), nil)
+				m.On("ReviewWeek", mock.Anything, week, mock.MatchedBy(func(todos []model.Todo) bool { return len(todos) == 150 }), true, true, uint(1)).Return(review, nil)
+			},
+			expectedHttpCode: 200,
+		},
+		{
+			name: "Should be Success, default current week with local narrative because quota exceeded",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.Anything).Return([]model.Todo{}, int64(0), nil)
+				m.On("ReviewWeek", mock.Anything, mock.MatchedBy(func(start time.Time) bool {
+					return start.Weekday() == time.Monday && time.Since(start) < 7*24*time.Hour
+				}), []model.Todo{}, false, false, uint(1)).Return(review, nil)
+			},
+			quota:            &model.QuotaExceededError{Period: "daily"},
+			expectedHttpCode: 200,
+		},
+		{
+			name:  "Should be error, because ai returned invalid review",
+			query: "?week=2026-W42",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.Anything).Return([]model.Todo{}, int64(0), nil)
+				m.On("ReviewWeek", mock.Anything, week, []model.Todo{}, true, false, uint(1)).Return(nil, model.ErrInvalidAIOutput)
+			},
+			expectedHttpCode: 502,
+		},
+		{
+			name:  "Should be error, because unexpected return from review",
+			query: "?week=2026-W42",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.Anything).Return([]model.Todo{}, int64(0), nil)
+				m.On("ReviewWeek", mock.Anything, week, []model.Todo{}, true, false, uint(1)).Return(nil, errors.New("db down"))
+			},
+			expectedHttpCode: 500,
+		},
+		{
+			name:  "Should be error, because unexpected return from todo model",
+			query: "?week=2026-W42",
+			mock: func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface) {
+				tm.On("GetTodos", 1, 100, uint(1), mock.Anything).Return(nil, int64(0), errors.New("database is down"))
+			},
+			expectedHttpCode: 500,
+		},
+		{
+			name:             "Should be error, because week format wrong",
+			query:            "?week=2026-42",
+			mock:             func(m *mocks.TodoAIInterface, tm *mocks.TodoInterface) {},
+			expectedHttpCode: 400,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			todoAiMockModel := new(mocks.TodoAIInterface)
+			todoMockModel := new(mocks.TodoInterface)
+			usageMockModel := new(mocks.AIUsageInterface)
+			usageMockModel.On("CheckQuota", uint(1)).Return(tc.quota).Maybe()
+
+			tc.mock(todoAiMockModel, todoMockModel)
+
+			TodoAIController := NewTodoAIControllerInterface(todoAiMockModel, todoMockModel, new(mocks.CategoryInterface), usageMockModel, config.ProgramConfig{})
+
+			req := httptest.NewRequest(http.MethodGet, "/todoai/review"+tc.query, nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			serve(ctx, TodoAIController.GetWeeklyReview())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+			todoAiMockModel.AssertExpectations(tt)
+			todoMockModel.AssertExpectations(tt)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"mytodo/helper"
+	"mytodo/model"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TodoItemControllerInterface interface {
+	AddItem() echo.HandlerFunc
+	GetItems() echo.HandlerFunc
+	UpdateItem() echo.HandlerFunc
+	DeleteItem() echo.HandlerFunc
+}
+
+type TodoItemController struct {
+	model model.TodoItemInterface
+}
+
+func NewTodoItemControllerInterface(m model.TodoItemInterface) TodoItemControllerInterface {
+	return &TodoItemController{
+		model: m,
+	}
+}
+
+func (ic *TodoItemController) AddItem() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Id Todo Wrong")
+		}
+		data := model.TodoItem{}
+		if err := c.Bind(&data); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data")
+		}
+		data.TodoID = uint(idTodo)
+		res, err := ic.model.AddItem(data, uint(id))
+		if err != nil {
+			return fail("Create Todo Item Failed", err)
+		}
+		return c.JSON(http.StatusCreated, helper.FormatResponse("Create Todo Item Successfull", res))
+	}
+}
+
+func (ic *TodoItemController) GetItems() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Id Todo Wrong")
+		}
+		res, err := ic.model.GetItems(idTodo, uint(id))
+		if err != nil {
+			return fail("Get Todo Items Failed", err)
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Get Todo Items Successfull", res))
+	}
+}
+
+func (ic *TodoItemController) UpdateItem() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Id Todo Wrong")
+		}
+		idItem, err := strconv.Atoi(c.Param("itemId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Id Item Wrong")
+		}
+		data := model.TodoItemUpdate{}
+		if err := c.Bind(&data); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Error Bind Data")
+		}
+		if err := ic.model.UpdateItem(idItem, idTodo, uint(id), data); err != nil {
+			return fail("Update Todo Item Failed", err)
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Update Todo Item Successfull", nil))
+	}
+}
+
+func (ic *TodoItemController) DeleteItem() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		idTodo, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Id Todo Wrong")
+		}
+		idItem, err := strconv.Atoi(c.Param("itemId"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Format Id Item Wrong")
+		}
+		if err := ic.model.DeleteItem(idItem, idTodo, uint(id)); err != nil {
+			return fail("Delete Todo Item Failed", err)
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Delete Todo Item Successfull", nil))
+	}
+}
@@ -0,0 +1,369 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"mytodo/model"
+	"mytodo/model/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTodoItemController_AddItem(t *testing.T) {
+	mockRequest := model.TodoItem{
+		Title: "Siapkan slide demo",
+	}
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoItemInterface)
+		expectedHttpCode int
+		in               any
+		id               string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("AddItem", mock.Anything, uint(1)).Return(&model.TodoItem{TodoID: 1, Title: "Siapkan slide demo", Position: 1}, nil)
+			},
+			expectedHttpCode: 201,
+			in:               mockRequest,
+			id:               "1",
+		},
+		{
+			name: "Should be error, because unexpected return from todo item model",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("AddItem", mock.Anything, uint(1)).Return(nil, errors.New("database is down"))
+			},
+			expectedHttpCode: 500,
+			in:               mockRequest,
+			id:               "1",
+		},
+		{
+			name: "Should be error, because todo not found",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("AddItem", mock.Anything, uint(1)).Return(nil, fmt.Errorf("todo 1: %w", model.ErrNotFound))
+			},
+			expectedHttpCode: 404,
+			in:               mockRequest,
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.TodoItemInterface) {},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+			id:               "!",
+		},
+		{
+			name:             "Should be error, because invalid parse body",
+			mock:             func(m *mocks.TodoItemInterface) {},
+			expectedHttpCode: 400,
+			in: map[string]any{
+				"title": 123,
+			},
+			id: "1",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			itemMockModel := new(mocks.TodoItemInterface)
+
+			tc.mock(itemMockModel)
+
+			itemController := NewTodoItemControllerInterface(itemMockModel)
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/todo/:id/items", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.id)
+
+			serve(ctx, itemController.AddItem())
+
+			w := res.Result()
+			_, err = io.ReadAll(w.Body)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, w.StatusCode)
+		})
+	}
+}
+
+func TestTodoItemController_GetItems(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoItemInterface)
+		expectedHttpCode int
+		id               string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("GetItems", 1, uint(1)).Return([]model.TodoItem{}, nil)
+			},
+			expectedHttpCode: 200,
+			id:               "1",
+		},
+		{
+			name: "Should be error, because todo not found",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("GetItems", 1, uint(1)).Return(nil, fmt.Errorf("todo 1: %w", model.ErrNotFound))
+			},
+			expectedHttpCode: 404,
+			id:               "1",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.TodoItemInterface) {},
+			expectedHttpCode: 400,
+			id:               "!",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			itemMockModel := new(mocks.TodoItemInterface)
+
+			tc.mock(itemMockModel)
+
+			itemController := NewTodoItemControllerInterface(itemMockModel)
+
+			req := httptest.NewRequest(http.MethodGet, "/todo/:id/items", nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(tc.id)
+
+			serve(ctx, itemController.GetItems())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoItemController_UpdateItem(t *testing.T) {
+	mockRequest := model.TodoItem{
+		Title: "Siapkan slide demo",
+		Done:  true,
+	}
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoItemInterface)
+		expectedHttpCode int
+		in               any
+		id               string
+		itemId           string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("UpdateItem", 2, 1, uint(1), mock.Anything).Return(nil)
+			},
+			expectedHttpCode: 200,
+			in:               mockRequest,
+			id:               "1",
+			itemId:           "2",
+		},
+		{
+			name: "Should keep done when only the title is sent",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("UpdateItem", 2, 1, uint(1), mock.MatchedBy(func(item model.TodoItemUpdate) bool {
+					return item.Title == "Siapkan slide demo" && item.Done == nil
+				})).Return(nil)
+			},
+			expectedHttpCode: 200,
+			in: map[string]any{
+				"title": "Siapkan slide demo",
+			},
+			id:     "1",
+			itemId: "2",
+		},
+		{
+			name: "Should be error, because unexpected return from todo item model",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("UpdateItem", 2, 1, uint(1), mock.Anything).Return(errors.New("database is down"))
+			},
+			expectedHttpCode: 500,
+			in:               mockRequest,
+			id:               "1",
+			itemId:           "2",
+		},
+		{
+			name: "Should be error, because item not found",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("UpdateItem", 2, 1, uint(1), mock.Anything).Return(fmt.Errorf("todo 1 item 2: %w", model.ErrNotFound))
+			},
+			expectedHttpCode: 404,
+			in:               mockRequest,
+			id:               "1",
+			itemId:           "2",
+		},
+		{
+			name: "Should be error, because auto complete rejected by workflow",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("UpdateItem", 2, 1, uint(1), mock.Anything).Return(fmt.Errorf("%w from Todo to Done", model.ErrIllegalTransition))
+			},
+			expectedHttpCode: 409,
+			in:               mockRequest,
+			id:               "1",
+			itemId:           "2",
+		},
+		{
+			name:             "Should be error, because item id value format wrong",
+			mock:             func(m *mocks.TodoItemInterface) {},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+			id:               "1",
+			itemId:           "!",
+		},
+		{
+			name:             "Should be error, because invalid parse body",
+			mock:             func(m *mocks.TodoItemInterface) {},
+			expectedHttpCode: 400,
+			in: map[string]any{
+				"done": "yes",
+			},
+			id:     "1",
+			itemId: "2",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			itemMockModel := new(mocks.TodoItemInterface)
+
+			tc.mock(itemMockModel)
+
+			itemController := NewTodoItemControllerInterface(itemMockModel)
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, "/todo/:id/items/:itemId", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id", "itemId")
+			ctx.SetParamValues(tc.id, tc.itemId)
+
+			serve(ctx, itemController.UpdateItem())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestTodoItemController_DeleteItem(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.TodoItemInterface)
+		expectedHttpCode int
+		id               string
+		itemId           string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("DeleteItem", 2, 1, uint(1)).Return(nil)
+			},
+			expectedHttpCode: 200,
+			id:               "1",
+			itemId:           "2",
+		},
+		{
+			name: "Should be error, because unexpected return from todo item model",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("DeleteItem", 2, 1, uint(1)).Return(errors.New("database is down"))
+			},
+			expectedHttpCode: 500,
+			id:               "1",
+			itemId:           "2",
+		},
+		{
+			name: "Should be error, because item not found",
+			mock: func(m *mocks.TodoItemInterface) {
+				m.On("DeleteItem", 2, 1, uint(1)).Return(fmt.Errorf("todo 1 item 2: %w", model.ErrNotFound))
+			},
+			expectedHttpCode: 404,
+			id:               "1",
+			itemId:           "2",
+		},
+		{
+			name:             "Should be error, because id value format wrong",
+			mock:             func(m *mocks.TodoItemInterface) {},
+			expectedHttpCode: 400,
+			id:               "!",
+			itemId:           "2",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			itemMockModel := new(mocks.TodoItemInterface)
+
+			tc.mock(itemMockModel)
+
+			itemController := NewTodoItemControllerInterface(itemMockModel)
+
+			req := httptest.NewRequest(http.MethodDelete, "/todo/:id/items/:itemId", nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+			ctx.SetParamNames("id", "itemId")
+			ctx.SetParamValues(tc.id, tc.itemId)
+
+			serve(ctx, itemController.DeleteItem())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package controller
 
 import (
+	"fmt"
 	"mytodo/config"
 	"mytodo/helper"
 	"mytodo/model"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -12,16 +14,21 @@ import (
 type UsersControllerInterface interface {
 	Register() echo.HandlerFunc
 	Login() echo.HandlerFunc
+	Refresh() echo.HandlerFunc
+	Logout() echo.HandlerFunc
+	LogoutAll() echo.HandlerFunc
 }
 
 type UsersController struct {
 	cfg   config.ProgramConfig
 	model model.UsersInterface
+	token model.TokenInterface
 }
 
-func NewUsersControllerInterface(m model.UsersInterface, cf config.ProgramConfig) UsersControllerInterface {
+func NewUsersControllerInterface(m model.UsersInterface, t model.TokenInterface, cf config.ProgramConfig) UsersControllerInterface {
 	return &UsersController{
 		model: m,
+		token: t,
 		cfg:   cf,
 	}
 }
@@ -30,12 +37,19 @@ func (uc *UsersController) Register() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		data := model.Users{}
 		if err := c.Bind(&data); err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Register Failed, Error Bind Data", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Register Failed, Error Bind Data")
+		}
+		if err := c.Validate(&data); err != nil {
+			return fail("Register Failed", err)
+		}
+		// batas bcrypt dihitung per byte, password non latin bisa melewatinya
+		if len(data.Password) > helper.MaxPasswordBytes {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Register Failed, Password Must Be At Most %d Bytes", helper.MaxPasswordBytes))
 		}
 
-		res := uc.model.Register(data)
-		if res == nil {
-			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Register Failed", nil))
+		res, err := uc.model.Register(data)
+		if err != nil {
+			return fail("Register Failed", err)
 		}
 		return c.JSON(http.StatusCreated, helper.FormatResponse("Register Successfull", res))
 	}
@@ -45,18 +59,103 @@ func (uc *UsersController) Login() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		data := model.Login{}
 		if err := c.Bind(&data); err != nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Login Failed, Error Bind Data", nil))
+			return echo.NewHTTPError(http.StatusBadRequest, "Login Failed, Error Bind Data")
 		}
-		res := uc.model.Login(data)
-		if res == nil {
-			return c.JSON(http.StatusNotFound, helper.FormatResponse("Login Failed, Username or Password Wrong", nil))
+		if err := c.Validate(&data); err != nil {
+			return fail("Login Failed", err)
 		}
-		token := helper.GenerateJWT(uc.cfg.Secret, res.ID)
+		res, err := uc.model.Login(data)
+		if err != nil {
+			return fail("Login Failed, Username or Password Wrong", err)
+		}
+		token := uc.issueTokens(res.ID, helper.GenerateTokenID(), nil)
 		if token == nil {
-			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Login Failed, Error Generate JWT", nil))
+			return echo.NewHTTPError(http.StatusInternalServerError, "Login Failed, Error Generate JWT")
 		}
 		token["info"] = res
 		c.Set("user", token["access_token"])
 		return c.JSON(http.StatusOK, helper.FormatResponse("Login Successfull", token))
 	}
 }
+
+func (uc *UsersController) Refresh() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		data := model.RefreshRequest{}
+		if err := c.Bind(&data); err != nil || data.RefreshToken == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Refresh Token Failed, Error Bind Data")
+		}
+		stored := uc.token.GetRefreshToken(helper.HashToken(data.RefreshToken))
+		if stored == nil {
+			return c.JSON(http.StatusUnauthorized, helper.FormatResponse("Refresh Token Invalid", nil))
+		}
+		if stored.RevokedAt != nil {
+			uc.token.RevokeTokenFamily(stored.Family)
+			return c.JSON(http.StatusUnauthorized, helper.FormatResponse("Refresh Token Reuse Detected, Session Revoked", nil))
+		}
+		if stored.ExpiresAt.Before(time.Now()) {
+			return c.JSON(http.StatusUnauthorized, helper.FormatResponse("Refresh Token Expired", nil))
+		}
+		token := uc.issueTokens(stored.UserID, stored.Family, stored)
+		if token == nil {
+			return c.JSON(http.StatusUnauthorized, helper.FormatResponse("Refresh Token Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Refresh Token Successfull", token))
+	}
+}
+
+func (uc *UsersController) Logout() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		jti, _ := claims["jti"].(string)
+		exp, _ := claims["exp"].(float64)
+		if !uc.token.RevokeSession(jti, uint(id), time.Unix(int64(exp), 0)) {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Logout Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Logout Successfull", nil))
+	}
+}
+
+func (uc *UsersController) LogoutAll() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		if !uc.token.RevokeAllTokens(uint(id)) {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Logout All Sessions Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Logout All Sessions Successfull", nil))
+	}
+}
+
+// issueTokens membuat pasangan access token dan refresh token baru. Jika old
+// tidak nil, refresh token lama dirotasi dan hanya boleh dipakai sekali.
+func (uc *UsersController) issueTokens(userID uint, family string, old *model.RefreshToken) map[string]any {
+	jti := helper.GenerateTokenID()
+	refreshToken := helper.GenerateRefreshToken()
+	if jti == "" || refreshToken == "" || family == "" {
+		return nil
+	}
+	token := helper.GenerateJWT(uc.cfg.Secret, userID, jti, uc.cfg.AccessTTL)
+	if token == nil {
+		return nil
+	}
+	now := time.Now()
+	stored := model.RefreshToken{
+		UserID:          userID,
+		TokenHash:       helper.HashToken(refreshToken),
+		Family:          family,
+		AccessJTI:       jti,
+		AccessExpiresAt: now.Add(uc.cfg.AccessTTL),
+		ExpiresAt:       now.Add(uc.cfg.RefreshTTL),
+	}
+	if old == nil {
+		if !uc.token.SaveRefreshToken(stored) {
+			return nil
+		}
+	} else if !uc.token.RotateRefreshToken(*old, stored) {
+		uc.token.RevokeTokenFamily(family)
+		return nil
+	}
+	token["refresh_token"] = refreshToken
+	return token
+}
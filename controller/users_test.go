@@ -1,9 +1,11 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"bytes"
 	"encoding/json"
@@ -14,6 +16,7 @@ import (
 	"net/http/httptest"
 	"strings"
 
+	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -21,10 +24,11 @@ import (
 
 func TestUserController_Register(t *testing.T) {
 
-	mockRequest := model.Users{
-		Name:     "bobi",
-		Email:    "agus@gmail.com",
-		Password: "Something",
+	// password tidak ikut saat model.Users di-encode, sehingga request ditulis sebagai map
+	mockRequest := map[string]any{
+		"name":     "bobi",
+		"email":    "agus@gmail.com",
+		"password": "Something",
 	}
 	mockUserResult := &model.Users{
 		Name:     "Budi",
@@ -41,7 +45,7 @@ func TestUserController_Register(t *testing.T) {
 		{
 			name: "should be success",
 			mock: func(m *mocks.UsersInterface) {
-				m.On("Register", mock.Anything).Return(mockUserResult)
+				m.On("Register", mock.Anything).Return(mockUserResult, nil)
 			},
 			expectedHttpCode: 201,
 			in:               mockRequest,
@@ -49,21 +53,55 @@ func TestUserController_Register(t *testing.T) {
 		{
 			name: "should be error, because unexpected return from register model",
 			mock: func(m *mocks.UsersInterface) {
-				m.On("Register", mock.Anything).Return(nil)
+				m.On("Register", mock.Anything).Return(nil, errors.New("database is down"))
 			},
 			expectedHttpCode: 500,
 			in:               mockRequest,
 		},
+		{
+			name: "should be error, because email already registered",
+			mock: func(m *mocks.UsersInterface) {
+				m.On("Register", mock.Anything).Return(nil, fmt.Errorf("user: %w", model.ErrConflict))
+			},
+			expectedHttpCode: 409,
+			in:               mockRequest,
+		},
 		{
 			name: "should be error, because invalid parse body",
 			mock: func(m *mocks.UsersInterface) {
-				m.On("Register", mock.Anything).Return(nil)
+				m.On("Register", mock.Anything).Return(nil, errors.New("database is down"))
 			},
 			expectedHttpCode: 400,
 			in: map[string]interface{}{
 				"name": 1234,
 			},
 		},
+		{
+			name:             "should be error, because email invalid",
+			mock:             func(m *mocks.UsersInterface) {},
+			expectedHttpCode: 422,
+			in:               map[string]any{"name": "bobi", "email": "agus", "password": "Something"},
+		},
+		{
+			name:             "should be error, because password longer than 72 bytes",
+			mock:             func(m *mocks.UsersInterface) {},
+			expectedHttpCode: 400,
+			in:               map[string]any{"name": "bobi", "email": "agus@gmail.com", "password": strings.Repeat("密", 30)},
+		},
+		{
+			name: "should be success, because 72 bytes password fits bcrypt",
+			mock: func(m *mocks.UsersInterface) {
+				m.On("Register", mock.Anything).Return(&model.Users{Name: "bobi"}, nil)
+			},
+			expectedHttpCode: 201,
+			in:               map[string]any{"name": "bobi", "email": "agus@gmail.com", "password": strings.Repeat("密", 24)},
+		},
+		{
+			name:             "should be error, because password too short",
+			mock:             func(m *mocks.UsersInterface) {},
+			expectedHttpCode: 422,
+			in:               map[string]any{"name": "bobi", "email": "agus@gmail.com", "password": "a"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -73,7 +111,7 @@ func TestUserController_Register(t *testing.T) {
 
 			tc.mock(userMockModel)
 
-			userController := NewUsersControllerInterface(userMockModel, config)
+			userController := NewUsersControllerInterface(userMockModel, new(mocks.TokenInterface), config)
 			handlerFunc := userController.Register()
 
 			buf := new(bytes.Buffer)
@@ -88,14 +126,16 @@ func TestUserController_Register(t *testing.T) {
 			e := echo.New()
 			ctx := e.NewContext(req, res)
 
-			err = handlerFunc(ctx)
-			require.NoError(t, err)
+			serve(ctx, handlerFunc)
 
 			w := res.Result()
-			_, err = io.ReadAll(w.Body)
+			body, err := io.ReadAll(w.Body)
 			require.NoError(t, err)
 
 			require.Equal(t, tc.expectedHttpCode, w.StatusCode)
+			// nama field password boleh muncul di error validasi, nilainya tidak
+			require.NotContains(t, string(body), `"password":`)
+			require.NotContains(t, string(body), "Something")
 		})
 	}
 }
@@ -117,11 +157,12 @@ func TestUsersController_Login(t *testing.T) {
 		mock             func(*mocks.UsersInterface)
 		expectedHttpCode int
 		in               any
+		tokenFail        bool
 	}{
 		{
 			name: "should be success",
 			mock: func(m *mocks.UsersInterface) {
-				m.On("Login", mock.Anything).Return(mockUserResult)
+				m.On("Login", mock.Anything).Return(mockUserResult, nil)
 			},
 			expectedHttpCode: 200,
 			in:               mockRequest,
@@ -133,7 +174,7 @@ func TestUsersController_Login(t *testing.T) {
 				// 	Name:     "Budi",
 				// 	Email:    "agus@gmail.com",
 				// 	Password: "Something",
-				// })
+				// }, nil)
 			},
 			expectedHttpCode: 400,
 			in: map[string]interface{}{
@@ -144,7 +185,7 @@ func TestUsersController_Login(t *testing.T) {
 		{
 			name: "should be error, because email or password wrong",
 			mock: func(m *mocks.UsersInterface) {
-				m.On("Login", mock.Anything).Return(nil)
+				m.On("Login", mock.Anything).Return(nil, model.ErrInvalidCredentials)
 			},
 			expectedHttpCode: 404,
 			in: map[string]interface{}{
@@ -152,10 +193,33 @@ func TestUsersController_Login(t *testing.T) {
 				"password": "Something",
 			},
 		},
+		{
+			name: "should be success, because login only checks email is filled",
+			mock: func(m *mocks.UsersInterface) {
+				m.On("Login", model.Login{Email: "agus", Password: "Something"}).Return(mockUserResult, nil)
+			},
+			expectedHttpCode: 200,
+			in:               map[string]any{"email": "agus", "password": "Something"},
+		},
+		{
+			name:             "should be error, because email empty",
+			mock:             func(m *mocks.UsersInterface) {},
+			expectedHttpCode: 422,
+			in:               map[string]any{"email": "", "password": "Something"},
+		},
+		{
+			name: "should be error, because token cannot be saved",
+			mock: func(m *mocks.UsersInterface) {
+				m.On("Login", mock.Anything).Return(mockUserResult, nil)
+			},
+			expectedHttpCode: 500,
+			in:               mockRequest,
+			tokenFail:        true,
+		},
 		// {
 		// 	name: "should be error, because token error",
 		// 	mock: func(m *mocks.UsersInterface) {
-		// 		m.On("Login", mock.Anything).Return("")
+		// 		m.On("Login", mock.Anything).Return("", nil)
 		// 	},
 		// 	expectedHttpCode: 400,
 		// 	in:               mockRequest,
@@ -167,9 +231,12 @@ func TestUsersController_Login(t *testing.T) {
 			userMockModel := new(mocks.UsersInterface)
 			config := config.ProgramConfig{}
 
+			tokenMockModel := new(mocks.TokenInterface)
+			tokenMockModel.On("SaveRefreshToken", mock.Anything).Return(!tc.tokenFail)
+
 			tc.mock(userMockModel)
 
-			UsersController := NewUsersControllerInterface(userMockModel, config)
+			UsersController := NewUsersControllerInterface(userMockModel, tokenMockModel, config)
 			handlerFunc := UsersController.Login()
 
 			buf := new(bytes.Buffer)
@@ -184,8 +251,7 @@ func TestUsersController_Login(t *testing.T) {
 			e := echo.New()
 			ctx := e.NewContext(req, res)
 
-			err = handlerFunc(ctx)
-			require.NoError(t, err)
+			serve(ctx, handlerFunc)
 
 			w := res.Result()
 			body, err := io.ReadAll(w.Body)
@@ -193,6 +259,180 @@ func TestUsersController_Login(t *testing.T) {
 			require.NoError(t, err)
 
 			require.Equal(t, tc.expectedHttpCode, w.StatusCode)
+			require.NotContains(t, string(body), "password")
+		})
+	}
+}
+
+func TestUsersController_Refresh(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+	activeToken := &model.RefreshToken{
+		UserID:    1,
+		Family:    "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	tests := []struct {
+		name             string
+		mock             func(*mocks.TokenInterface)
+		expectedHttpCode int
+		in               any
+	}{
+		{
+			name: "should be success",
+			mock: func(m *mocks.TokenInterface) {
+				m.On("GetRefreshToken", mock.Anything).Return(activeToken)
+				m.On("RotateRefreshToken", mock.Anything, mock.Anything).Return(true)
+			},
+			expectedHttpCode: 200,
+			in:               model.RefreshRequest{RefreshToken: "token"},
+		},
+		{
+			name: "should be error, because refresh token not found",
+			mock: func(m *mocks.TokenInterface) {
+				m.On("GetRefreshToken", mock.Anything).Return(nil)
+			},
+			expectedHttpCode: 401,
+			in:               model.RefreshRequest{RefreshToken: "token"},
+		},
+		{
+			name: "should be error, because refresh token reused",
+			mock: func(m *mocks.TokenInterface) {
+				m.On("GetRefreshToken", mock.Anything).Return(&model.RefreshToken{
+					UserID:    1,
+					Family:    "family",
+					ExpiresAt: time.Now().Add(time.Hour),
+					RevokedAt: &revokedAt,
+				})
+				m.On("RevokeTokenFamily", "family").Return(true).Once()
+			},
+			expectedHttpCode: 401,
+			in:               model.RefreshRequest{RefreshToken: "token"},
+		},
+		{
+			name: "should be error, because refresh token expired",
+			mock: func(m *mocks.TokenInterface) {
+				m.On("GetRefreshToken", mock.Anything).Return(&model.RefreshToken{
+					UserID:    1,
+					Family:    "family",
+					ExpiresAt: time.Now().Add(-time.Hour),
+				})
+			},
+			expectedHttpCode: 401,
+			in:               model.RefreshRequest{RefreshToken: "token"},
+		},
+		{
+			name: "should be error, because rotation lost the race",
+			mock: func(m *mocks.TokenInterface) {
+				m.On("GetRefreshToken", mock.Anything).Return(activeToken)
+				m.On("RotateRefreshToken", mock.Anything, mock.Anything).Return(false)
+				m.On("RevokeTokenFamily", "family").Return(true).Once()
+			},
+			expectedHttpCode: 401,
+			in:               model.RefreshRequest{RefreshToken: "token"},
+		},
+		{
+			name:             "should be error, because refresh token empty",
+			mock:             func(m *mocks.TokenInterface) {},
+			expectedHttpCode: 400,
+			in:               model.RefreshRequest{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenMockModel := new(mocks.TokenInterface)
+			config := config.ProgramConfig{AccessTTL: time.Minute, RefreshTTL: time.Hour}
+
+			tc.mock(tokenMockModel)
+
+			UsersController := NewUsersControllerInterface(new(mocks.UsersInterface), tokenMockModel, config)
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			e := echo.New()
+			ctx := e.NewContext(req, res)
+
+			serve(ctx, UsersController.Refresh())
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+			tokenMockModel.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsersController_Logout(t *testing.T) {
+	tests := []struct {
+		name             string
+		mock             func(*mocks.TokenInterface)
+		handler          func(UsersControllerInterface) echo.HandlerFunc
+		expectedHttpCode int
+	}{
+		{
+			name: "should be success logout",
+			mock: func(m *mocks.TokenInterface) {
+				m.On("RevokeSession", "jti", uint(1), mock.Anything).Return(true)
+			},
+			handler:          UsersControllerInterface.Logout,
+			expectedHttpCode: 200,
+		},
+		{
+			name: "should be error, because unexpected return from token model on logout",
+			mock: func(m *mocks.TokenInterface) {
+				m.On("RevokeSession", "jti", uint(1), mock.Anything).Return(false)
+			},
+			handler:          UsersControllerInterface.Logout,
+			expectedHttpCode: 500,
+		},
+		{
+			name: "should be success logout all",
+			mock: func(m *mocks.TokenInterface) {
+				m.On("RevokeAllTokens", uint(1)).Return(true)
+			},
+			handler:          UsersControllerInterface.LogoutAll,
+			expectedHttpCode: 200,
+		},
+		{
+			name: "should be error, because unexpected return from token model on logout all",
+			mock: func(m *mocks.TokenInterface) {
+				m.On("RevokeAllTokens", uint(1)).Return(false)
+			},
+			handler:          UsersControllerInterface.LogoutAll,
+			expectedHttpCode: 500,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenMockModel := new(mocks.TokenInterface)
+			tc.mock(tokenMockModel)
+
+			UsersController := NewUsersControllerInterface(new(mocks.UsersInterface), tokenMockModel, config.ProgramConfig{})
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id":  float64(1),
+				"jti": "jti",
+				"exp": float64(time.Now().Add(time.Minute).Unix()),
+			}
+
+			e := echo.New()
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			err := tc.handler(UsersController)(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
 		})
 	}
 }
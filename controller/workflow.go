@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"errors"
+	"mytodo/helper"
+	"mytodo/model"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WorkflowControllerInterface interface {
+	GetWorkflow() echo.HandlerFunc
+	SetWorkflow() echo.HandlerFunc
+	ResetWorkflow() echo.HandlerFunc
+}
+
+type WorkflowController struct {
+	model model.WorkflowInterface
+}
+
+func NewWorkflowControllerInterface(m model.WorkflowInterface) WorkflowControllerInterface {
+	return &WorkflowController{
+		model: m,
+	}
+}
+
+func (wc *WorkflowController) GetWorkflow() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		categoryID, err := categoryIDParam(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Category Format Wrong", nil))
+		}
+		res := wc.model.GetWorkflow(uint(id), categoryID)
+		if res == nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Get Workflow Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Get Workflow Successfull", res))
+	}
+}
+
+func (wc *WorkflowController) SetWorkflow() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		data := model.Workflow{}
+		if err := c.Bind(&data); err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Error Bind Data", nil))
+		}
+		err := wc.model.SetWorkflow(uint(id), data)
+		if errors.Is(err, model.ErrInvalidWorkflow) {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Update Workflow Failed, "+err.Error(), nil))
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Update Workflow Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Update Workflow Successfull", nil))
+	}
+}
+
+func (wc *WorkflowController) ResetWorkflow() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := helper.ExtractToken("user", c)
+		id := claims["id"].(float64)
+		categoryID, err := categoryIDParam(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, helper.FormatResponse("Id Category Format Wrong", nil))
+		}
+		if !wc.model.ResetWorkflow(uint(id), categoryID) {
+			return c.JSON(http.StatusInternalServerError, helper.FormatResponse("Reset Workflow Failed", nil))
+		}
+		return c.JSON(http.StatusOK, helper.FormatResponse("Reset Workflow Successfull", nil))
+	}
+}
+
+func categoryIDParam(c echo.Context) (uint, error) {
+	value := c.QueryParam("category_id")
+	if value == "" {
+		return 0, nil
+	}
+	categoryID, err := strconv.ParseUint(value, 10, 64)
+	return uint(categoryID), err
+}
@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"mytodo/model"
+	"mytodo/model/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowController_GetWorkflow(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.WorkflowInterface)
+		expectedHttpCode int
+		categoryID       string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.WorkflowInterface) {
+				m.On("GetWorkflow", uint(1), uint(2)).Return(&model.Workflow{Scope: "default", Transitions: model.DefaultTransitions})
+			},
+			expectedHttpCode: 200,
+			categoryID:       "2",
+		},
+		{
+			name: "Should be error, because unexpected return from workflow model",
+			mock: func(m *mocks.WorkflowInterface) {
+				m.On("GetWorkflow", uint(1), uint(0)).Return(nil)
+			},
+			expectedHttpCode: 500,
+			categoryID:       "",
+		},
+		{
+			name:             "Should be error, because category id format wrong",
+			mock:             func(m *mocks.WorkflowInterface) {},
+			expectedHttpCode: 400,
+			categoryID:       "abc",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			workflowMockModel := new(mocks.WorkflowInterface)
+
+			tc.mock(workflowMockModel)
+
+			workflowController := NewWorkflowControllerInterface(workflowMockModel)
+
+			req := httptest.NewRequest(http.MethodGet, "/workflow", nil)
+			q := req.URL.Query()
+			q.Add("category_id", tc.categoryID)
+			req.URL.RawQuery = q.Encode()
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			err := workflowController.GetWorkflow()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestWorkflowController_SetWorkflow(t *testing.T) {
+	mockRequest := model.Workflow{
+		Transitions: map[string][]string{
+			"Todo":       {"InProgress"},
+			"InProgress": {"Done"},
+		},
+	}
+	test := []struct {
+		name             string
+		mock             func(*mocks.WorkflowInterface)
+		expectedHttpCode int
+		in               any
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.WorkflowInterface) {
+				m.On("SetWorkflow", uint(1), mock.Anything).Return(nil)
+			},
+			expectedHttpCode: 200,
+			in:               mockRequest,
+		},
+		{
+			name: "Should be error, because workflow invalid",
+			mock: func(m *mocks.WorkflowInterface) {
+				m.On("SetWorkflow", uint(1), mock.Anything).Return(fmt.Errorf("%w: unknown status OnGoing", model.ErrInvalidWorkflow))
+			},
+			expectedHttpCode: 400,
+			in:               mockRequest,
+		},
+		{
+			name: "Should be error, because unexpected return from workflow model",
+			mock: func(m *mocks.WorkflowInterface) {
+				m.On("SetWorkflow", uint(1), mock.Anything).Return(errors.New("Something error"))
+			},
+			expectedHttpCode: 500,
+			in:               mockRequest,
+		},
+		{
+			name:             "Should be error, because invalid parse body",
+			mock:             func(m *mocks.WorkflowInterface) {},
+			expectedHttpCode: 400,
+			in: map[string]any{
+				"transitions": "Todo",
+			},
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			workflowMockModel := new(mocks.WorkflowInterface)
+
+			tc.mock(workflowMockModel)
+
+			workflowController := NewWorkflowControllerInterface(workflowMockModel)
+
+			buf := new(bytes.Buffer)
+			err := json.NewEncoder(buf).Encode(tc.in)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, "/workflow", strings.NewReader(buf.String()))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			err = workflowController.SetWorkflow()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
+
+func TestWorkflowController_ResetWorkflow(t *testing.T) {
+	test := []struct {
+		name             string
+		mock             func(*mocks.WorkflowInterface)
+		expectedHttpCode int
+		categoryID       string
+	}{
+		{
+			name: "Should be Success",
+			mock: func(m *mocks.WorkflowInterface) {
+				m.On("ResetWorkflow", uint(1), uint(2)).Return(true)
+			},
+			expectedHttpCode: 200,
+			categoryID:       "2",
+		},
+		{
+			name: "Should be error, because unexpected return from workflow model",
+			mock: func(m *mocks.WorkflowInterface) {
+				m.On("ResetWorkflow", uint(1), uint(2)).Return(false)
+			},
+			expectedHttpCode: 500,
+			categoryID:       "2",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(tt *testing.T) {
+			e := echo.New()
+
+			workflowMockModel := new(mocks.WorkflowInterface)
+
+			tc.mock(workflowMockModel)
+
+			workflowController := NewWorkflowControllerInterface(workflowMockModel)
+
+			req := httptest.NewRequest(http.MethodDelete, "/workflow?category_id="+tc.categoryID, nil)
+			res := httptest.NewRecorder()
+
+			jwtMock := jwt.New(jwt.SigningMethodHS256)
+			jwtMock.Claims = jwt.MapClaims{
+				"id": float64(1),
+			}
+
+			ctx := e.NewContext(req, res)
+			ctx.Set("user", jwtMock)
+
+			err := workflowController.ResetWorkflow()(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedHttpCode, res.Result().StatusCode)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ParseISOWeek membaca minggu ISO 8601 berformat 2006-W01 dan mengembalikan
+// Senin 00:00 minggu tersebut di zona waktu loc
+func ParseISOWeek(value string, loc *time.Location) (time.Time, error) {
+	var year, week int
+	if n, err := fmt.Sscanf(value, "%4d-W%2d", &year, &week); err != nil || n != 2 || len(value) != 8 {
+		return time.Time{}, errors.New("week must use format YYYY-Www")
+	}
+	// 4 Januari selalu berada di minggu pertama
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	start := monday.AddDate(0, 0, (week-1)*7)
+	if y, w := start.ISOWeek(); week < 1 || y != year || w != week {
+		return time.Time{}, fmt.Errorf("week %d does not exist in %d", week, year)
+	}
+	return start, nil
+}
+
+// FormatISOWeek mengembalikan minggu ISO 8601 dari t, misal 2026-W42
+func FormatISOWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// StartOfISOWeek mengembalikan Senin 00:00 minggu t
+func StartOfISOWeek(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,38 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseISOWeek(t *testing.T) {
+	test := []struct {
+		name      string
+		value     string
+		expected  time.Time
+		expectErr bool
+	}{
+		{name: "Week in middle of year", value: "2026-W42", expected: time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "First week starts in previous year", value: "2026-W01", expected: time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "Year with 53 weeks", value: "2020-W53", expected: time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC)},
+		{name: "Should be error, because week 53 does not exist", value: "2025-W53", expectErr: true},
+		{name: "Should be error, because week 0", value: "2026-W00", expectErr: true},
+		{name: "Should be error, because format wrong", value: "2026-42", expectErr: true},
+		{name: "Should be error, because trailing text", value: "2026-W421", expectErr: true},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			start, err := ParseISOWeek(tc.value, time.UTC)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, start)
+			require.Equal(t, tc.value, FormatISOWeek(start))
+			require.Equal(t, start, StartOfISOWeek(start.Add(6*24*time.Hour+23*time.Hour)))
+		})
+	}
+}
@@ -1,27 +1,33 @@
 package helper
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 )
 
-func GenerateJWT(signKey string, userID uint) map[string]any {
+func GenerateJWT(signKey string, userID uint, jti string, ttl time.Duration) map[string]any {
 	res := map[string]any{}
-	accessToken := generateToken(signKey, userID)
+	accessToken := generateToken(signKey, userID, jti, ttl)
 	if accessToken == "" {
 		return nil
 	}
 	res["access_token"] = accessToken
+	res["expires_in"] = int(ttl.Seconds())
 	return res
 }
 
-func generateToken(signKey string, id uint) string {
+func generateToken(signKey string, id uint, jti string, ttl time.Duration) string {
 	claims := jwt.MapClaims{}
 	claims["id"] = id
+	claims["jti"] = jti
 	claims["iat"] = time.Now().Unix()
-	claims["exp"] = time.Now().Add(time.Hour * 1).Unix()
+	claims["exp"] = time.Now().Add(ttl).Unix()
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	validToken, err := token.SignedString([]byte(signKey))
@@ -36,3 +42,27 @@ func ExtractToken(name string, c echo.Context) jwt.MapClaims {
 	claims := user.Claims.(jwt.MapClaims)
 	return claims
 }
+
+// GenerateTokenID membuat id unik untuk claim jti access token
+func GenerateTokenID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// GenerateRefreshToken membuat refresh token acak yang hanya dikirim ke client,
+// yang disimpan di database hanyalah hasil HashToken
+func GenerateRefreshToken() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
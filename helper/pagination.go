@@ -0,0 +1,115 @@
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// Pagination adalah metadata halaman yang dikirim bersama data list
+type Pagination struct {
+	Total      int64  `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page"`
+	TotalPages int    `json:"total_pages"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type cursorPayload struct {
+	ID uint `json:"id"`
+}
+
+// PageParams membaca page dan content. Nilai kosong memakai default dan
+// content dibatasi MaxPageSize, nilai yang bukan angka dikembalikan sebagai error.
+func PageParams(pageValue, contentValue string) (int, int, error) {
+	page, perPage := 1, DefaultPageSize
+	if pageValue != "" {
+		value, err := strconv.Atoi(pageValue)
+		if err != nil {
+			return 0, 0, err
+		}
+		page = max(value, 1)
+	}
+	if contentValue != "" {
+		value, err := strconv.Atoi(contentValue)
+		if err != nil {
+			return 0, 0, err
+		}
+		if value > 0 {
+			perPage = min(value, MaxPageSize)
+		}
+	}
+	return page, perPage, nil
+}
+
+// NewPagination menghitung jumlah halaman dan link next/prev berdasarkan URL request
+func NewPagination(u *url.URL, page, perPage int, total int64) Pagination {
+	res := Pagination{
+		Total:      total,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: int((total + int64(perPage) - 1) / int64(perPage)),
+	}
+	if page < res.TotalPages {
+		res.Next = pageLink(u, "page", strconv.Itoa(page+1))
+	}
+	if page > 1 && res.TotalPages > 0 {
+		res.Prev = pageLink(u, "page", strconv.Itoa(min(page-1, res.TotalPages)))
+	}
+	return res
+}
+
+// NewCursorPagination membuat metadata untuk mode cursor, nextID bernilai 0 jika sudah halaman terakhir
+func NewCursorPagination(u *url.URL, perPage int, total int64, nextID uint) Pagination {
+	res := Pagination{
+		Total:      total,
+		PerPage:    perPage,
+		TotalPages: int((total + int64(perPage) - 1) / int64(perPage)),
+	}
+	if nextID != 0 {
+		res.NextCursor = EncodeCursor(nextID)
+		res.Next = pageLink(u, "cursor", res.NextCursor)
+	}
+	return res
+}
+
+func pageLink(u *url.URL, key, value string) string {
+	query := u.Query()
+	query.Set(key, value)
+	link := url.URL{Path: u.Path, RawQuery: query.Encode()}
+	return link.String()
+}
+
+// EncodeCursor membungkus id terakhir menjadi cursor yang tidak perlu dipahami client
+func EncodeCursor(id uint) string {
+	payload, _ := json.Marshal(cursorPayload{ID: id})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeCursor membaca cursor, cursor kosong berarti mulai dari awal
+func DecodeCursor(cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	payload := cursorPayload{}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return 0, err
+	}
+	if payload.ID == 0 {
+		return 0, errors.New("cursor: invalid id")
+	}
+	return payload.ID, nil
+}
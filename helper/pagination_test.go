@@ -0,0 +1,76 @@
+package helper
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageParams(t *testing.T) {
+	test := []struct {
+		name            string
+		page            string
+		content         string
+		expectedPage    int
+		expectedPerPage int
+		expectErr       bool
+	}{
+		{name: "Defaults when empty", expectedPage: 1, expectedPerPage: DefaultPageSize},
+		{name: "Given values", page: "3", content: "20", expectedPage: 3, expectedPerPage: 20},
+		{name: "Page size is capped", page: "1", content: "1000", expectedPage: 1, expectedPerPage: MaxPageSize},
+		{name: "Non positive values fall back", page: "0", content: "-5", expectedPage: 1, expectedPerPage: DefaultPageSize},
+		{name: "Should be error, because page not a number", page: "abc", expectErr: true},
+		{name: "Should be error, because content not a number", content: "abc", expectErr: true},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			page, perPage, err := PageParams(tc.page, tc.content)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPage, page)
+			require.Equal(t, tc.expectedPerPage, perPage)
+		})
+	}
+}
+
+func TestNewPagination(t *testing.T) {
+	u, err := url.Parse("/todo?page=2&content=10&status=Todo")
+	require.NoError(t, err)
+
+	res := NewPagination(u, 2, 10, 25)
+	require.Equal(t, 3, res.TotalPages)
+	require.Equal(t, "/todo?content=10&page=3&status=Todo", res.Next)
+	require.Equal(t, "/todo?content=10&page=1&status=Todo", res.Prev)
+
+	res = NewPagination(u, 3, 10, 25)
+	require.Empty(t, res.Next)
+
+	res = NewPagination(u, 1, 10, 0)
+	require.Equal(t, 0, res.TotalPages)
+	require.Empty(t, res.Next)
+	require.Empty(t, res.Prev)
+}
+
+func TestCursor(t *testing.T) {
+	cursor := EncodeCursor(42)
+	id, err := DecodeCursor(cursor)
+	require.NoError(t, err)
+	require.Equal(t, uint(42), id)
+
+	id, err = DecodeCursor("")
+	require.NoError(t, err)
+	require.Zero(t, id)
+
+	_, err = DecodeCursor("not a cursor")
+	require.Error(t, err)
+
+	u, err := url.Parse("/todo?cursor=&content=5")
+	require.NoError(t, err)
+	res := NewCursorPagination(u, 5, 12, 42)
+	require.Equal(t, cursor, res.NextCursor)
+	require.Equal(t, "/todo?content=5&cursor="+cursor, res.Next)
+}
@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxPasswordBytes adalah batas panjang password bcrypt dalam byte, bukan karakter
+const MaxPasswordBytes = 72
+
+func HashPassword(password string, cost int) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword mencocokkan password dengan nilai yang tersimpan di database.
+// Baris lama yang masih plaintext tetap diterima, tetapi needRehash bernilai true
+// agar pemanggil dapat menggantinya dengan hash. Hal yang sama berlaku jika cost
+// hash berbeda dengan konfigurasi saat ini.
+func CheckPassword(stored, password string, cost int) (match bool, needRehash bool) {
+	if !isBcryptHash(stored) {
+		match = subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+		return match, match
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)); err != nil {
+		return false, false
+	}
+	current, err := bcrypt.Cost([]byte(stored))
+	return true, err != nil || current != cost
+}
+
+func isBcryptHash(value string) bool {
+	if len(value) != 60 {
+		return false
+	}
+	return strings.HasPrefix(value, "$2a$") || strings.HasPrefix(value, "$2b$") || strings.HasPrefix(value, "$2y$")
+}
@@ -7,4 +7,27 @@ func FormatResponse(msg string, data any) map[string]any{
 		response["data"] = data
 	}
 	return response
-}
\ No newline at end of file
+}
+
+// FormatPaginatedResponse sama seperti FormatResponse dengan tambahan metadata halaman
+func FormatPaginatedResponse(msg string, data any, pagination Pagination) map[string]any {
+	response := FormatResponse(msg, data)
+	response["pagination"] = pagination
+	return response
+}
+
+// FormatErrorResponse menambahkan objek error berisi code yang bisa dibaca
+// mesin, detail hanya diisi jika boleh ditampilkan ke client dan fields
+// berisi daftar field yang gagal validasi
+func FormatErrorResponse(msg string, code string, detail string, fields []FieldError) map[string]any {
+	response := FormatResponse(msg, nil)
+	apiError := map[string]any{"code": code}
+	if detail != "" {
+		apiError["detail"] = detail
+	}
+	if len(fields) > 0 {
+		apiError["fields"] = fields
+	}
+	response["error"] = apiError
+	return response
+}
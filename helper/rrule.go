@@ -0,0 +1,317 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// batas jumlah periode yang diperiksa agar rule tanpa COUNT/UNTIL tidak berputar tanpa akhir
+const maxRRulePeriods = 50000
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// RRule adalah subset RFC 5545: FREQ (DAILY/WEEKLY/MONTHLY/YEARLY), INTERVAL,
+// BYDAY, COUNT dan UNTIL. Minggu selalu dimulai hari Senin (WKST=MO).
+type RRule struct {
+	Freq     string
+	Interval int
+	ByDay    []WeekdayNum
+	Count    int
+	Until    time.Time
+}
+
+// WeekdayNum adalah satu nilai BYDAY. N bernilai 0 untuk semua hari tersebut,
+// atau urutan ke-N (negatif dihitung dari akhir bulan) pada FREQ=MONTHLY.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+func ParseRRule(value string) (*RRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("rrule: empty rule")
+	}
+	rule := &RRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, found := strings.Cut(part, "=")
+		if !found || val == "" {
+			return nil, fmt.Errorf("rrule: invalid part %q", part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+			switch rule.Freq {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+			default:
+				return nil, fmt.Errorf("rrule: unsupported FREQ %q", val)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("rrule: invalid INTERVAL %q", val)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("rrule: invalid COUNT %q", val)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(val), ",") {
+				weekday, err := parseWeekdayNum(day)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		default:
+			return nil, fmt.Errorf("rrule: unsupported part %q, allowed FREQ, INTERVAL, BYDAY, COUNT, UNTIL", key)
+		}
+	}
+	if rule.Freq == "" {
+		return nil, errors.New("rrule: FREQ is required")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, errors.New("rrule: COUNT and UNTIL cannot be used together")
+	}
+	if rule.Freq == FreqYearly && len(rule.ByDay) > 0 {
+		return nil, errors.New("rrule: BYDAY is not supported with FREQ=YEARLY")
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != FreqMonthly {
+			return nil, errors.New("rrule: numbered BYDAY is only supported with FREQ=MONTHLY")
+		}
+	}
+	return rule, nil
+}
+
+func parseUntil(val string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", val); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", val, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", val, time.Local); err == nil {
+		return t.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return time.Time{}, fmt.Errorf("rrule: invalid UNTIL %q", val)
+}
+
+func parseWeekdayNum(val string) (WeekdayNum, error) {
+	if len(val) < 2 {
+		return WeekdayNum{}, fmt.Errorf("rrule: invalid BYDAY %q", val)
+	}
+	weekday, found := weekdayCodes[val[len(val)-2:]]
+	if !found {
+		return WeekdayNum{}, fmt.Errorf("rrule: invalid BYDAY %q", val)
+	}
+	res := WeekdayNum{Weekday: weekday}
+	if prefix := val[:len(val)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n > 5 || n < -5 {
+			return WeekdayNum{}, fmt.Errorf("rrule: invalid BYDAY %q", val)
+		}
+		res.N = n
+	}
+	return res, nil
+}
+
+// String mengembalikan bentuk normal dari rule sehingga dapat disimpan di database
+func (r *RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			code := ""
+			for key, weekday := range weekdayCodes {
+				if weekday == day.Weekday {
+					code = key
+				}
+			}
+			if day.N != 0 {
+				code = strconv.Itoa(day.N) + code
+			}
+			days = append(days, code)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between mengembalikan semua kejadian dari dtstart yang berada di rentang [from, to]
+func (r *RRule) Between(dtstart, from, to time.Time) []time.Time {
+	res := []time.Time{}
+	r.Iterate(dtstart, func(t time.Time) bool {
+		if t.After(to) {
+			return false
+		}
+		if !t.Before(from) {
+			res = append(res, t)
+		}
+		return true
+	})
+	return res
+}
+
+// After mengembalikan kejadian pertama yang terjadi setelah t
+func (r *RRule) After(dtstart, t time.Time) (time.Time, bool) {
+	var res time.Time
+	found := false
+	r.Iterate(dtstart, func(occurrence time.Time) bool {
+		if occurrence.After(t) {
+			res = occurrence
+			found = true
+			return false
+		}
+		return true
+	})
+	return res, found
+}
+
+// Iterate memanggil fn untuk setiap kejadian secara berurutan sampai fn
+// mengembalikan false atau rule berakhir
+func (r *RRule) Iterate(dtstart time.Time, fn func(time.Time) bool) {
+	emitted := 0
+	for period := 0; period < maxRRulePeriods; period++ {
+		for _, candidate := range r.candidates(dtstart, period) {
+			if candidate.Before(dtstart) {
+				continue
+			}
+			if !r.Until.IsZero() && candidate.After(r.Until) {
+				return
+			}
+			if !fn(candidate) {
+				return
+			}
+			emitted++
+			if r.Count > 0 && emitted >= r.Count {
+				return
+			}
+		}
+	}
+}
+
+func (r *RRule) candidates(dtstart time.Time, period int) []time.Time {
+	h, m, s := dtstart.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, h, m, s, dtstart.Nanosecond(), dtstart.Location())
+	}
+	step := period * r.Interval
+	switch r.Freq {
+	case FreqDaily:
+		day := dtstart.AddDate(0, 0, step)
+		if len(r.ByDay) > 0 && !r.hasWeekday(day.Weekday()) {
+			return nil
+		}
+		return []time.Time{day}
+	case FreqWeekly:
+		if len(r.ByDay) == 0 {
+			return []time.Time{dtstart.AddDate(0, 0, 7*step)}
+		}
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		monday := at(dtstart.Year(), dtstart.Month(), dtstart.Day()-offset+7*step)
+		res := []time.Time{}
+		for i := 0; i < 7; i++ {
+			day := monday.AddDate(0, 0, i)
+			if r.hasWeekday(day.Weekday()) {
+				res = append(res, day)
+			}
+		}
+		return res
+	case FreqMonthly:
+		first := at(dtstart.Year(), dtstart.Month()+time.Month(step), 1)
+		if len(r.ByDay) == 0 {
+			day := at(first.Year(), first.Month(), dtstart.Day())
+			if day.Month() != first.Month() {
+				return nil
+			}
+			return []time.Time{day}
+		}
+		return r.monthlyByDay(first)
+	case FreqYearly:
+		day := at(dtstart.Year()+step, dtstart.Month(), dtstart.Day())
+		if day.Month() != dtstart.Month() {
+			return nil
+		}
+		return []time.Time{day}
+	}
+	return nil
+}
+
+func (r *RRule) monthlyByDay(first time.Time) []time.Time {
+	days := []time.Time{}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	res := []time.Time{}
+	for _, byDay := range r.ByDay {
+		matches := []time.Time{}
+		for _, day := range days {
+			if day.Weekday() == byDay.Weekday {
+				matches = append(matches, day)
+			}
+		}
+		switch {
+		case byDay.N == 0:
+			res = append(res, matches...)
+		case byDay.N > 0 && byDay.N <= len(matches):
+			res = append(res, matches[byDay.N-1])
+		case byDay.N < 0 && -byDay.N <= len(matches):
+			res = append(res, matches[len(matches)+byDay.N])
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Before(res[j]) })
+	unique := []time.Time{}
+	for _, day := range res {
+		if len(unique) == 0 || !day.Equal(unique[len(unique)-1]) {
+			unique = append(unique, day)
+		}
+	}
+	return unique
+}
+
+func (r *RRule) hasWeekday(weekday time.Weekday) bool {
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRRule(t *testing.T) {
+	test := []struct {
+		name      string
+		in        string
+		expected  string
+		expectErr bool
+	}{
+		{name: "Should be Success, weekly by day", in: "FREQ=WEEKLY;BYDAY=MO,WE", expected: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{name: "Should be Success, with prefix and interval", in: "RRULE:freq=daily;interval=2;count=3", expected: "FREQ=DAILY;INTERVAL=2;COUNT=3"},
+		{name: "Should be Success, monthly last friday", in: "FREQ=MONTHLY;BYDAY=-1FR", expected: "FREQ=MONTHLY;BYDAY=-1FR"},
+		{name: "Should be error, because FREQ missing", in: "COUNT=3", expectErr: true},
+		{name: "Should be error, because COUNT and UNTIL together", in: "FREQ=DAILY;COUNT=3;UNTIL=20231231", expectErr: true},
+		{name: "Should be error, because numbered BYDAY on weekly", in: "FREQ=WEEKLY;BYDAY=1MO", expectErr: true},
+		{name: "Should be error, because unsupported part", in: "FREQ=DAILY;BYHOUR=9", expectErr: true},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := ParseRRule(tc.in)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, rule.String())
+		})
+	}
+}
+
+func TestRRule_Between(t *testing.T) {
+	// Jumat, 3 November 2023 jam 09.00
+	dtstart := time.Date(2023, 11, 3, 9, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2023, month, d, 9, 0, 0, 0, time.UTC)
+	}
+	test := []struct {
+		name     string
+		rule     string
+		from     time.Time
+		to       time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "Daily with count",
+			rule:     "FREQ=DAILY;COUNT=3",
+			from:     dtstart,
+			to:       day(12, 31),
+			expected: []time.Time{day(11, 3), day(11, 4), day(11, 5)},
+		},
+		{
+			name:     "Weekly by day skips days before dtstart",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,FR;COUNT=4",
+			from:     dtstart,
+			to:       day(12, 31),
+			expected: []time.Time{day(11, 3), day(11, 6), day(11, 10), day(11, 13)},
+		},
+		{
+			name:     "Every two weeks inside range",
+			rule:     "FREQ=WEEKLY;INTERVAL=2",
+			from:     day(11, 10),
+			to:       day(12, 1),
+			expected: []time.Time{day(11, 17), day(12, 1)},
+		},
+		{
+			name:     "Monthly last friday until",
+			rule:     "FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20231231T000000Z",
+			from:     dtstart,
+			to:       day(12, 31),
+			expected: []time.Time{day(11, 24), day(12, 29)},
+		},
+		{
+			name:     "Monthly on day 31 skips short months",
+			rule:     "FREQ=MONTHLY;COUNT=2",
+			from:     time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC),
+			to:       day(12, 31),
+			expected: []time.Time{time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC), time.Date(2023, 3, 31, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := ParseRRule(tc.rule)
+			require.NoError(t, err)
+			start := dtstart
+			if tc.from.Before(dtstart) {
+				start = tc.from
+			}
+			require.Equal(t, tc.expected, rule.Between(start, tc.from, tc.to))
+		})
+	}
+}
+
+func TestRRule_After(t *testing.T) {
+	dtstart := time.Date(2023, 11, 3, 9, 0, 0, 0, time.UTC)
+	rule, err := ParseRRule("FREQ=YEARLY;COUNT=2")
+	require.NoError(t, err)
+
+	next, found := rule.After(dtstart, dtstart)
+	require.True(t, found)
+	require.Equal(t, time.Date(2024, 11, 3, 9, 0, 0, 0, time.UTC), next)
+
+	_, found = rule.After(dtstart, next)
+	require.False(t, found)
+}
@@ -0,0 +1,91 @@
+package helper
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// SearchTerms memecah kata kunci pencarian menjadi kata huruf kecil tanpa
+// operator boolean MySQL sehingga aman dipakai di MATCH ... AGAINST
+func SearchTerms(query string) []string {
+	terms := []string{}
+	seen := map[string]bool{}
+	for _, term := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool { return !isWordRune(r) }) {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// BooleanQuery menyusun query BOOLEAN MODE dengan prefix matching untuk setiap kata
+func BooleanQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, term+"*")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Highlight memotong text menjadi snippet sepanjang width karakter di sekitar
+// kata pertama yang cocok dan membungkus setiap kata yang diawali salah satu
+// term dengan <mark>. Text lain di-escape sebagai HTML.
+func Highlight(text string, terms []string, width int) (string, bool) {
+	runes := []rune(text)
+	type span struct{ start, end int }
+	matches := []span{}
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+		word := strings.ToLower(string(runes[i:j]))
+		for _, term := range terms {
+			if strings.HasPrefix(word, term) {
+				matches = append(matches, span{start: i, end: j})
+				break
+			}
+		}
+		i = j
+	}
+	start, end := 0, len(runes)
+	if width > 0 && len(runes) > width {
+		if len(matches) > 0 {
+			start = max(matches[0].start-width/4, 0)
+		}
+		end = min(start+width, len(runes))
+		start = max(end-width, 0)
+	}
+	escape := func(part []rune) string { return html.EscapeString(string(part)) }
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, match := range matches {
+		if match.end <= pos || match.start >= end {
+			continue
+		}
+		matchStart := max(match.start, pos)
+		matchEnd := min(match.end, end)
+		b.WriteString(escape(runes[pos:matchStart]))
+		b.WriteString("<mark>" + escape(runes[matchStart:matchEnd]) + "</mark>")
+		pos = matchEnd
+	}
+	b.WriteString(escape(runes[pos:end]))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String(), len(matches) > 0
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,62 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTerms(t *testing.T) {
+	require.Equal(t, []string{"rapat", "client", "x"}, SearchTerms(`+Rapat -client-x "rapat"*`))
+	require.Equal(t, "rapat* client* x*", BooleanQuery(SearchTerms("rapat client-x")))
+	require.Empty(t, SearchTerms(" +-* "))
+}
+
+func TestHighlight(t *testing.T) {
+	test := []struct {
+		name     string
+		text     string
+		terms    []string
+		width    int
+		expected string
+		matched  bool
+	}{
+		{
+			name:     "Prefix match wraps the whole word",
+			text:     "Rapat mingguan dengan klien",
+			terms:    []string{"ming", "klien"},
+			expected: "Rapat <mark>mingguan</mark> dengan <mark>klien</mark>",
+			matched:  true,
+		},
+		{
+			name:     "Text outside the match is escaped",
+			text:     "<b>beli</b> susu",
+			terms:    []string{"susu"},
+			expected: "&lt;b&gt;beli&lt;/b&gt; <mark>susu</mark>",
+			matched:  true,
+		},
+		{
+			name:     "Long text is cut around the first match",
+			text:     "satu dua tiga empat lima enam tujuh delapan sembilan sepuluh",
+			terms:    []string{"tujuh"},
+			width:    20,
+			expected: "…enam <mark>tujuh</mark> delapan s…",
+			matched:  true,
+		},
+		{
+			name:     "No match keeps the head of the text",
+			text:     "satu dua tiga empat",
+			terms:    []string{"lima"},
+			width:    8,
+			expected: "satu dua…",
+			matched:  false,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			snippet, matched := Highlight(tc.text, tc.terms, tc.width)
+			require.Equal(t, tc.expected, snippet)
+			require.Equal(t, tc.matched, matched)
+		})
+	}
+}
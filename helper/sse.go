@@ -0,0 +1,23 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WriteSSE menulis satu event Server-Sent Events dengan data JSON lalu flush
+// agar langsung terkirim ke client
+func WriteSSE(w http.ResponseWriter, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package helper
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSSE(t *testing.T) {
+	res := httptest.NewRecorder()
+	require.NoError(t, WriteSSE(res, "delta", map[string]string{"content": "a\nb"}))
+	require.NoError(t, WriteSSE(res, "done", FormatResponse("ok", nil)))
+	require.Equal(t, "event: delta\ndata: {\"content\":\"a\\nb\"}\n\nevent: done\ndata: {\"message\":\"ok\"}\n\n", res.Body.String())
+	require.True(t, res.Flushed)
+}
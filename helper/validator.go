@@ -0,0 +1,85 @@
+package helper
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	idtranslations "github.com/go-playground/validator/v10/translations/id"
+)
+
+// bahasa pesan validasi, bahasa lain memakai ValidationLangDefault
+const (
+	ValidationLangID      = "id"
+	ValidationLangEN      = "en"
+	ValidationLangDefault = ValidationLangEN
+)
+
+// FieldError adalah satu field yang gagal validasi beserta pesan yang sudah diterjemahkan
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Validator memvalidasi struct berdasarkan tag validate, dipasang sebagai echo.Validator
+type Validator struct {
+	validate    *validator.Validate
+	translators map[string]ut.Translator
+}
+
+func NewValidator() *Validator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	// nama field di pesan error memakai nama json yang dikirim client
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			return field.Name
+		}
+		return name
+	})
+	english, indonesian := en.New(), id.New()
+	uni := ut.New(english, english, indonesian)
+	translators := map[string]ut.Translator{}
+	translators[ValidationLangEN], _ = uni.GetTranslator(ValidationLangEN)
+	translators[ValidationLangID], _ = uni.GetTranslator(ValidationLangID)
+	if err := entranslations.RegisterDefaultTranslations(validate, translators[ValidationLangEN]); err != nil {
+		panic(err)
+	}
+	if err := idtranslations.RegisterDefaultTranslations(validate, translators[ValidationLangID]); err != nil {
+		panic(err)
+	}
+	return &Validator{validate: validate, translators: translators}
+}
+
+func (v *Validator) Validate(i any) error {
+	return v.validate.Struct(i)
+}
+
+// FieldErrors menerjemahkan error validasi ke bahasa lang. Hasilnya nil jika
+// err bukan error validasi field.
+func (v *Validator) FieldErrors(err error, lang string) []FieldError {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+	translator, found := v.translators[lang]
+	if !found {
+		translator = v.translators[ValidationLangDefault]
+	}
+	res := make([]FieldError, 0, len(errs))
+	for _, fieldErr := range errs {
+		// namespace tanpa nama struct, misal "memo" atau "steps[0].memo"
+		field := fieldErr.Namespace()
+		if i := strings.Index(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+		res = append(res, FieldError{Field: field, Rule: fieldErr.Tag(), Message: fieldErr.Translate(translator)})
+	}
+	return res
+}
@@ -0,0 +1,498 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mytodo/config"
+	"mytodo/helper"
+	"mytodo/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// integration test menjalankan app asli (route, controller dan model) di atas
+// SQLite in-memory yang dimigrasi dengan file migrasi sqlite, setiap test
+// mendapat database baru
+type testApp struct {
+	e  *echo.Echo
+	db *gorm.DB
+}
+
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+	cfg := config.ProgramConfig{
+		DBDriver:       model.DriverSQLite,
+		DBPath:         ":memory:",
+		Secret:         "integration-secret",
+		BcryptCost:     4,
+		AccessTTL:      15 * time.Minute,
+		RefreshTTL:     time.Hour,
+		AIProvider:     "fake",
+		AIDailyQuota:   50000,
+		AIMonthlyQuota: 1000000,
+	}
+	db := model.InitModel(cfg)
+	require.NotNil(t, db)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	require.NoError(t, checkSchema(db, true))
+	e, err := newServer(cfg, db)
+	require.NoError(t, err)
+	return &testApp{e: e, db: db}
+}
+
+type testResponse struct {
+	Code int
+	Body struct {
+		Message    string          `json:"message"`
+		Data       json.RawMessage `json:"data"`
+		Pagination map[string]any  `json:"pagination"`
+		Error      struct {
+			Code   string              `json:"code"`
+			Detail string              `json:"detail"`
+			Fields []helper.FieldError `json:"fields"`
+		} `json:"error"`
+	}
+}
+
+// data membaca field data response ke dest
+func (r testResponse) data(t *testing.T, dest any) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal(r.Body.Data, dest), "data: %s", r.Body.Data)
+}
+
+func (a *testApp) do(t *testing.T, method, path, token string, body any) testResponse {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	a.e.ServeHTTP(rec, req)
+	res := testResponse{Code: rec.Code}
+	if rec.Body.Len() > 0 {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res.Body), "%s %s: %s", method, path, rec.Body.String())
+	}
+	return res
+}
+
+// signup mendaftarkan user lewat /signup lalu login lewat /auth, mengembalikan access token
+func (a *testApp) signup(t *testing.T, name, email string) (uint, string) {
+	t.Helper()
+	res := a.do(t, http.MethodPost, "/signup", "", map[string]any{"name": name, "email": email, "password": "rahasia123"})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.Message)
+	user := model.Users{}
+	res.data(t, &user)
+
+	res = a.do(t, http.MethodPost, "/auth", "", map[string]any{"email": email, "password": "rahasia123"})
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	token := map[string]any{}
+	res.data(t, &token)
+	require.NotEmpty(t, token["access_token"])
+	return user.ID, token["access_token"].(string)
+}
+
+// seedTodo menyimpan todo langsung lewat model sebagai fixture
+func (a *testApp) seedTodo(t *testing.T, userID, categoryID uint, memo string, dateTime time.Time) model.Todo {
+	t.Helper()
+	todo, err := model.NewTodoModel(a.db).AddTodo(model.Todo{Memo: memo, DateTime: dateTime, Duration: 30, Status: model.StatusTodo, CategoryID: &categoryID, UserID: userID})
+	require.NoError(t, err)
+	return *todo
+}
+
+// firstCategory mengembalikan category pertama milik user, misalnya category default saat signup
+func (a *testApp) firstCategory(t *testing.T, token string) model.Category {
+	t.Helper()
+	res := a.do(t, http.MethodGet, "/category", token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	categories := []model.Category{}
+	res.data(t, &categories)
+	require.NotEmpty(t, categories)
+	return categories[0]
+}
+
+func TestIntegration_TodoFlow(t *testing.T) {
+	app := newTestApp(t)
+	userID, token := app.signup(t, "Budi", "budi@example.com")
+
+	res := app.do(t, http.MethodPost, "/category", token, map[string]any{"category": "Kantor", "color": "#1E88E5"})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodGet, "/category", token, nil)
+	require.Equal(t, http.StatusOK, res.Code)
+	categories := []model.Category{}
+	res.data(t, &categories)
+	var office model.Category
+	for _, category := range categories {
+		require.Equal(t, userID, category.UserID)
+		if category.Category == "Kantor" {
+			office = category
+		}
+	}
+	require.NotZero(t, office.ID)
+
+	dateTime := time.Date(2024, 3, 4, 9, 0, 0, 0, time.Local)
+	res = app.do(t, http.MethodPost, "/todo", token, map[string]any{
+		"memo":        "Rapat mingguan tim",
+		"date_time":   dateTime.Format(time.RFC3339),
+		"duration":    60,
+		"category_id": office.ID,
+		"tag_names":   []string{"rapat"},
+	})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.Message)
+	created := model.Todo{}
+	res.data(t, &created)
+	require.Equal(t, model.StatusTodo, created.Status)
+	require.Equal(t, userID, created.UserID)
+
+	res = app.do(t, http.MethodGet, fmt.Sprintf("/todo/%d", created.ID), token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	got := model.Todo{}
+	res.data(t, &got)
+	require.Equal(t, "Rapat mingguan tim", got.Memo)
+	require.Equal(t, "Kantor", got.Category.Category)
+	require.True(t, dateTime.Equal(got.DateTime))
+
+	for _, status := range []string{model.StatusInProgress, model.StatusDone} {
+		res = app.do(t, http.MethodPut, fmt.Sprintf("/todo/status/%d", created.ID), token, map[string]any{"status": status})
+		require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	}
+	res = app.do(t, http.MethodPut, fmt.Sprintf("/todo/status/%d", created.ID), token, map[string]any{"status": model.StatusBlocked})
+	require.Equal(t, http.StatusConflict, res.Code, "Done todo cannot be blocked")
+
+	res = app.do(t, http.MethodGet, fmt.Sprintf("/todo/%d/history", created.ID), token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	history := []model.TodoStatusHistory{}
+	res.data(t, &history)
+	require.Len(t, history, 2)
+	require.Equal(t, model.StatusDone, history[1].ToStatus)
+
+	// status dan jadwal todo harus tetap seperti di database saat dibaca dari list
+	app.seedTodo(t, userID, office.ID, "Tulis laporan", dateTime.AddDate(0, 0, 1))
+	res = app.do(t, http.MethodGet, "/todo?status=Done", token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	todos := []model.Todo{}
+	res.data(t, &todos)
+	require.Len(t, todos, 1)
+	require.Equal(t, created.ID, todos[0].ID)
+	require.Equal(t, model.StatusDone, todos[0].Status)
+	require.True(t, dateTime.Equal(todos[0].DateTime))
+
+	res = app.do(t, http.MethodGet, "/todo?date=2024-03-05", token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	res.data(t, &todos)
+	require.Len(t, todos, 1)
+	require.Equal(t, "Tulis laporan", todos[0].Memo)
+	require.Equal(t, model.StatusTodo, todos[0].Status)
+
+	res = app.do(t, http.MethodGet, "/todo/search?q=rapat&page=1&content=10", token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	results := []model.TodoSearchResult{}
+	res.data(t, &results)
+	require.Len(t, results, 1)
+	require.Equal(t, created.ID, results[0].Todo.ID)
+	require.EqualValues(t, 1, res.Body.Pagination["total"])
+
+	res = app.do(t, http.MethodDelete, fmt.Sprintf("/todo/%d", created.ID), token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodGet, fmt.Sprintf("/todo/%d", created.ID), token, nil)
+	require.Equal(t, http.StatusNotFound, res.Code)
+}
+
+func TestIntegration_TodoWithoutCategory(t *testing.T) {
+	app := newTestApp(t)
+	_, token := app.signup(t, "Budi", "budi@example.com")
+	dateTime := time.Date(2024, 3, 4, 9, 0, 0, 0, time.Local)
+
+	// todo tanpa category disimpan category_id NULL, foreign key tetap aktif
+	res := app.do(t, http.MethodPost, "/todo", token, map[string]any{"memo": "Tanpa category", "date_time": dateTime.Format(time.RFC3339)})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.Message)
+	created := model.Todo{}
+	res.data(t, &created)
+	require.Nil(t, created.CategoryID)
+
+	res = app.do(t, http.MethodPost, "/todo", token, map[string]any{"memo": "Category nol", "date_time": dateTime.Format(time.RFC3339), "category_id": 0})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.Message)
+	zero := model.Todo{}
+	res.data(t, &zero)
+	require.Nil(t, zero.CategoryID)
+
+	todoPath := fmt.Sprintf("/todo/%d", created.ID)
+	category := app.firstCategory(t, token)
+	res = app.do(t, http.MethodPut, todoPath, token, map[string]any{"memo": "Tanpa category", "date_time": dateTime.Format(time.RFC3339), "category_id": category.ID})
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodPut, todoPath, token, map[string]any{"memo": "Tanpa category", "date_time": dateTime.Format(time.RFC3339)})
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+
+	res = app.do(t, http.MethodGet, todoPath, token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	got := model.Todo{}
+	res.data(t, &got)
+	require.Nil(t, got.CategoryID)
+	require.Empty(t, got.Category.Category)
+
+	res = app.do(t, http.MethodPut, fmt.Sprintf("/todo/status/%d", created.ID), token, map[string]any{"status": model.StatusInProgress})
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+
+	res = app.do(t, http.MethodGet, "/todo", token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	todos := []model.Todo{}
+	res.data(t, &todos)
+	require.Len(t, todos, 2)
+
+	res = app.do(t, http.MethodGet, "/todoai/review?week=2024-W10", token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	review := model.WeeklyReview{}
+	res.data(t, &review)
+	require.Len(t, review.Stats.ByCategory, 1)
+	require.Nil(t, review.Stats.ByCategory[0].CategoryID)
+	require.Equal(t, 2, review.Stats.ByCategory[0].Total)
+}
+
+func TestIntegration_Auth(t *testing.T) {
+	app := newTestApp(t)
+	_, token := app.signup(t, "Budi", "budi@example.com")
+
+	res := app.do(t, http.MethodGet, "/todo", "", nil)
+	require.Equal(t, http.StatusBadRequest, res.Code, "missing token")
+	res = app.do(t, http.MethodGet, "/todo", "bukan-token", nil)
+	require.Equal(t, http.StatusUnauthorized, res.Code)
+
+	res = app.do(t, http.MethodPost, "/auth", "", map[string]any{"email": "budi@example.com", "password": "salah"})
+	require.Equal(t, http.StatusNotFound, res.Code)
+	require.Equal(t, "not_found", res.Body.Error.Code)
+
+	res = app.do(t, http.MethodPost, "/signup", "", map[string]any{"name": "Budi Lain", "email": "budi@example.com", "password": "rahasia123"})
+	require.Equal(t, http.StatusConflict, res.Code, res.Body.Message)
+	require.Equal(t, "conflict", res.Body.Error.Code)
+
+	res = app.do(t, http.MethodPost, "/auth/logout", token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodGet, "/todo", token, nil)
+	require.Equal(t, http.StatusUnauthorized, res.Code, "access token must be revoked after logout")
+}
+
+func TestIntegration_CrossUserIsolation(t *testing.T) {
+	app := newTestApp(t)
+	ownerID, owner := app.signup(t, "Budi", "budi@example.com")
+	_, other := app.signup(t, "Sari", "sari@example.com")
+
+	category := app.firstCategory(t, owner)
+	todo := app.seedTodo(t, ownerID, category.ID, "Rahasia milik Budi", time.Date(2024, 3, 4, 9, 0, 0, 0, time.Local))
+	todoPath := fmt.Sprintf("/todo/%d", todo.ID)
+	categoryPath := fmt.Sprintf("/category/%d", category.ID)
+
+	res := app.do(t, http.MethodGet, todoPath, other, nil)
+	require.Equal(t, http.StatusNotFound, res.Code)
+	require.Equal(t, "not_found", res.Body.Error.Code)
+	res = app.do(t, http.MethodGet, categoryPath, other, nil)
+	require.Equal(t, http.StatusNotFound, res.Code)
+	res = app.do(t, http.MethodGet, todoPath+"/items", other, nil)
+	require.Equal(t, http.StatusNotFound, res.Code, res.Body.Message)
+
+	// category milik user lain tidak boleh dipakai untuk todo sendiri
+	res = app.do(t, http.MethodPost, "/todo", other, map[string]any{"memo": "Pinjam category", "category_id": category.ID})
+	require.Equal(t, http.StatusForbidden, res.Code, res.Body.Message)
+	require.Equal(t, "forbidden", res.Body.Error.Code)
+
+	res = app.do(t, http.MethodGet, "/todo", other, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	todos := []model.Todo{}
+	res.data(t, &todos)
+	require.Empty(t, todos)
+
+	res = app.do(t, http.MethodGet, "/todo/search?q=rahasia&page=1&content=10", other, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	results := []model.TodoSearchResult{}
+	res.data(t, &results)
+	require.Empty(t, results)
+
+	// perubahan oleh user lain harus ditolak tanpa menyentuh data pemilik
+	for _, req := range []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{method: http.MethodPut, path: todoPath, body: map[string]any{"memo": "Diubah Sari"}},
+		{method: http.MethodPut, path: fmt.Sprintf("/todo/status/%d", todo.ID), body: map[string]any{"status": model.StatusDone}},
+		{method: http.MethodPost, path: todoPath + "/items", body: map[string]any{"title": "Item Sari"}},
+		{method: http.MethodPut, path: todoPath + "/items/1", body: map[string]any{"done": true}},
+		{method: http.MethodDelete, path: todoPath + "/items/1"},
+		{method: http.MethodDelete, path: todoPath},
+		{method: http.MethodPut, path: categoryPath, body: map[string]any{"category": "Diubah Sari"}},
+		{method: http.MethodDelete, path: categoryPath},
+	} {
+		res = app.do(t, req.method, req.path, other, req.body)
+		require.Equal(t, http.StatusNotFound, res.Code, "%s %s must fail for another user", req.method, req.path)
+	}
+
+	res = app.do(t, http.MethodPost, "/todoai/threads", owner, map[string]any{"title": "Rencana Budi"})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.Message)
+	thread := model.TodoAIThread{}
+	res.data(t, &thread)
+	threadPath := fmt.Sprintf("/todoai/threads/%d", thread.ID)
+	res = app.do(t, http.MethodDelete, threadPath, other, nil)
+	require.Equal(t, http.StatusNotFound, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodGet, threadPath, owner, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodDelete, threadPath, owner, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodDelete, threadPath, owner, nil)
+	require.Equal(t, http.StatusNotFound, res.Code, res.Body.Message)
+
+	res = app.do(t, http.MethodGet, todoPath, owner, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	got := model.Todo{}
+	res.data(t, &got)
+	require.Equal(t, "Rahasia milik Budi", got.Memo)
+	require.Equal(t, model.StatusTodo, got.Status)
+	require.Equal(t, category.Category, app.firstCategory(t, owner).Category)
+}
+
+func TestIntegration_Validation(t *testing.T) {
+	app := newTestApp(t)
+	_, token := app.signup(t, "Budi", "budi@example.com")
+
+	res := app.do(t, http.MethodPost, "/signup", "", map[string]any{"name": "Sari", "email": "", "password": "a"})
+	require.Equal(t, http.StatusUnprocessableEntity, res.Code, res.Body.Message)
+	require.Equal(t, "validation_failed", res.Body.Error.Code)
+	fields := []string{}
+	for _, field := range res.Body.Error.Fields {
+		fields = append(fields, field.Field)
+	}
+	require.Equal(t, []string{"email", "password"}, fields)
+
+	// batas bcrypt 72 byte, bukan 72 karakter
+	res = app.do(t, http.MethodPost, "/signup", "", map[string]any{"name": "Sari", "email": "sari@example.com", "password": strings.Repeat("密", 30)})
+	require.Equal(t, http.StatusBadRequest, res.Code, res.Body.Message)
+
+	res = app.do(t, http.MethodPost, "/todo?lang=id", token, map[string]any{"memo": ""})
+	require.Equal(t, http.StatusUnprocessableEntity, res.Code, res.Body.Message)
+	require.Equal(t, []helper.FieldError{{Field: "memo", Rule: "required", Message: "memo wajib diisi"}}, res.Body.Error.Fields)
+
+	res = app.do(t, http.MethodPost, "/category", token, map[string]any{"category": "Kantor", "color": "#zzz"})
+	require.Equal(t, http.StatusUnprocessableEntity, res.Code, res.Body.Message)
+	require.Equal(t, "color", res.Body.Error.Fields[0].Field)
+}
+
+func TestIntegration_Checklist(t *testing.T) {
+	app := newTestApp(t)
+	userID, token := app.signup(t, "Budi", "budi@example.com")
+	todo := app.seedTodo(t, userID, app.firstCategory(t, token).ID, "Siapkan demo", time.Date(2024, 3, 4, 9, 0, 0, 0, time.Local))
+	todoPath := fmt.Sprintf("/todo/%d", todo.ID)
+
+	// opsi checklist bisa diubah setelah todo dibuat
+	res := app.do(t, http.MethodPut, todoPath, token, map[string]any{"memo": "Siapkan demo", "auto_complete": true, "require_items_done": true})
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodGet, todoPath, token, nil)
+	got := model.Todo{}
+	res.data(t, &got)
+	require.True(t, got.AutoComplete)
+	require.True(t, got.RequireItemsDone)
+
+	res = app.do(t, http.MethodPost, todoPath+"/items", token, map[string]any{"title": "Slide"})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.Message)
+	item := model.TodoItem{}
+	res.data(t, &item)
+	itemPath := fmt.Sprintf("%s/items/%d", todoPath, item.ID)
+	res = app.do(t, http.MethodPost, todoPath+"/items", token, map[string]any{"title": "Demo data"})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.Message)
+
+	res = app.do(t, http.MethodPut, itemPath, token, map[string]any{"done": true})
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	// ganti judul saja tidak boleh membatalkan centang item
+	res = app.do(t, http.MethodPut, itemPath, token, map[string]any{"title": "Slide final"})
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+
+	res = app.do(t, http.MethodGet, todoPath+"/items", token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	items := []model.TodoItem{}
+	res.data(t, &items)
+	require.Len(t, items, 2)
+	require.Equal(t, "Slide final", items[0].Title)
+	require.True(t, items[0].Done)
+	require.False(t, items[1].Done)
+
+	res = app.do(t, http.MethodPut, fmt.Sprintf("%s/items/%d", todoPath, items[0].ID+100), token, map[string]any{"done": true})
+	require.Equal(t, http.StatusNotFound, res.Code, res.Body.Message)
+
+	// auto_complete yang ditolak workflow membatalkan centang item
+	res = app.do(t, http.MethodPut, "/workflow", token, map[string]any{"transitions": map[string][]string{model.StatusTodo: {model.StatusInProgress}, model.StatusInProgress: {model.StatusDone}}})
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodPut, fmt.Sprintf("%s/items/%d", todoPath, items[1].ID), token, map[string]any{"done": true})
+	require.Equal(t, http.StatusConflict, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodGet, todoPath+"/items", token, nil)
+	items = []model.TodoItem{}
+	res.data(t, &items)
+	require.False(t, items[1].Done)
+	res = app.do(t, http.MethodDelete, "/workflow", token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+
+	// item terakhir selesai membuat todo Done karena auto_complete
+	res = app.do(t, http.MethodPut, fmt.Sprintf("%s/items/%d", todoPath, items[1].ID), token, map[string]any{"done": true})
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodGet, todoPath, token, nil)
+	got = model.Todo{}
+	res.data(t, &got)
+	require.Equal(t, model.StatusDone, got.Status)
+}
+
+func TestIntegration_TagConflict(t *testing.T) {
+	app := newTestApp(t)
+	_, token := app.signup(t, "Budi", "budi@example.com")
+	_, other := app.signup(t, "Sari", "sari@example.com")
+
+	res := app.do(t, http.MethodPost, "/tag", token, map[string]any{"name": "Urgent"})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.Message)
+	res = app.do(t, http.MethodPost, "/tag", token, map[string]any{"name": "client-x"})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.Message)
+
+	res = app.do(t, http.MethodGet, "/tag", token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	tags := []model.Tag{}
+	res.data(t, &tags)
+	require.Len(t, tags, 2)
+	byName := map[string]model.Tag{}
+	for _, tag := range tags {
+		byName[tag.Name] = tag
+	}
+	tagPath := fmt.Sprintf("/tag/%d", byName["client-x"].ID)
+
+	// nama tag unik per user, jadi user lain tetap boleh memakai nama yang sama
+	res = app.do(t, http.MethodPost, "/tag", token, map[string]any{"name": " URGENT "})
+	require.Equal(t, http.StatusConflict, res.Code, res.Body.Message)
+	require.Equal(t, "conflict", res.Body.Error.Code)
+	res = app.do(t, http.MethodPost, "/tag", other, map[string]any{"name": "urgent"})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.Message)
+
+	res = app.do(t, http.MethodPut, tagPath, token, map[string]any{"name": "Urgent"})
+	require.Equal(t, http.StatusConflict, res.Code, res.Body.Message)
+	require.Equal(t, "conflict", res.Body.Error.Code)
+	res = app.do(t, http.MethodPut, tagPath, other, map[string]any{"name": "Sari"})
+	require.Equal(t, http.StatusNotFound, res.Code, res.Body.Message)
+
+	res = app.do(t, http.MethodGet, tagPath, token, nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.Message)
+	got := model.Tag{}
+	res.data(t, &got)
+	require.Equal(t, "client-x", got.Name)
+}
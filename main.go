@@ -1,42 +1,102 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"mytodo/ai"
 	"mytodo/config"
 	"mytodo/controller"
+	"mytodo/helper"
 	"mytodo/model"
 	"mytodo/routes"
+	"mytodo/scheduler"
+	"os"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 func main() {
-	e := echo.New()
+	autoMigrate := flag.Bool("auto-migrate", false, "jalankan migrasi yang tertinggal sebelum server start")
+	flag.Parse()
 	config := config.InitConfig()
+	if flag.Arg(0) == "migrate" {
+		err := runMigrate(*config, flag.Args()[1:])
+		if errors.Is(err, errMigrateUsage) {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			os.Exit(2)
+		}
+		if err != nil {
+			logrus.Fatal("Main: Migrasi Gagal, ", err.Error())
+		}
+		return
+	}
 
 	db := model.InitModel(*config)
-	model.Migrate(db)
+	if err := checkSchema(db, config.AutoMigrate || *autoMigrate); err != nil {
+		logrus.Fatal("Main: ", err.Error())
+	}
+	e, err := newServer(*config, db)
+	if err != nil {
+		logrus.Fatal("Main: ", err.Error())
+	}
+	e.Use(middleware.LoggerWithConfig(
+		middleware.LoggerConfig{
+			Format: "method=${method}, uri=${uri}, status=${status}, latency_human=${latency_human}\n",
+		}))
+
+	notifier, err := scheduler.NewNotifier(*config)
+	if err != nil {
+		logrus.Fatal("Main: Notifier Reminder Tidak Valid ", err.Error())
+	}
+	reminderScheduler := scheduler.NewScheduler(model.NewReminderModel(db), notifier, config.ReminderInterval, config.ReminderLease)
+	go reminderScheduler.Start(context.Background())
+
+	e.Logger.Fatal(e.Start(fmt.Sprintf(":%d", config.ServerPort)).Error())
+}
 
-	usersModel := model.NewUsersModel(db)
+// newServer menyusun model, controller dan route di atas db, dipakai main
+// dan integration test
+func newServer(config config.ProgramConfig, db *gorm.DB) (*echo.Echo, error) {
+	e := echo.New()
+	e.HTTPErrorHandler = controller.HTTPErrorHandler
+	e.Validator = helper.NewValidator()
+	usersModel := model.NewUsersModel(db, config.BcryptCost)
 	categoryModel := model.NewCategoryModel(db)
+	tagModel := model.NewTagModel(db)
 	todoModel := model.NewTodoModel(db)
-	todoAIModel := model.NewTodoAIModel(db)
+	todoItemModel := model.NewTodoItemModel(db)
+	workflowModel := model.NewWorkflowModel(db)
+	reminderModel := model.NewReminderModel(db)
+	aiProvider, err := ai.NewProvider(config)
+	if err != nil {
+		return nil, fmt.Errorf("Provider AI Tidak Valid %w", err)
+	}
+	todoAIModel := model.NewTodoAIModel(db, aiProvider, model.AIPricing{PromptPer1K: config.AIPromptPrice, CompletionPer1K: config.AICompletionPrice})
+	aiUsageModel := model.NewAIUsageModel(db, config.AIDailyQuota, config.AIMonthlyQuota)
+	tokenModel := model.NewTokenModel(db)
 
-	usersController := controller.NewUsersControllerInterface(usersModel, *config)
+	usersController := controller.NewUsersControllerInterface(usersModel, tokenModel, config)
 	categoryController := controller.NewCategoryControllerInterface(categoryModel)
-	todoController := controller.NewTodoControllerInterface(todoModel)
-	todoAIController := controller.NewTodoAIControllerInterface(todoAIModel, *config)
+	tagController := controller.NewTagControllerInterface(tagModel)
+	todoItemController := controller.NewTodoItemControllerInterface(todoItemModel)
+	workflowController := controller.NewWorkflowControllerInterface(workflowModel)
+	reminderController := controller.NewReminderControllerInterface(reminderModel)
+	todoController := controller.NewTodoControllerInterface(todoModel, todoAIModel, aiUsageModel)
+	todoAIController := controller.NewTodoAIControllerInterface(todoAIModel, todoModel, categoryModel, aiUsageModel, config)
 
 	e.Pre(middleware.RemoveTrailingSlash())
-	e.Use(middleware.LoggerWithConfig(
-		middleware.LoggerConfig{
-			Format: "method=${method}, uri=${uri}, status=${status}, latency_human=${latency_human}\n",
-		}))
-	routes.RouteUsers(e, usersController)
-	routes.RouteCategory(e, categoryController, *config)
-	routes.RouteTodo(e, todoController, *config)
-	routes.RouteTodoAI(e, todoAIController, *config)
-
-	e.Logger.Fatal(e.Start(fmt.Sprintf(":%d", config.ServerPort)).Error())
+	routes.RouteUsers(e, usersController, config, tokenModel)
+	routes.RouteCategory(e, categoryController, config, tokenModel)
+	routes.RouteTag(e, tagController, config, tokenModel)
+	routes.RouteTodo(e, todoController, config, tokenModel)
+	routes.RouteTodoItem(e, todoItemController, config, tokenModel)
+	routes.RouteWorkflow(e, workflowController, config, tokenModel)
+	routes.RouteReminder(e, reminderController, config, tokenModel)
+	routes.RouteTodoAI(e, todoAIController, config, tokenModel, usersModel)
+	return e, nil
 }
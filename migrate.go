@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mytodo/config"
+	"mytodo/migration"
+	"mytodo/model"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var errMigrateUsage = errors.New("migrate: invalid command")
+
+const migrateUsage = `usage: mytodo migrate <command>
+
+commands:
+  up             jalankan semua migrasi yang tertinggal
+  down [n]       batalkan n migrasi terakhir (default 1)
+  status         tampilkan migrasi dan waktu dijalankan
+  create <name>  buat pasangan file migrasi baru untuk setiap dialect`
+
+// runMigrate menjalankan subcommand mytodo migrate up|down|status|create
+func runMigrate(cfg config.ProgramConfig, args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+	if args[0] == "create" {
+		if len(args) < 2 {
+			return errMigrateUsage
+		}
+		files, err := migration.Create(filepath.Join("migration", "sql"), strings.Join(args[1:], "_"))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			fmt.Println("created", file)
+		}
+		return nil
+	}
+
+	steps := 1
+	switch {
+	case args[0] == "down" && len(args) > 1:
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return errors.New("down: n must be a positive number")
+		}
+		steps = n
+	case args[0] != "up" && args[0] != "down" && args[0] != "status":
+		return errMigrateUsage
+	}
+
+	db := model.InitModel(cfg)
+	if db == nil {
+		return errors.New("cannot connect to database")
+	}
+	migrator, err := migration.New(db)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, item := range applied {
+			fmt.Println("applied", item)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx, steps)
+		for _, item := range reverted {
+			fmt.Println("reverted", item)
+		}
+		return err
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, item := range status {
+			appliedAt := "pending"
+			if item.AppliedAt != nil {
+				appliedAt = item.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", item.Version, item.Name, appliedAt)
+		}
+		return w.Flush()
+	}
+	return errMigrateUsage
+}
+
+// checkSchema menolak start selama masih ada migrasi yang tertinggal,
+// kecuali autoMigrate diaktifkan
+func checkSchema(db *gorm.DB, autoMigrate bool) error {
+	if db == nil {
+		return errors.New("Tidak Dapat Terkoneksi Database")
+	}
+	migrator, err := migration.New(db)
+	if err != nil {
+		return err
+	}
+	if autoMigrate {
+		_, err := migrator.Up(context.Background())
+		return err
+	}
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		return fmt.Errorf("Skema Database Tertinggal %d Migrasi, Jalankan mytodo migrate up Atau Start Dengan -auto-migrate", pending)
+	}
+	return nil
+}
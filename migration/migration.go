@@ -0,0 +1,352 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// file migrasi per dialect database, misal sql/mysql/0001_init.up.sql
+//
+//go:embed sql
+var files embed.FS
+
+// nama tabel pencatat migrasi dan nama lock yang dipakai bersama semua instance
+const (
+	tableName   = "schema_migrations"
+	lockName    = "mytodo_schema_migrations"
+	lockKey     = 7261637300
+	lockTimeout = 60 * time.Second
+)
+
+var ErrLocked = errors.New("migration: another instance is migrating")
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration adalah satu versi skema beserta SQL untuk naik dan turun
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status adalah keadaan satu migrasi, AppliedAt kosong berarti belum dijalankan
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+type schemaMigration struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New memuat migrasi yang di-embed untuk dialect database db
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Load(files, path.Join("sql", db.Dialector.Name()))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load membaca pasangan file NNNN_nama.up.sql dan NNNN_nama.down.sql di dir,
+// diurutkan berdasarkan versi
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("migration: read %s: %w", dir, err)
+	}
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("migration: read %s: %w", entry.Name(), err)
+		}
+		migration, found := byVersion[version]
+		if !found {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration: version %d used by %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+	res := []Migration{}
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration: %04d_%s has no up file", migration.Version, migration.Name)
+		}
+		res = append(res, *migration)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+	return res, nil
+}
+
+// Up menjalankan semua migrasi yang belum dijalankan secara berurutan
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	res := []Migration{}
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, found := applied[migration.Version]; found {
+				continue
+			}
+			logrus.Info("Migration: Menjalankan ", migration)
+			if err := run(conn, migration.Up, func(tx *gorm.DB) error {
+				return tx.Table(tableName).Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+			}); err != nil {
+				return fmt.Errorf("migration: up %s: %w", migration, err)
+			}
+			res = append(res, migration)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// Down membatalkan steps migrasi terakhir yang sudah dijalankan
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	res := []Migration{}
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		versions := []int64{}
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+		for _, version := range versions[:min(steps, len(versions))] {
+			migration, found := m.find(version)
+			if !found {
+				return fmt.Errorf("migration: version %d is applied but unknown to this binary", version)
+			}
+			logrus.Info("Migration: Membatalkan ", migration)
+			if err := run(conn, migration.Down, func(tx *gorm.DB) error {
+				return tx.Table(tableName).Where("version = ?", version).Delete(&schemaMigration{}).Error
+			}); err != nil {
+				return fmt.Errorf("migration: down %s: %w", migration, err)
+			}
+			res = append(res, migration)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// Status mengembalikan semua migrasi beserta waktu dijalankannya
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	res := []Status{}
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, found := applied[migration.Version]; found {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		res = append(res, status)
+	}
+	return res, nil
+}
+
+// Pending menghitung migrasi yang belum dijalankan, dipakai untuk menolak
+// start saat skema tertinggal
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	res := 0
+	for _, item := range status {
+		if item.AppliedAt == nil {
+			res++
+		}
+	}
+	return res, nil
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// applied membaca schema_migrations, tabel yang belum ada berarti belum ada
+// migrasi yang dijalankan
+func (m *Migrator) applied(conn *gorm.DB) (map[int64]schemaMigration, error) {
+	res := map[int64]schemaMigration{}
+	if !conn.Migrator().HasTable(tableName) {
+		return res, nil
+	}
+	rows := []schemaMigration{}
+	if err := conn.Table(tableName).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("migration: read %s: %w", tableName, err)
+	}
+	for _, row := range rows {
+		res[row.Version] = row
+	}
+	return res, nil
+}
+
+// withLock menjalankan fn di satu koneksi yang memegang advisory lock,
+// sehingga hanya satu instance yang menjalankan migrasi dalam satu waktu.
+// Dialect tanpa advisory lock (sqlite) dikunci oleh database itu sendiri.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		switch conn.Dialector.Name() {
+		case "mysql":
+			locked := sql.NullInt64{}
+			if err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, int(lockTimeout.Seconds())).Row().Scan(&locked); err != nil {
+				return fmt.Errorf("migration: lock: %w", err)
+			}
+			if !locked.Valid || locked.Int64 != 1 {
+				return ErrLocked
+			}
+			defer conn.Exec("SELECT RELEASE_LOCK(?)", lockName)
+		case "postgres":
+			if err := conn.Exec(fmt.Sprintf("SET lock_timeout = '%ds'", int(lockTimeout.Seconds()))).Error; err != nil {
+				return fmt.Errorf("migration: lock: %w", err)
+			}
+			defer conn.Exec("RESET lock_timeout")
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+				return fmt.Errorf("%w: %w", ErrLocked, err)
+			}
+			defer conn.Exec("SELECT pg_advisory_unlock(?)", lockKey)
+		}
+		if err := conn.Exec(createTable).Error; err != nil {
+			return fmt.Errorf("migration: create %s: %w", tableName, err)
+		}
+		return fn(conn)
+	})
+}
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP NOT NULL
+)`
+
+// run menjalankan setiap statement content lalu record dalam satu transaksi.
+// DDL di MySQL tetap di-commit per statement, jadi migrasi yang gagal di
+// tengah harus diperbaiki manual.
+func run(conn *gorm.DB, content string, record func(tx *gorm.DB) error) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range Statements(content) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return record(tx)
+	})
+}
+
+// Statements memecah isi file migrasi menjadi statement, setiap statement
+// diakhiri ; di akhir baris dan baris komentar -- diabaikan
+func Statements(content string) []string {
+	res := []string{}
+	current := strings.Builder{}
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			res = append(res, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		res = append(res, rest)
+	}
+	return res
+}
+
+// Dialects adalah dialect database yang punya folder migrasi, setiap
+// migrasi harus ditulis untuk semuanya dengan versi yang sama
+var Dialects = []string{"mysql", "postgres", "sqlite"}
+
+// Create membuat pasangan file migrasi kosong di root/<dialect> untuk setiap
+// dialect dengan versi berikutnya yang sama
+func Create(root, name string) ([]string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return nil, errors.New("migration: name is required")
+	}
+	version := int64(1)
+	for _, dialect := range Dialects {
+		dir := filepath.Join(root, dialect)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		migrations, err := Load(os.DirFS(dir), ".")
+		if err != nil {
+			return nil, err
+		}
+		if len(migrations) > 0 {
+			version = max(version, migrations[len(migrations)-1].Version+1)
+		}
+	}
+	res := []string{}
+	for _, dialect := range Dialects {
+		for _, direction := range []string{"up", "down"} {
+			file := filepath.Join(root, dialect, fmt.Sprintf("%04d_%s.%s.sql", version, name, direction))
+			content := fmt.Sprintf("-- %04d_%s %s (%s)\n", version, name, direction, dialect)
+			if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+				return nil, err
+			}
+			res = append(res, file)
+		}
+	}
+	return res, nil
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
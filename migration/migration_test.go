@@ -0,0 +1,217 @@
+package migration
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestLoad(t *testing.T) {
+	test := []struct {
+		name     string
+		fsys     fstest.MapFS
+		expected []string
+		hasError bool
+	}{
+		{
+			name: "Sorted by version, other files ignored",
+			fsys: fstest.MapFS{
+				"sql/0010_add_index.up.sql":   {Data: []byte("CREATE INDEX a ON b (c);")},
+				"sql/0010_add_index.down.sql": {Data: []byte("DROP INDEX a ON b;")},
+				"sql/0002_init.up.sql":        {Data: []byte("CREATE TABLE b (c INT);")},
+				"sql/README.md":               {Data: []byte("catatan")},
+			},
+			expected: []string{"0002_init", "0010_add_index"},
+		},
+		{
+			name: "Up file missing",
+			fsys: fstest.MapFS{
+				"sql/0001_init.down.sql": {Data: []byte("DROP TABLE b;")},
+			},
+			hasError: true,
+		},
+		{
+			name: "Version used twice",
+			fsys: fstest.MapFS{
+				"sql/0001_init.up.sql":  {Data: []byte("CREATE TABLE b (c INT);")},
+				"sql/0001_other.up.sql": {Data: []byte("CREATE TABLE d (e INT);")},
+			},
+			hasError: true,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			migrations, err := Load(tc.fsys, "sql")
+			if tc.hasError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, migration := range migrations {
+				names = append(names, migration.String())
+			}
+			require.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	dialects, err := files.ReadDir("sql")
+	require.NoError(t, err)
+	require.Len(t, dialects, len(Dialects))
+	expected, err := Load(files, path.Join("sql", Dialects[0]))
+	require.NoError(t, err)
+	for _, dialect := range Dialects {
+		migrations, err := Load(files, path.Join("sql", dialect))
+		require.NoError(t, err)
+		require.Len(t, migrations, len(expected), "%s: every dialect must have the same migrations", dialect)
+		for i, migration := range migrations {
+			require.Equal(t, int64(i+1), migration.Version, "%s: versions must be sequential", dialect)
+			require.Equal(t, expected[i].Name, migration.Name, "%s: every dialect must have the same migrations", dialect)
+			require.NotEmpty(t, Statements(migration.Up), "%s: %s has no statement", dialect, migration)
+			_, err := files.ReadFile(path.Join("sql", dialect, migration.String()+".down.sql"))
+			require.NoError(t, err, "%s: %s has no down file", dialect, migration)
+		}
+	}
+}
+
+func TestStatements(t *testing.T) {
+	content := `-- komentar
+CREATE TABLE a (
+    b INT -- kolom
+);
+
+UPDATE a SET b = 1;
+DELETE FROM a`
+	require.Equal(t, []string{"CREATE TABLE a (\n    b INT -- kolom\n)", "UPDATE a SET b = 1", "DELETE FROM a"}, Statements(content))
+	require.Empty(t, Statements("-- hanya komentar\n"))
+}
+
+func TestCreate(t *testing.T) {
+	root := t.TempDir()
+	files, err := Create(root, "Add Todo Priority")
+	require.NoError(t, err)
+	require.Len(t, files, 2*len(Dialects))
+	require.Equal(t, []string{filepath.Join(root, "mysql", "0001_add_todo_priority.up.sql"), filepath.Join(root, "mysql", "0001_add_todo_priority.down.sql")}, files[:2])
+	require.Equal(t, filepath.Join(root, "sqlite", "0001_add_todo_priority.down.sql"), files[len(files)-1])
+
+	// versi berikutnya mengikuti dialect dengan versi tertinggi
+	require.NoError(t, os.WriteFile(filepath.Join(root, "sqlite", "0002_only_sqlite.up.sql"), []byte("SELECT 1;"), 0o644))
+	files, err = Create(root, "rename-memo")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(root, "mysql", "0003_rename_memo.up.sql"), files[0])
+	for _, file := range files {
+		_, err = os.Stat(file)
+		require.NoError(t, err)
+	}
+
+	_, err = Create(root, " !! ")
+	require.Error(t, err)
+}
+
+// model sebelum migrasi SQL dipakai, dibuat dengan AutoMigrate seperti
+// database lama
+type baselineUsers struct {
+	gorm.Model
+	Name     string `gorm:"type:varchar(255)"`
+	Email    string `gorm:"type:varchar(255);uniqueIndex"`
+	Password string `gorm:"type:varchar(255)"`
+}
+
+type baselineCategory struct {
+	gorm.Model
+	Category string `gorm:"type:varchar(255)"`
+	Color    string `gorm:"type:varchar(255)"`
+	UserID   uint
+	User     baselineUsers
+}
+
+type baselineTodo struct {
+	gorm.Model
+	Memo       string `gorm:"type:varchar(255)"`
+	DateTime   time.Time
+	Status     string
+	CategoryID uint
+	Category   baselineCategory
+	UserID     uint
+	User       baselineUsers
+}
+
+func (baselineUsers) TableName() string    { return "users" }
+func (baselineCategory) TableName() string { return "categories" }
+func (baselineTodo) TableName() string     { return "todos" }
+
+func TestUp_FromBaselineSchema(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&baselineUsers{}, &baselineCategory{}, &baselineTodo{}))
+	user := baselineUsers{Name: "lama", Email: "lama@mail.com", Password: "rahasia"}
+	require.NoError(t, db.Create(&user).Error)
+	category := baselineCategory{Category: "Kerja", UserID: user.ID}
+	require.NoError(t, db.Create(&category).Error)
+	require.NoError(t, db.Create(&baselineTodo{Memo: "Rapat", DateTime: time.Now(), Status: "OnGoing", CategoryID: category.ID, UserID: user.ID}).Error)
+	// todo lama tanpa category tersimpan dengan category_id 0
+	require.NoError(t, db.Omit("Category").Create(&baselineTodo{Memo: "Belanja", DateTime: time.Now(), Status: "OnGoing", UserID: user.ID}).Error)
+
+	migrator, err := New(db)
+	require.NoError(t, err)
+	applied, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, len(migrator.migrations))
+	pending, err := migrator.Pending(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, pending)
+
+	require.True(t, db.Migrator().HasColumn("users", "role"))
+	for _, column := range []string{"duration", "category_source", "category_confidence", "parent_id", "rrule", "auto_complete", "require_items_done", "started_at", "finished_at"} {
+		require.True(t, db.Migrator().HasColumn("todos", column), column)
+	}
+	for _, table := range []string{"tags", "todo_tags", "todo_occurrences", "todo_items", "status_transitions", "todo_status_histories", "refresh_tokens", "revoked_tokens", "reminders", "ai_usages", "todo_ai_threads", "todo_ai_messages", "weekly_review_caches"} {
+		require.True(t, db.Migrator().HasTable(table), table)
+	}
+
+	// baris lama tetap ada dan mendapat nilai default kolom baru
+	role := ""
+	require.NoError(t, db.Raw("SELECT role FROM users WHERE id = ?", user.ID).Scan(&role).Error)
+	require.Equal(t, "user", role)
+	todo := struct {
+		Status           string
+		Duration         int
+		CategorySource   string
+		RRule            string `gorm:"column:rrule"`
+		AutoComplete     bool
+		RequireItemsDone bool
+	}{}
+	require.NoError(t, db.Raw("SELECT status, duration, category_source, rrule, auto_complete, require_items_done FROM todos WHERE memo = ?", "Rapat").Scan(&todo).Error)
+	require.Equal(t, "Todo", todo.Status)
+	require.Zero(t, todo.Duration)
+	require.Equal(t, "manual", todo.CategorySource)
+	require.Empty(t, todo.RRule)
+	require.False(t, todo.AutoComplete)
+	require.False(t, todo.RequireItemsDone)
+	uncategorized := int64(0)
+	require.NoError(t, db.Table("todos").Where("category_id IS NULL").Count(&uncategorized).Error)
+	require.Equal(t, int64(1), uncategorized)
+
+	// kembali ke skema awal tanpa menghapus data lama
+	_, err = migrator.Down(context.Background(), len(migrator.migrations)-1)
+	require.NoError(t, err)
+	require.False(t, db.Migrator().HasColumn("users", "role"))
+	require.False(t, db.Migrator().HasColumn("todos", "rrule"))
+	require.False(t, db.Migrator().HasTable("tags"))
+	count := int64(0)
+	require.NoError(t, db.Table("todos").Count(&count).Error)
+	require.Equal(t, int64(2), count)
+}
@@ -0,0 +1,46 @@
+-- Skema awal, sama persis dengan hasil AutoMigrate sebelum migrasi SQL
+-- dipakai (Users, Category dan Todo). IF NOT EXISTS membuat database lama
+-- yang dibuat AutoMigrate tercatat di versi ini tanpa perubahan, kolom dan
+-- tabel baru ditambahkan oleh migrasi berikutnya.
+
+CREATE TABLE IF NOT EXISTS `users` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `name` varchar(255),
+    `email` varchar(255),
+    `password` varchar(255),
+    PRIMARY KEY (`id`),
+    INDEX `idx_users_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_users_email` (`email`)
+);
+
+CREATE TABLE IF NOT EXISTS `categories` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `category` varchar(255),
+    `color` varchar(255),
+    `user_id` bigint unsigned,
+    PRIMARY KEY (`id`),
+    INDEX `idx_categories_deleted_at` (`deleted_at`),
+    CONSTRAINT `fk_categories_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `todos` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `memo` varchar(255),
+    `date_time` datetime(3) NULL,
+    `status` longtext,
+    `category_id` bigint unsigned,
+    `user_id` bigint unsigned,
+    PRIMARY KEY (`id`),
+    INDEX `idx_todos_deleted_at` (`deleted_at`),
+    CONSTRAINT `fk_todos_category` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`),
+    CONSTRAINT `fk_todos_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
@@ -0,0 +1,2 @@
+-- Status lama tidak disimpan sehingga backfill tidak bisa dikembalikan,
+-- status Todo tetap valid untuk versi sebelumnya.
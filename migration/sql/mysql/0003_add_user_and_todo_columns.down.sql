@@ -0,0 +1,19 @@
+-- Menghapus kolom dan index baru, isi kolom tersebut ikut hilang.
+
+ALTER TABLE `todos` DROP INDEX `idx_todo_memo_fulltext`;
+
+ALTER TABLE `todos`
+    DROP INDEX `idx_todos_parent_id`,
+    DROP COLUMN `finished_at`,
+    DROP COLUMN `started_at`,
+    DROP COLUMN `require_items_done`,
+    DROP COLUMN `auto_complete`,
+    DROP COLUMN `rrule`,
+    DROP COLUMN `parent_id`,
+    DROP COLUMN `category_confidence`,
+    DROP COLUMN `category_source`,
+    DROP COLUMN `duration`;
+
+ALTER TABLE `categories` DROP INDEX `idx_category_fulltext`;
+
+ALTER TABLE `users` DROP COLUMN `role`;
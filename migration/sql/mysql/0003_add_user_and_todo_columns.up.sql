@@ -0,0 +1,20 @@
+-- Kolom dan index baru di tabel skema awal. Baris lama mendapat role user,
+-- category manual dan tanpa aturan pengulangan.
+
+ALTER TABLE `users` ADD COLUMN `role` varchar(20) DEFAULT 'user';
+
+ALTER TABLE `categories` ADD FULLTEXT INDEX `idx_category_fulltext` (`category`);
+
+ALTER TABLE `todos`
+    ADD COLUMN `duration` bigint DEFAULT 0,
+    ADD COLUMN `category_source` varchar(20) DEFAULT 'manual',
+    ADD COLUMN `category_confidence` double DEFAULT 0,
+    ADD COLUMN `parent_id` bigint unsigned,
+    ADD COLUMN `rrule` varchar(255) DEFAULT '',
+    ADD COLUMN `auto_complete` boolean DEFAULT false,
+    ADD COLUMN `require_items_done` boolean DEFAULT false,
+    ADD COLUMN `started_at` datetime,
+    ADD COLUMN `finished_at` datetime,
+    ADD INDEX `idx_todos_parent_id` (`parent_id`);
+
+ALTER TABLE `todos` ADD FULLTEXT INDEX `idx_todo_memo_fulltext` (`memo`);
@@ -0,0 +1,206 @@
+-- Tabel fitur baru: tag, pengulangan, checklist, workflow status, token,
+-- reminder dan AI.
+
+CREATE TABLE `tags` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `name` varchar(100),
+    `color` varchar(255),
+    `user_id` bigint unsigned,
+    PRIMARY KEY (`id`),
+    INDEX `idx_tags_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_tag_user_name` (`name`,`user_id`)
+);
+
+CREATE TABLE `todo_tags` (
+    `todo_id` bigint unsigned,
+    `tag_id` bigint unsigned,
+    PRIMARY KEY (`todo_id`,`tag_id`),
+    CONSTRAINT `fk_todo_tags_todo` FOREIGN KEY (`todo_id`) REFERENCES `todos`(`id`),
+    CONSTRAINT `fk_todo_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags`(`id`)
+);
+
+CREATE TABLE `todo_occurrences` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `todo_id` bigint unsigned,
+    `date` varchar(10),
+    `skipped` boolean,
+    `memo` varchar(255),
+    `date_time` datetime,
+    `status` varchar(50),
+    PRIMARY KEY (`id`),
+    INDEX `idx_todo_occurrences_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_todo_occurrence` (`todo_id`,`date`)
+);
+
+CREATE TABLE `todo_items` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `todo_id` bigint unsigned,
+    `title` varchar(255),
+    `position` bigint,
+    `done` boolean,
+    `duration` bigint,
+    PRIMARY KEY (`id`),
+    INDEX `idx_todo_items_deleted_at` (`deleted_at`),
+    INDEX `idx_todo_items_todo_id` (`todo_id`)
+);
+
+CREATE TABLE `status_transitions` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `user_id` bigint unsigned,
+    `category_id` bigint unsigned,
+    `from_status` varchar(50),
+    `to_status` varchar(50),
+    PRIMARY KEY (`id`),
+    INDEX `idx_status_transitions_deleted_at` (`deleted_at`),
+    INDEX `idx_status_transition` (`user_id`,`category_id`)
+);
+
+CREATE TABLE `todo_status_histories` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `todo_id` bigint unsigned,
+    `user_id` bigint unsigned,
+    `occurrence_date` varchar(10),
+    `from_status` varchar(50),
+    `to_status` varchar(50),
+    `changed_at` datetime,
+    PRIMARY KEY (`id`),
+    INDEX `idx_todo_status_histories_deleted_at` (`deleted_at`),
+    INDEX `idx_todo_status_histories_todo_id` (`todo_id`)
+);
+
+CREATE TABLE `refresh_tokens` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `user_id` bigint unsigned,
+    `token_hash` varchar(64),
+    `family` varchar(64),
+    `access_jti` varchar(64),
+    `access_expires_at` datetime,
+    `expires_at` datetime,
+    `revoked_at` datetime,
+    `replaced_by` varchar(64),
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_refresh_tokens_token_hash` (`token_hash`),
+    INDEX `idx_refresh_tokens_family` (`family`),
+    INDEX `idx_refresh_tokens_access_jti` (`access_jti`),
+    INDEX `idx_refresh_tokens_deleted_at` (`deleted_at`),
+    INDEX `idx_refresh_tokens_user_id` (`user_id`)
+);
+
+CREATE TABLE `revoked_tokens` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `jti` varchar(64),
+    `user_id` bigint unsigned,
+    `expires_at` datetime,
+    PRIMARY KEY (`id`),
+    INDEX `idx_revoked_tokens_expires_at` (`expires_at`),
+    INDEX `idx_revoked_tokens_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_revoked_tokens_jti` (`jti`),
+    INDEX `idx_revoked_tokens_user_id` (`user_id`)
+);
+
+CREATE TABLE `reminders` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `todo_id` bigint unsigned,
+    `user_id` bigint unsigned,
+    `offset_minutes` bigint,
+    `remind_at` datetime,
+    `sent_at` datetime,
+    `attempts` bigint,
+    `last_error` varchar(255),
+    `lease_owner` varchar(100),
+    `lease_until` datetime,
+    PRIMARY KEY (`id`),
+    INDEX `idx_reminders_deleted_at` (`deleted_at`),
+    INDEX `idx_reminders_todo_id` (`todo_id`),
+    INDEX `idx_reminders_remind_at` (`remind_at`),
+    INDEX `idx_reminders_lease_owner` (`lease_owner`),
+    CONSTRAINT `fk_reminders_todo` FOREIGN KEY (`todo_id`) REFERENCES `todos`(`id`),
+    CONSTRAINT `fk_reminders_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `ai_usages` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `user_id` bigint unsigned,
+    `feature` varchar(50),
+    `model` varchar(100),
+    `prompt_tokens` bigint,
+    `completion_tokens` bigint,
+    `total_tokens` bigint,
+    `cost` double,
+    `latency_ms` bigint,
+    `outcome` varchar(20),
+    `error` varchar(255),
+    PRIMARY KEY (`id`),
+    INDEX `idx_ai_usages_deleted_at` (`deleted_at`),
+    INDEX `idx_ai_usages_user_id` (`user_id`)
+);
+
+CREATE TABLE `todo_ai_threads` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `user_id` bigint unsigned,
+    `title` varchar(255),
+    `summary` text,
+    `summarized_up_to` bigint unsigned,
+    PRIMARY KEY (`id`),
+    INDEX `idx_todo_ai_threads_deleted_at` (`deleted_at`),
+    INDEX `idx_todo_ai_threads_user_id` (`user_id`)
+);
+
+CREATE TABLE `todo_ai_messages` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `thread_id` bigint unsigned,
+    `role` varchar(20),
+    `content` text,
+    `suggestions` text,
+    PRIMARY KEY (`id`),
+    INDEX `idx_todo_ai_messages_deleted_at` (`deleted_at`),
+    INDEX `idx_todo_ai_messages_thread_id` (`thread_id`),
+    CONSTRAINT `fk_todo_ai_threads_messages` FOREIGN KEY (`thread_id`) REFERENCES `todo_ai_threads`(`id`)
+);
+
+CREATE TABLE `weekly_review_caches` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `user_id` bigint unsigned,
+    `week` varchar(10),
+    `fingerprint` varchar(64),
+    `narrative` text,
+    PRIMARY KEY (`id`),
+    INDEX `idx_weekly_review_caches_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_weekly_review` (`user_id`,`week`)
+);
@@ -0,0 +1,2 @@
+-- category_id NULL tidak bisa dikembalikan menjadi 0 karena foreign key,
+-- kolom tetap boleh NULL untuk versi sebelumnya.
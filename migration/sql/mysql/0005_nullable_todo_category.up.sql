@@ -0,0 +1,6 @@
+-- Todo tanpa category disimpan dengan category_id NULL, foreign key ke
+-- categories tetap dipakai. Baris lama yang berisi 0 ikut diubah.
+
+ALTER TABLE `todos` MODIFY `category_id` bigint unsigned NULL;
+
+UPDATE `todos` SET `category_id` = NULL WHERE `category_id` = 0;
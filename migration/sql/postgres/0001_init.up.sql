@@ -0,0 +1,44 @@
+-- Skema awal PostgreSQL, setara dengan migrasi mysql 0001_init. Database
+-- lama yang dibuat AutoMigrate tercatat di versi ini tanpa perubahan.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(255),
+    "email" varchar(255),
+    "password" varchar(255),
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "categories" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "category" varchar(255),
+    "color" varchar(255),
+    "user_id" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_categories_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_categories_deleted_at" ON "categories" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "todos" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "memo" varchar(255),
+    "date_time" timestamptz,
+    "status" text,
+    "category_id" bigint,
+    "user_id" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_todos_category" FOREIGN KEY ("category_id") REFERENCES "categories"("id"),
+    CONSTRAINT "fk_todos_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_todos_deleted_at" ON "todos" ("deleted_at");
@@ -0,0 +1,2 @@
+-- Status lama "OnGoing" dan status kosong menjadi status awal workflow
+UPDATE "todos" SET "status" = 'Todo' WHERE "status" = 'OnGoing' OR "status" = '' OR "status" IS NULL;
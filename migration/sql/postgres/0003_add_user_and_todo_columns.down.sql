@@ -0,0 +1,18 @@
+-- Menghapus kolom dan index baru, isi kolom tersebut ikut hilang.
+
+DROP INDEX IF EXISTS "idx_todo_memo_fulltext";
+DROP INDEX IF EXISTS "idx_todos_parent_id";
+ALTER TABLE "todos"
+    DROP COLUMN "finished_at",
+    DROP COLUMN "started_at",
+    DROP COLUMN "require_items_done",
+    DROP COLUMN "auto_complete",
+    DROP COLUMN "rrule",
+    DROP COLUMN "parent_id",
+    DROP COLUMN "category_confidence",
+    DROP COLUMN "category_source",
+    DROP COLUMN "duration";
+
+DROP INDEX IF EXISTS "idx_category_fulltext";
+
+ALTER TABLE "users" DROP COLUMN "role";
@@ -0,0 +1,19 @@
+-- Kolom dan index baru di tabel skema awal, setara dengan migrasi mysql
+-- 0003. Index FULLTEXT diganti index GIN to_tsvector.
+
+ALTER TABLE "users" ADD COLUMN "role" varchar(20) DEFAULT 'user';
+
+CREATE INDEX "idx_category_fulltext" ON "categories" USING GIN (to_tsvector('simple', "category"));
+
+ALTER TABLE "todos"
+    ADD COLUMN "duration" bigint DEFAULT 0,
+    ADD COLUMN "category_source" varchar(20) DEFAULT 'manual',
+    ADD COLUMN "category_confidence" double precision DEFAULT 0,
+    ADD COLUMN "parent_id" bigint,
+    ADD COLUMN "rrule" varchar(255) DEFAULT '',
+    ADD COLUMN "auto_complete" boolean DEFAULT false,
+    ADD COLUMN "require_items_done" boolean DEFAULT false,
+    ADD COLUMN "started_at" timestamptz,
+    ADD COLUMN "finished_at" timestamptz;
+CREATE INDEX "idx_todos_parent_id" ON "todos" ("parent_id");
+CREATE INDEX "idx_todo_memo_fulltext" ON "todos" USING GIN (to_tsvector('simple', "memo"));
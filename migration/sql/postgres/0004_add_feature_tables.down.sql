@@ -0,0 +1,15 @@
+-- Menghapus tabel fitur baru, semua data di dalamnya ikut hilang.
+
+DROP TABLE IF EXISTS "weekly_review_caches";
+DROP TABLE IF EXISTS "todo_ai_messages";
+DROP TABLE IF EXISTS "todo_ai_threads";
+DROP TABLE IF EXISTS "ai_usages";
+DROP TABLE IF EXISTS "reminders";
+DROP TABLE IF EXISTS "revoked_tokens";
+DROP TABLE IF EXISTS "refresh_tokens";
+DROP TABLE IF EXISTS "todo_status_histories";
+DROP TABLE IF EXISTS "status_transitions";
+DROP TABLE IF EXISTS "todo_items";
+DROP TABLE IF EXISTS "todo_occurrences";
+DROP TABLE IF EXISTS "todo_tags";
+DROP TABLE IF EXISTS "tags";
@@ -0,0 +1,205 @@
+-- Tabel fitur baru, setara dengan migrasi mysql 0004.
+
+CREATE TABLE "tags" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(100),
+    "color" varchar(255),
+    "user_id" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_tag_user_name" ON "tags" ("name","user_id");
+CREATE INDEX "idx_tags_deleted_at" ON "tags" ("deleted_at");
+
+CREATE TABLE "todo_tags" (
+    "todo_id" bigint,
+    "tag_id" bigint,
+    PRIMARY KEY ("todo_id","tag_id"),
+    CONSTRAINT "fk_todo_tags_todo" FOREIGN KEY ("todo_id") REFERENCES "todos"("id"),
+    CONSTRAINT "fk_todo_tags_tag" FOREIGN KEY ("tag_id") REFERENCES "tags"("id")
+);
+
+CREATE TABLE "todo_occurrences" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "todo_id" bigint,
+    "date" varchar(10),
+    "skipped" boolean,
+    "memo" varchar(255),
+    "date_time" timestamptz,
+    "status" varchar(50),
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_todo_occurrence" ON "todo_occurrences" ("todo_id","date");
+CREATE INDEX "idx_todo_occurrences_deleted_at" ON "todo_occurrences" ("deleted_at");
+
+CREATE TABLE "todo_items" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "todo_id" bigint,
+    "title" varchar(255),
+    "position" bigint,
+    "done" boolean,
+    "duration" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_todo_items_todo_id" ON "todo_items" ("todo_id");
+CREATE INDEX "idx_todo_items_deleted_at" ON "todo_items" ("deleted_at");
+
+CREATE TABLE "status_transitions" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint,
+    "category_id" bigint,
+    "from_status" varchar(50),
+    "to_status" varchar(50),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_status_transitions_deleted_at" ON "status_transitions" ("deleted_at");
+CREATE INDEX "idx_status_transition" ON "status_transitions" ("user_id","category_id");
+
+CREATE TABLE "todo_status_histories" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "todo_id" bigint,
+    "user_id" bigint,
+    "occurrence_date" varchar(10),
+    "from_status" varchar(50),
+    "to_status" varchar(50),
+    "changed_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_todo_status_histories_todo_id" ON "todo_status_histories" ("todo_id");
+CREATE INDEX "idx_todo_status_histories_deleted_at" ON "todo_status_histories" ("deleted_at");
+
+CREATE TABLE "refresh_tokens" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint,
+    "token_hash" varchar(64),
+    "family" varchar(64),
+    "access_jti" varchar(64),
+    "access_expires_at" timestamptz,
+    "expires_at" timestamptz,
+    "revoked_at" timestamptz,
+    "replaced_by" varchar(64),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_refresh_tokens_access_jti" ON "refresh_tokens" ("access_jti");
+CREATE INDEX "idx_refresh_tokens_family" ON "refresh_tokens" ("family");
+CREATE UNIQUE INDEX "idx_refresh_tokens_token_hash" ON "refresh_tokens" ("token_hash");
+CREATE INDEX "idx_refresh_tokens_user_id" ON "refresh_tokens" ("user_id");
+CREATE INDEX "idx_refresh_tokens_deleted_at" ON "refresh_tokens" ("deleted_at");
+
+CREATE TABLE "revoked_tokens" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "jti" varchar(64),
+    "user_id" bigint,
+    "expires_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_revoked_tokens_expires_at" ON "revoked_tokens" ("expires_at");
+CREATE INDEX "idx_revoked_tokens_user_id" ON "revoked_tokens" ("user_id");
+CREATE UNIQUE INDEX "idx_revoked_tokens_jti" ON "revoked_tokens" ("jti");
+CREATE INDEX "idx_revoked_tokens_deleted_at" ON "revoked_tokens" ("deleted_at");
+
+CREATE TABLE "reminders" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "todo_id" bigint,
+    "user_id" bigint,
+    "offset_minutes" bigint,
+    "remind_at" timestamptz,
+    "sent_at" timestamptz,
+    "attempts" bigint,
+    "last_error" varchar(255),
+    "lease_owner" varchar(100),
+    "lease_until" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_reminders_todo" FOREIGN KEY ("todo_id") REFERENCES "todos"("id"),
+    CONSTRAINT "fk_reminders_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_reminders_deleted_at" ON "reminders" ("deleted_at");
+CREATE INDEX "idx_reminders_lease_owner" ON "reminders" ("lease_owner");
+CREATE INDEX "idx_reminders_remind_at" ON "reminders" ("remind_at");
+CREATE INDEX "idx_reminders_todo_id" ON "reminders" ("todo_id");
+
+CREATE TABLE "ai_usages" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint,
+    "feature" varchar(50),
+    "model" varchar(100),
+    "prompt_tokens" bigint,
+    "completion_tokens" bigint,
+    "total_tokens" bigint,
+    "cost" double precision,
+    "latency_ms" bigint,
+    "outcome" varchar(20),
+    "error" varchar(255),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_ai_usages_user_id" ON "ai_usages" ("user_id");
+CREATE INDEX "idx_ai_usages_deleted_at" ON "ai_usages" ("deleted_at");
+
+CREATE TABLE "todo_ai_threads" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint,
+    "title" varchar(255),
+    "summary" text,
+    "summarized_up_to" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_todo_ai_threads_deleted_at" ON "todo_ai_threads" ("deleted_at");
+CREATE INDEX "idx_todo_ai_threads_user_id" ON "todo_ai_threads" ("user_id");
+
+CREATE TABLE "todo_ai_messages" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "thread_id" bigint,
+    "role" varchar(20),
+    "content" text,
+    "suggestions" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_todo_ai_threads_messages" FOREIGN KEY ("thread_id") REFERENCES "todo_ai_threads"("id")
+);
+CREATE INDEX "idx_todo_ai_messages_thread_id" ON "todo_ai_messages" ("thread_id");
+CREATE INDEX "idx_todo_ai_messages_deleted_at" ON "todo_ai_messages" ("deleted_at");
+
+CREATE TABLE "weekly_review_caches" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint,
+    "week" varchar(10),
+    "fingerprint" varchar(64),
+    "narrative" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_weekly_review" ON "weekly_review_caches" ("user_id","week");
+CREATE INDEX "idx_weekly_review_caches_deleted_at" ON "weekly_review_caches" ("deleted_at");
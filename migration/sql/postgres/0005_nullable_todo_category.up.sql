@@ -0,0 +1,6 @@
+-- Todo tanpa category disimpan dengan category_id NULL, setara dengan
+-- migrasi mysql 0005.
+
+ALTER TABLE "todos" ALTER COLUMN "category_id" DROP NOT NULL;
+
+UPDATE "todos" SET "category_id" = NULL WHERE "category_id" = 0;
@@ -0,0 +1,5 @@
+-- Menghapus tabel skema awal, semua data ikut hilang.
+
+DROP TABLE IF EXISTS `todos`;
+DROP TABLE IF EXISTS `categories`;
+DROP TABLE IF EXISTS `users`;
@@ -0,0 +1,41 @@
+-- Skema awal SQLite, setara dengan migrasi mysql 0001_init. Database lama
+-- yang dibuat AutoMigrate tercatat di versi ini tanpa perubahan.
+
+CREATE TABLE IF NOT EXISTS `users` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` varchar(255),
+    `email` varchar(255),
+    `password` varchar(255)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_users_email` ON `users` (`email`);
+CREATE INDEX IF NOT EXISTS `idx_users_deleted_at` ON `users` (`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `categories` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `category` varchar(255),
+    `color` varchar(255),
+    `user_id` integer,
+    CONSTRAINT `fk_categories_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_categories_deleted_at` ON `categories` (`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `todos` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `memo` varchar(255),
+    `date_time` datetime,
+    `status` text,
+    `category_id` integer,
+    `user_id` integer,
+    CONSTRAINT `fk_todos_category` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`),
+    CONSTRAINT `fk_todos_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_todos_deleted_at` ON `todos` (`deleted_at`);
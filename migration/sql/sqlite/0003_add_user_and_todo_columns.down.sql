@@ -0,0 +1,14 @@
+-- Menghapus kolom dan index baru, isi kolom tersebut ikut hilang.
+
+DROP INDEX IF EXISTS `idx_todos_parent_id`;
+ALTER TABLE `todos` DROP COLUMN `finished_at`;
+ALTER TABLE `todos` DROP COLUMN `started_at`;
+ALTER TABLE `todos` DROP COLUMN `require_items_done`;
+ALTER TABLE `todos` DROP COLUMN `auto_complete`;
+ALTER TABLE `todos` DROP COLUMN `rrule`;
+ALTER TABLE `todos` DROP COLUMN `parent_id`;
+ALTER TABLE `todos` DROP COLUMN `category_confidence`;
+ALTER TABLE `todos` DROP COLUMN `category_source`;
+ALTER TABLE `todos` DROP COLUMN `duration`;
+
+ALTER TABLE `users` DROP COLUMN `role`;
@@ -0,0 +1,16 @@
+-- Kolom dan index baru di tabel skema awal, setara dengan migrasi mysql
+-- 0003 tanpa index FULLTEXT karena pencarian todo di SQLite memakai LIKE.
+-- SQLite hanya menerima satu ADD COLUMN per ALTER TABLE.
+
+ALTER TABLE `users` ADD COLUMN `role` varchar(20) DEFAULT 'user';
+
+ALTER TABLE `todos` ADD COLUMN `duration` integer DEFAULT 0;
+ALTER TABLE `todos` ADD COLUMN `category_source` varchar(20) DEFAULT 'manual';
+ALTER TABLE `todos` ADD COLUMN `category_confidence` real DEFAULT 0;
+ALTER TABLE `todos` ADD COLUMN `parent_id` integer;
+ALTER TABLE `todos` ADD COLUMN `rrule` varchar(255) DEFAULT '';
+ALTER TABLE `todos` ADD COLUMN `auto_complete` numeric DEFAULT false;
+ALTER TABLE `todos` ADD COLUMN `require_items_done` numeric DEFAULT false;
+ALTER TABLE `todos` ADD COLUMN `started_at` datetime;
+ALTER TABLE `todos` ADD COLUMN `finished_at` datetime;
+CREATE INDEX `idx_todos_parent_id` ON `todos` (`parent_id`);
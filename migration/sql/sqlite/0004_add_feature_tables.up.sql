@@ -0,0 +1,193 @@
+-- Tabel fitur baru, setara dengan migrasi mysql 0004.
+
+CREATE TABLE `tags` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` varchar(100),
+    `color` varchar(255),
+    `user_id` integer
+);
+CREATE UNIQUE INDEX `idx_tag_user_name` ON `tags` (`name`,`user_id`);
+CREATE INDEX `idx_tags_deleted_at` ON `tags` (`deleted_at`);
+
+CREATE TABLE `todo_tags` (
+    `todo_id` integer,
+    `tag_id` integer,
+    PRIMARY KEY (`todo_id`,`tag_id`),
+    CONSTRAINT `fk_todo_tags_todo` FOREIGN KEY (`todo_id`) REFERENCES `todos`(`id`),
+    CONSTRAINT `fk_todo_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags`(`id`)
+);
+
+CREATE TABLE `todo_occurrences` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `todo_id` integer,
+    `date` varchar(10),
+    `skipped` numeric,
+    `memo` varchar(255),
+    `date_time` datetime,
+    `status` varchar(50)
+);
+CREATE INDEX `idx_todo_occurrences_deleted_at` ON `todo_occurrences` (`deleted_at`);
+CREATE UNIQUE INDEX `idx_todo_occurrence` ON `todo_occurrences` (`todo_id`,`date`);
+
+CREATE TABLE `todo_items` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `todo_id` integer,
+    `title` varchar(255),
+    `position` integer,
+    `done` numeric,
+    `duration` integer
+);
+CREATE INDEX `idx_todo_items_todo_id` ON `todo_items` (`todo_id`);
+CREATE INDEX `idx_todo_items_deleted_at` ON `todo_items` (`deleted_at`);
+
+CREATE TABLE `status_transitions` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` integer,
+    `category_id` integer,
+    `from_status` varchar(50),
+    `to_status` varchar(50)
+);
+CREATE INDEX `idx_status_transition` ON `status_transitions` (`user_id`,`category_id`);
+CREATE INDEX `idx_status_transitions_deleted_at` ON `status_transitions` (`deleted_at`);
+
+CREATE TABLE `todo_status_histories` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `todo_id` integer,
+    `user_id` integer,
+    `occurrence_date` varchar(10),
+    `from_status` varchar(50),
+    `to_status` varchar(50),
+    `changed_at` datetime
+);
+CREATE INDEX `idx_todo_status_histories_todo_id` ON `todo_status_histories` (`todo_id`);
+CREATE INDEX `idx_todo_status_histories_deleted_at` ON `todo_status_histories` (`deleted_at`);
+
+CREATE TABLE `refresh_tokens` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` integer,
+    `token_hash` varchar(64),
+    `family` varchar(64),
+    `access_jti` varchar(64),
+    `access_expires_at` datetime,
+    `expires_at` datetime,
+    `revoked_at` datetime,
+    `replaced_by` varchar(64)
+);
+CREATE INDEX `idx_refresh_tokens_access_jti` ON `refresh_tokens` (`access_jti`);
+CREATE INDEX `idx_refresh_tokens_family` ON `refresh_tokens` (`family`);
+CREATE UNIQUE INDEX `idx_refresh_tokens_token_hash` ON `refresh_tokens` (`token_hash`);
+CREATE INDEX `idx_refresh_tokens_user_id` ON `refresh_tokens` (`user_id`);
+CREATE INDEX `idx_refresh_tokens_deleted_at` ON `refresh_tokens` (`deleted_at`);
+
+CREATE TABLE `revoked_tokens` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `jti` varchar(64),
+    `user_id` integer,
+    `expires_at` datetime
+);
+CREATE INDEX `idx_revoked_tokens_expires_at` ON `revoked_tokens` (`expires_at`);
+CREATE INDEX `idx_revoked_tokens_user_id` ON `revoked_tokens` (`user_id`);
+CREATE UNIQUE INDEX `idx_revoked_tokens_jti` ON `revoked_tokens` (`jti`);
+CREATE INDEX `idx_revoked_tokens_deleted_at` ON `revoked_tokens` (`deleted_at`);
+
+CREATE TABLE `reminders` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `todo_id` integer,
+    `user_id` integer,
+    `offset_minutes` integer,
+    `remind_at` datetime,
+    `sent_at` datetime,
+    `attempts` integer,
+    `last_error` varchar(255),
+    `lease_owner` varchar(100),
+    `lease_until` datetime,
+    CONSTRAINT `fk_reminders_todo` FOREIGN KEY (`todo_id`) REFERENCES `todos`(`id`),
+    CONSTRAINT `fk_reminders_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_reminders_lease_owner` ON `reminders` (`lease_owner`);
+CREATE INDEX `idx_reminders_remind_at` ON `reminders` (`remind_at`);
+CREATE INDEX `idx_reminders_todo_id` ON `reminders` (`todo_id`);
+CREATE INDEX `idx_reminders_deleted_at` ON `reminders` (`deleted_at`);
+
+CREATE TABLE `ai_usages` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` integer,
+    `feature` varchar(50),
+    `model` varchar(100),
+    `prompt_tokens` integer,
+    `completion_tokens` integer,
+    `total_tokens` integer,
+    `cost` real,
+    `latency_ms` integer,
+    `outcome` varchar(20),
+    `error` varchar(255)
+);
+CREATE INDEX `idx_ai_usages_user_id` ON `ai_usages` (`user_id`);
+CREATE INDEX `idx_ai_usages_deleted_at` ON `ai_usages` (`deleted_at`);
+
+CREATE TABLE `todo_ai_threads` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` integer,
+    `title` varchar(255),
+    `summary` text,
+    `summarized_up_to` integer
+);
+CREATE INDEX `idx_todo_ai_threads_user_id` ON `todo_ai_threads` (`user_id`);
+CREATE INDEX `idx_todo_ai_threads_deleted_at` ON `todo_ai_threads` (`deleted_at`);
+
+CREATE TABLE `todo_ai_messages` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `thread_id` integer,
+    `role` varchar(20),
+    `content` text,
+    `suggestions` text,
+    CONSTRAINT `fk_todo_ai_threads_messages` FOREIGN KEY (`thread_id`) REFERENCES `todo_ai_threads`(`id`)
+);
+CREATE INDEX `idx_todo_ai_messages_thread_id` ON `todo_ai_messages` (`thread_id`);
+CREATE INDEX `idx_todo_ai_messages_deleted_at` ON `todo_ai_messages` (`deleted_at`);
+
+CREATE TABLE `weekly_review_caches` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` integer,
+    `week` varchar(10),
+    `fingerprint` varchar(64),
+    `narrative` text
+);
+CREATE INDEX `idx_weekly_review_caches_deleted_at` ON `weekly_review_caches` (`deleted_at`);
+CREATE UNIQUE INDEX `idx_weekly_review` ON `weekly_review_caches` (`user_id`,`week`);
@@ -0,0 +1,5 @@
+-- Todo tanpa category disimpan dengan category_id NULL, setara dengan
+-- migrasi mysql 0005. Kolom category_id di SQLite sudah boleh NULL sehingga
+-- hanya baris lama yang berisi 0 yang diubah.
+
+UPDATE `todos` SET `category_id` = NULL WHERE `category_id` = 0;
@@ -0,0 +1,228 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mytodo/ai"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type AIUsageInterface interface {
+	CheckQuota(userID uint) error
+	GetUsageReport(userID uint) *UsageReport
+	GetUsageAggregate(from, to time.Time) []UsageAggregate
+}
+
+// AIUsage mencatat setiap permintaan ke provider AI, termasuk permintaan
+// ulang saat jawaban tidak valid
+type AIUsage struct {
+	gorm.Model
+	UserID           uint    `json:"user_id" gorm:"index"`
+	Feature          string  `json:"feature" gorm:"type:varchar(50)"`
+	ModelName        string  `json:"model" gorm:"column:model;type:varchar(100)"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	Cost             float64 `json:"cost"`
+	LatencyMs        int64   `json:"latency_ms"`
+	Outcome          string  `json:"outcome" gorm:"type:varchar(20)"`
+	Error            string  `json:"error,omitempty" gorm:"type:varchar(255)"`
+}
+
+// hasil permintaan ke provider AI
+const (
+	OutcomeSuccess   = "success"
+	OutcomeInvalid   = "invalid"
+	OutcomeError     = "error"
+	OutcomeCancelled = "cancelled"
+	OutcomeCached    = "cached"
+)
+
+// AIPricing adalah harga per 1000 token untuk menghitung perkiraan biaya
+type AIPricing struct {
+	PromptPer1K     float64
+	CompletionPer1K float64
+}
+
+func (p AIPricing) Cost(usage ai.Usage) float64 {
+	return (float64(usage.PromptTokens)*p.PromptPer1K + float64(usage.CompletionTokens)*p.CompletionPer1K) / 1000
+}
+
+// QuotaExceededError dikembalikan saat user sudah melewati kuota token
+type QuotaExceededError struct {
+	Period  string    `json:"period"`
+	Limit   int64     `json:"limit"`
+	Used    int64     `json:"used"`
+	ResetAt time.Time `json:"reset_at"`
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s ai quota exceeded: used %d of %d tokens, resets at %s", e.Period, e.Used, e.Limit, e.ResetAt.Format(time.RFC3339))
+}
+
+// UsagePeriod adalah pemakaian satu periode kuota, Limit 0 berarti tanpa batas
+type UsagePeriod struct {
+	Start            time.Time `json:"start"`
+	ResetAt          time.Time `json:"reset_at"`
+	Calls            int64     `json:"calls"`
+	PromptTokens     int64     `json:"prompt_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+	TotalTokens      int64     `json:"total_tokens"`
+	Cost             float64   `json:"cost"`
+	Limit            int64     `json:"limit"`
+	Remaining        int64     `json:"remaining"`
+}
+
+type UsageReport struct {
+	Daily   UsagePeriod `json:"daily"`
+	Monthly UsagePeriod `json:"monthly"`
+}
+
+// UsageAggregate adalah total pemakaian AI per user untuk admin
+type UsageAggregate struct {
+	UserID           uint    `json:"user_id"`
+	Name             string  `json:"name"`
+	Email            string  `json:"email"`
+	Calls            int64   `json:"calls"`
+	Errors           int64   `json:"errors"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	TotalTokens      int64   `json:"total_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+type AIUsageModel struct {
+	db           *gorm.DB
+	dailyQuota   int64
+	monthlyQuota int64
+}
+
+func (um *AIUsageModel) InitAIUsage(db *gorm.DB) {
+	um.db = db
+}
+
+func NewAIUsageModel(db *gorm.DB, dailyQuota, monthlyQuota int64) AIUsageInterface {
+	return &AIUsageModel{
+		db:           db,
+		dailyQuota:   dailyQuota,
+		monthlyQuota: monthlyQuota,
+	}
+}
+
+// CheckQuota mengembalikan *QuotaExceededError jika kuota harian atau bulanan
+// user sudah habis. Kuota bulanan didahulukan karena reset-nya lebih lama.
+func (um *AIUsageModel) CheckQuota(userID uint) error {
+	report := um.GetUsageReport(userID)
+	if report == nil {
+		return errors.New("failed to read ai usage")
+	}
+	if report.Monthly.exceeded() {
+		return report.Monthly.quotaError("monthly")
+	}
+	if report.Daily.exceeded() {
+		return report.Daily.quotaError("daily")
+	}
+	return nil
+}
+
+func (p UsagePeriod) exceeded() bool {
+	return p.Limit > 0 && p.TotalTokens >= p.Limit
+}
+
+func (p UsagePeriod) quotaError(period string) *QuotaExceededError {
+	return &QuotaExceededError{
+		Period:  period,
+		Limit:   p.Limit,
+		Used:    p.TotalTokens,
+		ResetAt: p.ResetAt,
+	}
+}
+
+func (um *AIUsageModel) GetUsageReport(userID uint) *UsageReport {
+	now := time.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	daily, err := um.usageSince(userID, day, day.AddDate(0, 0, 1), um.dailyQuota)
+	if err != nil {
+		logrus.Error("Model: Error Menghitung Pemakaian AI ", err.Error())
+		return nil
+	}
+	monthly, err := um.usageSince(userID, month, month.AddDate(0, 1, 0), um.monthlyQuota)
+	if err != nil {
+		logrus.Error("Model: Error Menghitung Pemakaian AI ", err.Error())
+		return nil
+	}
+	return &UsageReport{Daily: daily, Monthly: monthly}
+}
+
+func (um *AIUsageModel) usageSince(userID uint, start, reset time.Time, limit int64) (UsagePeriod, error) {
+	res := UsagePeriod{}
+	err := um.db.Model(&AIUsage{}).
+		Select("COUNT(*) AS calls, COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens, COALESCE(SUM(completion_tokens), 0) AS completion_tokens, COALESCE(SUM(total_tokens), 0) AS total_tokens, COALESCE(SUM(cost), 0) AS cost").
+		Where("user_id = ? AND created_at >= ?", userID, start).
+		Scan(&res).Error
+	if err != nil {
+		return res, err
+	}
+	res.Start = start
+	res.ResetAt = reset
+	res.Limit = limit
+	if limit > 0 {
+		res.Remaining = max(limit-res.TotalTokens, 0)
+	}
+	return res, nil
+}
+
+func (um *AIUsageModel) GetUsageAggregate(from, to time.Time) []UsageAggregate {
+	res := []UsageAggregate{}
+	err := um.db.Model(&AIUsage{}).
+		Select("ai_usages.user_id, users.name, users.email, COUNT(*) AS calls, "+
+			"SUM(CASE WHEN ai_usages.outcome IN ? THEN 0 ELSE 1 END) AS errors, "+
+			"COALESCE(SUM(ai_usages.prompt_tokens), 0) AS prompt_tokens, COALESCE(SUM(ai_usages.completion_tokens), 0) AS completion_tokens, "+
+			"COALESCE(SUM(ai_usages.total_tokens), 0) AS total_tokens, COALESCE(SUM(ai_usages.cost), 0) AS cost", []string{OutcomeSuccess, OutcomeCached}).
+		Joins("LEFT JOIN users ON users.id = ai_usages.user_id").
+		Where("ai_usages.created_at >= ? AND ai_usages.created_at < ?", from, to).
+		Group("ai_usages.user_id, users.name, users.email").
+		Order("total_tokens DESC, ai_usages.user_id").
+		Scan(&res).Error
+	if err != nil {
+		logrus.Error("Model: Error Mendapatkan Rekap Pemakaian AI ", err.Error())
+		return nil
+	}
+	return res
+}
+
+// recordAIUsage menyimpan satu permintaan ke provider, kegagalan hanya dicatat di log
+func recordAIUsage(db *gorm.DB, pricing AIPricing, userID uint, feature string, res ai.Response, latency time.Duration, err error, parseErr error) {
+	usage := AIUsage{
+		UserID:           userID,
+		Feature:          feature,
+		ModelName:        res.Model,
+		PromptTokens:     res.Usage.PromptTokens,
+		CompletionTokens: res.Usage.CompletionTokens,
+		TotalTokens:      res.Usage.TotalTokens,
+		Cost:             pricing.Cost(res.Usage),
+		LatencyMs:        latency.Milliseconds(),
+		Outcome:          OutcomeSuccess,
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		usage.Outcome = OutcomeCancelled
+	case err != nil:
+		usage.Outcome = OutcomeError
+		usage.Error = err.Error()
+	case parseErr != nil:
+		usage.Outcome = OutcomeInvalid
+		usage.Error = parseErr.Error()
+	case res.Cached:
+		usage.Outcome = OutcomeCached
+	}
+	usage.Error = truncateRunes(usage.Error, 255)
+	if err := db.Create(&usage).Error; err != nil {
+		logrus.Error("Model: Error Mencatat Pemakaian AI ", err.Error())
+	}
+}
@@ -6,23 +6,23 @@ import (
 )
 
 type CategoryInterface interface {
-	AddCategory(newCategory Category) bool
-	GetCategories(page int, perpage int, id uint) []Category
-	GetCategory(id int, idUser uint) *Category
-	UpdateCategory(category Category, id int, idUser uint) bool
-	DeleteCategory(id int, idUser uint) bool
+	AddCategory(newCategory Category) error
+	GetCategories(page int, perpage int, id uint) ([]Category, int64, error)
+	GetCategory(id int, idUser uint) (*Category, error)
+	UpdateCategory(category Category, id int, idUser uint) error
+	DeleteCategory(id int, idUser uint) error
 }
 
 type Category struct {
 	// ID        uint      `json:"id" form:"id" gorm:"type:primaryKey;autoIncrement:true"`
 	gorm.Model
-	Category string `json:"category" form:"category" gorm:"type:varchar(255)"`
-	Color    string `json:"color" form:"color" gorm:"type:varchar(255)"`
+	Category string `json:"category" form:"category" gorm:"type:varchar(255);index:idx_category_fulltext,class:FULLTEXT" validate:"required,max=255"`
+	Color    string `json:"color" form:"color" gorm:"type:varchar(255)" validate:"omitempty,hexcolor"`
 	// CreatedAt time.Time `json:"created_at" form:"created_at" gorm:"type:datetime"`
 	// UpdatedAt time.Time `json:"updated_at" form:"updated_at" gorm:"type:datetime"`
 	// DeletedAt time.Time `json:"deleted_at" form:"deleted_at" gorm:"type:datetime"`
 	UserID uint  `json:"user_id" form:"user_id"`
-	User   Users `json:"user" form:"user"`
+	User   Users `json:"user" form:"user" validate:"-"`
 }
 
 type CategoryModel struct {
@@ -39,69 +39,106 @@ func NewCategoryModel(db *gorm.DB) CategoryInterface {
 	}
 }
 
-func (cm *CategoryModel) AddCategory(newCategory Category) bool {
+func (cm *CategoryModel) AddCategory(newCategory Category) error {
 	if err := cm.db.Create(&newCategory).Error; err != nil {
 		logrus.Error("Model: Error Saat Input Category ")
-		return false
+		return dbError(err, "category")
 	}
-	return true
+	return nil
 }
 
-func (cm *CategoryModel) GetCategories(page, perpage int, id uint) []Category {
+func (cm *CategoryModel) GetCategories(page, perpage int, id uint) ([]Category, int64, error) {
 	categories := []Category{}
+	var total int64
 	offset := (page - 1) * perpage
-	if err := cm.db.Limit(perpage).Offset(offset).Where("user_id = ?", id).Find(&categories).Error; err != nil {
+	if err := cm.db.Model(&Category{}).Where("user_id = ?", id).Count(&total).Error; err != nil {
+		logrus.Error("Model: Error Menghitung Data Category ", err.Error())
+		return nil, 0, dbError(err, "count categories")
+	}
+	if err := cm.db.Limit(perpage).Offset(offset).Where("user_id = ?", id).Order("id").Find(&categories).Error; err != nil {
 		logrus.Error("Model: Error Mendapatkan Data Category ", err.Error())
-		return nil
+		return nil, 0, dbError(err, "list categories")
 	}
 	for i := 0; i < len(categories); i++ {
 		user := Users{}
 		if err := cm.db.First(&user, categories[i].UserID).Error; err != nil {
 			logrus.Error("Model: Error Mendapatkan User Data Category ", err.Error())
-			return nil
+			return nil, 0, dbError(err, "user %d", categories[i].UserID)
 		}
 		categories[i].User = user
 	}
-	return categories
+	return categories, total, nil
 }
-func (cm *CategoryModel) GetCategory(id int, idUser uint) *Category {
+func (cm *CategoryModel) GetCategory(id int, idUser uint) (*Category, error) {
 	category := Category{}
 	if err := cm.db.Where("user_id = ?", idUser).First(&category, id).Error; err != nil {
 		logrus.Error("Model: Data Category Tidak Ditemukan ", err.Error())
-		return nil
+		return nil, dbError(err, "category %d", id)
 	}
 	user := Users{}
 	if err := cm.db.First(&user, idUser).Error; err != nil {
 		logrus.Error("Model: Data User Category Tidak Ditemukan ", err.Error())
-		return nil
+		return nil, dbError(err, "user %d", idUser)
 	}
 	category.User = user
-	return &category
+	return &category, nil
 }
-func (cm *CategoryModel) UpdateCategory(categoryUp Category, id int, idUser uint) bool {
-	data := cm.GetCategory(id, idUser)
-	if data == nil {
+func (cm *CategoryModel) UpdateCategory(categoryUp Category, id int, idUser uint) error {
+	data, err := cm.GetCategory(id, idUser)
+	if err != nil {
 		logrus.Error("Model: Error Update Data Category")
-		return false
+		return err
 	}
 	data.Category = categoryUp.Category
 	data.Color = categoryUp.Color
 	if err := cm.db.Save(&data).Error; err != nil {
 		logrus.Error("Model: Error Update Data Category ", err.Error())
-		return false
+		return dbError(err, "update category %d", id)
 	}
-	return true
+	return nil
 }
-func (cm *CategoryModel) DeleteCategory(id int, idUser uint) bool {
+func (cm *CategoryModel) DeleteCategory(id int, idUser uint) error {
 	category := Category{}
-	data := cm.GetCategory(id, idUser)
-	if data == nil {
+	if _, err := cm.GetCategory(id, idUser); err != nil {
 		logrus.Error("Model: Error Delete Category")
-		return false
+		return err
 	}
 	if err := cm.db.Where("user_id = ?", idUser).Delete(&category, id).Error; err != nil {
 		logrus.Error("Model: Error Delete Category", err.Error())
-		return false
+		return dbError(err, "delete category %d", id)
+	}
+	return nil
+}
+
+// checkCategory memastikan category yang dipilih untuk todo ada dan milik
+// user, categoryID nil berarti todo tanpa category
+func checkCategory(db *gorm.DB, userID uint, categoryID *uint) error {
+	if categoryID == nil {
+		return nil
+	}
+	category := Category{}
+	if err := db.Select("id", "user_id").First(&category, *categoryID).Error; err != nil {
+		return dbError(err, "category %d", *categoryID)
+	}
+	if category.UserID != userID {
+		return newError(ErrForbidden, "category %d belongs to another user", *categoryID)
+	}
+	return nil
+}
+
+// todoCategoryID menyamakan category_id 0 dengan tanpa category, todo tanpa
+// category disimpan NULL agar foreign key ke categories tetap terpenuhi
+func todoCategoryID(categoryID *uint) *uint {
+	if categoryID == nil || *categoryID == 0 {
+		return nil
+	}
+	return categoryID
+}
+
+// categoryValue mengembalikan 0 untuk todo tanpa category
+func categoryValue(categoryID *uint) uint {
+	if categoryID == nil {
+		return 0
 	}
-	return true
+	return *categoryID
 }
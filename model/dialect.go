@@ -0,0 +1,121 @@
+package model
+
+import (
+	"fmt"
+	"mytodo/helper"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// nama driver database yang didukung, sama dengan nama gorm.Dialector
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Dialect menyembunyikan SQL yang berbeda antar database
+type Dialect interface {
+	Name() string
+	// Contains mencocokkan column yang mengandung text tanpa membedakan huruf besar kecil
+	Contains(column, text string) SQLExpr
+	// Search mencari kata yang diawali salah satu terms di column
+	Search(column string, terms []string) SearchExpr
+}
+
+// SQLExpr adalah potongan SQL beserta argumennya
+type SQLExpr struct {
+	SQL  string
+	Args []any
+}
+
+// SearchExpr berisi ekspresi skor relevansi (NULL jika column NULL) dan
+// kondisi cocok, keduanya memakai Args yang sama
+type SearchExpr struct {
+	Score string
+	Match string
+	Args  []any
+}
+
+// DialectOf memilih Dialect berdasarkan driver koneksi db, driver yang tidak
+// dikenal memakai MySQL
+func DialectOf(db *gorm.DB) Dialect {
+	switch db.Dialector.Name() {
+	case DriverPostgres:
+		return postgresDialect{}
+	case DriverSQLite:
+		return sqliteDialect{}
+	}
+	return mysqlDialect{}
+}
+
+// MySQL/MariaDB memakai index FULLTEXT dan collation yang case insensitive
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string { return DriverMySQL }
+
+func (mysqlDialect) Contains(column, text string) SQLExpr {
+	return SQLExpr{SQL: column + " LIKE ? ESCAPE '!'", Args: []any{"%" + escapeLike(text) + "%"}}
+}
+
+func (mysqlDialect) Search(column string, terms []string) SearchExpr {
+	match := fmt.Sprintf("MATCH(%s) AGAINST (? IN BOOLEAN MODE)", column)
+	return SearchExpr{Score: match, Match: match, Args: []any{helper.BooleanQuery(terms)}}
+}
+
+// PostgreSQL memakai tsvector dengan konfigurasi simple agar kata tidak di-stem
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return DriverPostgres }
+
+func (postgresDialect) Contains(column, text string) SQLExpr {
+	return SQLExpr{SQL: column + " ILIKE ? ESCAPE '!'", Args: []any{"%" + escapeLike(text) + "%"}}
+}
+
+func (postgresDialect) Search(column string, terms []string) SearchExpr {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, term+":*")
+	}
+	vector := fmt.Sprintf("to_tsvector('simple', %s)", column)
+	return SearchExpr{
+		Score: fmt.Sprintf("ts_rank(%s, to_tsquery('simple', ?))", vector),
+		Match: fmt.Sprintf("%s @@ to_tsquery('simple', ?)", vector),
+		Args:  []any{strings.Join(parts, " | ")},
+	}
+}
+
+// SQLite tidak punya index full text tanpa tabel virtual, sehingga setiap
+// term dicocokkan dengan LIKE di awal kata dan skornya jumlah term yang cocok
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return DriverSQLite }
+
+func (sqliteDialect) Contains(column, text string) SQLExpr {
+	// LIKE di SQLite sudah case insensitive untuk huruf ASCII
+	return SQLExpr{SQL: column + " LIKE ? ESCAPE '!'", Args: []any{"%" + escapeLike(text) + "%"}}
+}
+
+func (sqliteDialect) Search(column string, terms []string) SearchExpr {
+	parts := make([]string, 0, len(terms))
+	args := make([]any, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, fmt.Sprintf("(' ' || LOWER(%s) LIKE ? ESCAPE '!')", column))
+		args = append(args, "% "+escapeLike(term)+"%")
+	}
+	score := "(" + strings.Join(parts, " + ") + ")"
+	return SearchExpr{Score: score, Match: score + " > 0", Args: args}
+}
+
+// upsert menyisipkan baris atau memperbarui kolom updates jika baris dengan
+// nilai columns yang sama sudah ada. MySQL tidak memakai columns dan
+// memperbarui baris yang bentrok di unique index mana pun.
+func upsert(columns []string, updates ...string) clause.OnConflict {
+	conflict := make([]clause.Column, 0, len(columns))
+	for _, column := range columns {
+		conflict = append(conflict, clause.Column{Name: column})
+	}
+	return clause.OnConflict{Columns: conflict, DoUpdates: clause.AssignmentColumns(updates)}
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRun membuka db tanpa koneksi ke server, hanya untuk menyusun SQL
+func dryRun(t *testing.T, dialector gorm.Dialector) *gorm.DB {
+	db, err := gorm.Open(dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	return db
+}
+
+func TestDialect(t *testing.T) {
+	test := []struct {
+		name      string
+		dialector gorm.Dialector
+		contains  string
+		score     string
+		match     string
+		args      []any
+		upsert    string
+	}{
+		{
+			name:      "MySQL",
+			dialector: mysql.New(mysql.Config{DSN: "u:p@tcp(127.0.0.1:1)/mytodo", SkipInitializeWithVersion: true}),
+			contains:  "todos.memo LIKE ? ESCAPE '!'",
+			score:     "MATCH(todos.memo) AGAINST (? IN BOOLEAN MODE)",
+			match:     "MATCH(todos.memo) AGAINST (? IN BOOLEAN MODE)",
+			args:      []any{"rapat* client*"},
+			upsert:    "ON DUPLICATE KEY UPDATE `fingerprint`=VALUES(`fingerprint`),`narrative`=VALUES(`narrative`),`updated_at`=VALUES(`updated_at`)",
+		},
+		{
+			name:      "PostgreSQL",
+			dialector: postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}),
+			contains:  "todos.memo ILIKE ? ESCAPE '!'",
+			score:     "ts_rank(to_tsvector('simple', todos.memo), to_tsquery('simple', ?))",
+			match:     "to_tsvector('simple', todos.memo) @@ to_tsquery('simple', ?)",
+			args:      []any{"rapat:* | client:*"},
+			upsert:    `ON CONFLICT ("user_id","week") DO UPDATE SET "fingerprint"="excluded"."fingerprint","narrative"="excluded"."narrative","updated_at"="excluded"."updated_at" RETURNING "id"`,
+		},
+		{
+			name:      "SQLite",
+			dialector: sqlite.Open(":memory:"),
+			contains:  "todos.memo LIKE ? ESCAPE '!'",
+			score:     "((' ' || LOWER(todos.memo) LIKE ? ESCAPE '!') + (' ' || LOWER(todos.memo) LIKE ? ESCAPE '!'))",
+			match:     "((' ' || LOWER(todos.memo) LIKE ? ESCAPE '!') + (' ' || LOWER(todos.memo) LIKE ? ESCAPE '!')) > 0",
+			args:      []any{"% rapat%", "% client%"},
+			upsert:    "ON CONFLICT (`user_id`,`week`) DO UPDATE SET `fingerprint`=`excluded`.`fingerprint`,`narrative`=`excluded`.`narrative`,`updated_at`=`excluded`.`updated_at` RETURNING `id`",
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			db := dryRun(t, tc.dialector)
+			dialect := DialectOf(db)
+			require.Equal(t, db.Dialector.Name(), dialect.Name())
+
+			contains := dialect.Contains("todos.memo", "50%_off")
+			require.Equal(t, tc.contains, contains.SQL)
+			require.Equal(t, []any{"%50!%!_off%"}, contains.Args)
+
+			search := dialect.Search("todos.memo", []string{"rapat", "client"})
+			require.Equal(t, tc.score, search.Score)
+			require.Equal(t, tc.match, search.Match)
+			require.Equal(t, tc.args, search.Args)
+
+			sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tx.Clauses(upsert([]string{"user_id", "week"}, "fingerprint", "narrative", "updated_at")).Create(&WeeklyReviewCache{UserID: 1, Week: "2024-W01"})
+			})
+			require.Contains(t, sql, tc.upsert)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// jenis error domain, controller memetakan jenis ini ke status HTTP
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+	ErrForbidden  = errors.New("forbidden")
+)
+
+// domainError menandai err dengan jenis error domain tanpa mengubah pesannya,
+// sehingga errors.Is berlaku untuk jenis, err maupun cause dari gorm
+type domainError struct {
+	kind  error
+	err   error
+	cause error
+}
+
+func (e domainError) Error() string { return e.err.Error() }
+func (e domainError) Unwrap() []error {
+	if e.cause == nil {
+		return []error{e.kind, e.err}
+	}
+	return []error{e.kind, e.err, e.cause}
+}
+
+// newError membuat error berjenis kind dengan pesan format
+func newError(kind error, format string, args ...any) error {
+	return domainError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+// dbError menerjemahkan error gorm menjadi error domain, error lain (misal
+// database mati) dikembalikan apa adanya
+func dbError(err error, format string, args ...any) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domainError{kind: ErrNotFound, err: fmt.Errorf(format+" not found", args...), cause: err}
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return domainError{kind: ErrConflict, err: fmt.Errorf(format+" already exists", args...), cause: err}
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return domainError{kind: ErrValidation, err: fmt.Errorf(format+" references missing data", args...), cause: err}
+	}
+	return fmt.Errorf(format+": %w", append(args, err)...)
+}
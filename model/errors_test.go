@@ -0,0 +1,40 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestDBError(t *testing.T) {
+	down := errors.New("connection refused")
+	test := []struct {
+		name    string
+		err     error
+		kind    error
+		message string
+	}{
+		{name: "Record not found", err: gorm.ErrRecordNotFound, kind: ErrNotFound, message: "todo 7 not found"},
+		{name: "Duplicated key", err: gorm.ErrDuplicatedKey, kind: ErrConflict, message: "todo 7 already exists"},
+		{name: "Foreign key violated", err: gorm.ErrForeignKeyViolated, kind: ErrValidation, message: "todo 7 references missing data"},
+		{name: "Database down", err: down, kind: down, message: "todo 7: connection refused"},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			err := dbError(tc.err, "todo %d", 7)
+			require.ErrorIs(t, err, tc.kind)
+			require.ErrorIs(t, err, tc.err)
+			require.EqualError(t, err, tc.message)
+			for _, kind := range []error{ErrNotFound, ErrConflict, ErrValidation, ErrForbidden} {
+				if kind != tc.kind {
+					require.NotErrorIs(t, err, kind)
+				}
+			}
+		})
+	}
+	require.NoError(t, dbError(nil, "todo"))
+	require.ErrorIs(t, ErrIllegalTransition, ErrConflict)
+	require.ErrorIs(t, ErrOpenItems, ErrConflict)
+}
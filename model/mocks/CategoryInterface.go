@@ -14,38 +14,43 @@ type CategoryInterface struct {
 }
 
 // AddCategory provides a mock function with given fields: newCategory
-func (_m *CategoryInterface) AddCategory(newCategory model.Category) bool {
+func (_m *CategoryInterface) AddCategory(newCategory model.Category) error {
 	ret := _m.Called(newCategory)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(model.Category) bool); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(model.Category) error); ok {
 		r0 = rf(newCategory)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
 // DeleteCategory provides a mock function with given fields: id, idUser
-func (_m *CategoryInterface) DeleteCategory(id int, idUser uint) bool {
+func (_m *CategoryInterface) DeleteCategory(id int, idUser uint) error {
 	ret := _m.Called(id, idUser)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(int, uint) bool); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, uint) error); ok {
 		r0 = rf(id, idUser)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
 // GetCategories provides a mock function with given fields: page, perpage, id
-func (_m *CategoryInterface) GetCategories(page int, perpage int, id uint) []model.Category {
+func (_m *CategoryInterface) GetCategories(page int, perpage int, id uint) ([]model.Category, int64, error) {
 	ret := _m.Called(page, perpage, id)
 
 	var r0 []model.Category
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int, int, uint) ([]model.Category, int64, error)); ok {
+		return rf(page, perpage, id)
+	}
 	if rf, ok := ret.Get(0).(func(int, int, uint) []model.Category); ok {
 		r0 = rf(page, perpage, id)
 	} else {
@@ -54,14 +59,30 @@ func (_m *CategoryInterface) GetCategories(page int, perpage int, id uint) []mod
 		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(int, int, uint) int64); ok {
+		r1 = rf(page, perpage, id)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(int, int, uint) error); ok {
+		r2 = rf(page, perpage, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // GetCategory provides a mock function with given fields: id, idUser
-func (_m *CategoryInterface) GetCategory(id int, idUser uint) *model.Category {
+func (_m *CategoryInterface) GetCategory(id int, idUser uint) (*model.Category, error) {
 	ret := _m.Called(id, idUser)
 
 	var r0 *model.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, uint) (*model.Category, error)); ok {
+		return rf(id, idUser)
+	}
 	if rf, ok := ret.Get(0).(func(int, uint) *model.Category); ok {
 		r0 = rf(id, idUser)
 	} else {
@@ -70,18 +91,24 @@ func (_m *CategoryInterface) GetCategory(id int, idUser uint) *model.Category {
 		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(int, uint) error); ok {
+		r1 = rf(id, idUser)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // UpdateCategory provides a mock function with given fields: category, id, idUser
-func (_m *CategoryInterface) UpdateCategory(category model.Category, id int, idUser uint) bool {
+func (_m *CategoryInterface) UpdateCategory(category model.Category, id int, idUser uint) error {
 	ret := _m.Called(category, id, idUser)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(model.Category, int, uint) bool); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(model.Category, int, uint) error); ok {
 		r0 = rf(category, id, idUser)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Error(0)
 	}
 
 	return r0
@@ -3,12 +3,12 @@
 package mocks
 
 import (
-	echo "github.com/labstack/echo/v4"
-	mock "github.com/stretchr/testify/mock"
-
+	context "context"
 	model "mytodo/model"
 
-	openai "github.com/sashabaranov/go-openai"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TodoAIInterface is an autogenerated mock type for the TodoAIInterface type
@@ -16,23 +16,285 @@ type TodoAIInterface struct {
 	mock.Mock
 }
 
-// GetResponseAPI provides a mock function with given fields: c, key, todoAI
-func (_m *TodoAIInterface) GetResponseAPI(c echo.Context, key string, todoAI model.TodoAI) (openai.ChatCompletionResponse, error) {
-	ret := _m.Called(c, key, todoAI)
+// AcceptBreakdown provides a mock function with given fields: todo, steps, mode, userID
+func (_m *TodoAIInterface) AcceptBreakdown(todo model.Todo, steps []model.TodoStep, mode string, userID uint) *model.BreakdownResult {
+	ret := _m.Called(todo, steps, mode, userID)
+
+	var r0 *model.BreakdownResult
+	if rf, ok := ret.Get(0).(func(model.Todo, []model.TodoStep, string, uint) *model.BreakdownResult); ok {
+		r0 = rf(todo, steps, mode, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BreakdownResult)
+		}
+	}
+
+	return r0
+}
+
+// AcceptSuggestions provides a mock function with given fields: suggestions, userID
+func (_m *TodoAIInterface) AcceptSuggestions(suggestions []model.TodoSuggestion, userID uint) []model.Todo {
+	ret := _m.Called(suggestions, userID)
+
+	var r0 []model.Todo
+	if rf, ok := ret.Get(0).(func([]model.TodoSuggestion, uint) []model.Todo); ok {
+		r0 = rf(suggestions, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Todo)
+		}
+	}
+
+	return r0
+}
+
+// AddThread provides a mock function with given fields: newThread, userID
+func (_m *TodoAIInterface) AddThread(newThread model.TodoAIThread, userID uint) *model.TodoAIThread {
+	ret := _m.Called(newThread, userID)
+
+	var r0 *model.TodoAIThread
+	if rf, ok := ret.Get(0).(func(model.TodoAIThread, uint) *model.TodoAIThread); ok {
+		r0 = rf(newThread, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TodoAIThread)
+		}
+	}
+
+	return r0
+}
+
+// BreakdownTodo provides a mock function with given fields: ctx, todo, lang, userID
+func (_m *TodoAIInterface) BreakdownTodo(ctx context.Context, todo model.Todo, lang string, userID uint) (*model.TodoBreakdown, error) {
+	ret := _m.Called(ctx, todo, lang, userID)
+
+	var r0 *model.TodoBreakdown
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Todo, string, uint) (*model.TodoBreakdown, error)); ok {
+		return rf(ctx, todo, lang, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Todo, string, uint) *model.TodoBreakdown); ok {
+		r0 = rf(ctx, todo, lang, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TodoBreakdown)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Todo, string, uint) error); ok {
+		r1 = rf(ctx, todo, lang, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CategorizeTodo provides a mock function with given fields: ctx, memo, useAI, userID
+func (_m *TodoAIInterface) CategorizeTodo(ctx context.Context, memo string, useAI bool, userID uint) (*model.CategoryPrediction, error) {
+	ret := _m.Called(ctx, memo, useAI, userID)
+
+	var r0 *model.CategoryPrediction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, uint) (*model.CategoryPrediction, error)); ok {
+		return rf(ctx, memo, useAI, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, uint) *model.CategoryPrediction); ok {
+		r0 = rf(ctx, memo, useAI, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.CategoryPrediction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, uint) error); ok {
+		r1 = rf(ctx, memo, useAI, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteThread provides a mock function with given fields: id, userID
+func (_m *TodoAIInterface) DeleteThread(id int, userID uint) error {
+	ret := _m.Called(id, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, uint) error); ok {
+		r0 = rf(id, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetThread provides a mock function with given fields: id, userID
+func (_m *TodoAIInterface) GetThread(id int, userID uint) *model.TodoAIThread {
+	ret := _m.Called(id, userID)
+
+	var r0 *model.TodoAIThread
+	if rf, ok := ret.Get(0).(func(int, uint) *model.TodoAIThread); ok {
+		r0 = rf(id, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TodoAIThread)
+		}
+	}
+
+	return r0
+}
+
+// GetThreads provides a mock function with given fields: page, perpage, userID
+func (_m *TodoAIInterface) GetThreads(page int, perpage int, userID uint) ([]model.TodoAIThread, int64) {
+	ret := _m.Called(page, perpage, userID)
+
+	var r0 []model.TodoAIThread
+	var r1 int64
+	if rf, ok := ret.Get(0).(func(int, int, uint) ([]model.TodoAIThread, int64)); ok {
+		return rf(page, perpage, userID)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, uint) []model.TodoAIThread); ok {
+		r0 = rf(page, perpage, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TodoAIThread)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, uint) int64); ok {
+		r1 = rf(page, perpage, userID)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	return r0, r1
+}
+
+// PlanTodos provides a mock function with given fields: ctx, todoAI, userID, upcoming, categories
+func (_m *TodoAIInterface) PlanTodos(ctx context.Context, todoAI model.TodoAI, userID uint, upcoming []model.Todo, categories []model.Category) (*model.TodoPlan, error) {
+	ret := _m.Called(ctx, todoAI, userID, upcoming, categories)
+
+	var r0 *model.TodoPlan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TodoAI, uint, []model.Todo, []model.Category) (*model.TodoPlan, error)); ok {
+		return rf(ctx, todoAI, userID, upcoming, categories)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TodoAI, uint, []model.Todo, []model.Category) *model.TodoPlan); ok {
+		r0 = rf(ctx, todoAI, userID, upcoming, categories)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TodoPlan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TodoAI, uint, []model.Todo, []model.Category) error); ok {
+		r1 = rf(ctx, todoAI, userID, upcoming, categories)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReviewWeek provides a mock function with given fields: ctx, start, todos, useAI, refresh, userID
+func (_m *TodoAIInterface) ReviewWeek(ctx context.Context, start time.Time, todos []model.Todo, useAI bool, refresh bool, userID uint) (*model.WeeklyReview, error) {
+	ret := _m.Called(ctx, start, todos, useAI, refresh, userID)
+
+	var r0 *model.WeeklyReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, []model.Todo, bool, bool, uint) (*model.WeeklyReview, error)); ok {
+		return rf(ctx, start, todos, useAI, refresh, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, []model.Todo, bool, bool, uint) *model.WeeklyReview); ok {
+		r0 = rf(ctx, start, todos, useAI, refresh, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WeeklyReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, []model.Todo, bool, bool, uint) error); ok {
+		r1 = rf(ctx, start, todos, useAI, refresh, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendMessage provides a mock function with given fields: ctx, threadID, userID, content
+func (_m *TodoAIInterface) SendMessage(ctx context.Context, threadID int, userID uint, content string) (*model.TodoAIMessage, error) {
+	ret := _m.Called(ctx, threadID, userID, content)
+
+	var r0 *model.TodoAIMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, uint, string) (*model.TodoAIMessage, error)); ok {
+		return rf(ctx, threadID, userID, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, uint, string) *model.TodoAIMessage); ok {
+		r0 = rf(ctx, threadID, userID, content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TodoAIMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, uint, string) error); ok {
+		r1 = rf(ctx, threadID, userID, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuggestTodos provides a mock function with given fields: ctx, todoAI, userID
+func (_m *TodoAIInterface) SuggestTodos(ctx context.Context, todoAI model.TodoAI, userID uint) ([]model.TodoSuggestion, error) {
+	ret := _m.Called(ctx, todoAI, userID)
+
+	var r0 []model.TodoSuggestion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TodoAI, uint) ([]model.TodoSuggestion, error)); ok {
+		return rf(ctx, todoAI, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TodoAI, uint) []model.TodoSuggestion); ok {
+		r0 = rf(ctx, todoAI, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TodoSuggestion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TodoAI, uint) error); ok {
+		r1 = rf(ctx, todoAI, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuggestTodosStream provides a mock function with given fields: ctx, todoAI, userID, onDelta
+func (_m *TodoAIInterface) SuggestTodosStream(ctx context.Context, todoAI model.TodoAI, userID uint, onDelta model.StreamFunc) ([]model.TodoSuggestion, error) {
+	ret := _m.Called(ctx, todoAI, userID, onDelta)
 
-	var r0 openai.ChatCompletionResponse
+	var r0 []model.TodoSuggestion
 	var r1 error
-	if rf, ok := ret.Get(0).(func(echo.Context, string, model.TodoAI) (openai.ChatCompletionResponse, error)); ok {
-		return rf(c, key, todoAI)
+	if rf, ok := ret.Get(0).(func(context.Context, model.TodoAI, uint, model.StreamFunc) ([]model.TodoSuggestion, error)); ok {
+		return rf(ctx, todoAI, userID, onDelta)
 	}
-	if rf, ok := ret.Get(0).(func(echo.Context, string, model.TodoAI) openai.ChatCompletionResponse); ok {
-		r0 = rf(c, key, todoAI)
+	if rf, ok := ret.Get(0).(func(context.Context, model.TodoAI, uint, model.StreamFunc) []model.TodoSuggestion); ok {
+		r0 = rf(ctx, todoAI, userID, onDelta)
 	} else {
-		r0 = ret.Get(0).(openai.ChatCompletionResponse)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TodoSuggestion)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(echo.Context, string, model.TodoAI) error); ok {
-		r1 = rf(c, key, todoAI)
+	if rf, ok := ret.Get(1).(func(context.Context, model.TodoAI, uint, model.StreamFunc) error); ok {
+		r1 = rf(ctx, todoAI, userID, onDelta)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -14,38 +14,80 @@ type TodoInterface struct {
 }
 
 // AddTodo provides a mock function with given fields: newTodo
-func (_m *TodoInterface) AddTodo(newTodo model.Todo) bool {
+func (_m *TodoInterface) AddTodo(newTodo model.Todo) (*model.Todo, error) {
 	ret := _m.Called(newTodo)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(model.Todo) bool); ok {
+	var r0 *model.Todo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(model.Todo) (*model.Todo, error)); ok {
+		return rf(newTodo)
+	}
+	if rf, ok := ret.Get(0).(func(model.Todo) *model.Todo); ok {
 		r0 = rf(newTodo)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Todo)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(model.Todo) error); ok {
+		r1 = rf(newTodo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // DeleteTodo provides a mock function with given fields: id, userID
-func (_m *TodoInterface) DeleteTodo(id int, userID uint) bool {
+func (_m *TodoInterface) DeleteTodo(id int, userID uint) error {
 	ret := _m.Called(id, userID)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(int, uint) bool); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, uint) error); ok {
 		r0 = rf(id, userID)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
+// GetStatusHistory provides a mock function with given fields: id, userID
+func (_m *TodoInterface) GetStatusHistory(id int, userID uint) ([]model.TodoStatusHistory, error) {
+	ret := _m.Called(id, userID)
+
+	var r0 []model.TodoStatusHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, uint) ([]model.TodoStatusHistory, error)); ok {
+		return rf(id, userID)
+	}
+	if rf, ok := ret.Get(0).(func(int, uint) []model.TodoStatusHistory); ok {
+		r0 = rf(id, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TodoStatusHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, uint) error); ok {
+		r1 = rf(id, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTodo provides a mock function with given fields: id, userID
-func (_m *TodoInterface) GetTodo(id int, userID uint) *model.Todo {
+func (_m *TodoInterface) GetTodo(id int, userID uint) (*model.Todo, error) {
 	ret := _m.Called(id, userID)
 
 	var r0 *model.Todo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, uint) (*model.Todo, error)); ok {
+		return rf(id, userID)
+	}
 	if rf, ok := ret.Get(0).(func(int, uint) *model.Todo); ok {
 		r0 = rf(id, userID)
 	} else {
@@ -54,48 +96,146 @@ func (_m *TodoInterface) GetTodo(id int, userID uint) *model.Todo {
 		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(int, uint) error); ok {
+		r1 = rf(id, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// GetTodos provides a mock function with given fields: page, content, userID, status, date
-func (_m *TodoInterface) GetTodos(page int, content int, userID uint, status string, date string) []model.Todo {
-	ret := _m.Called(page, content, userID, status, date)
+// GetTodos provides a mock function with given fields: page, content, userID, filter
+func (_m *TodoInterface) GetTodos(page int, content int, userID uint, filter model.TodoFilter) ([]model.Todo, int64, error) {
+	ret := _m.Called(page, content, userID, filter)
 
 	var r0 []model.Todo
-	if rf, ok := ret.Get(0).(func(int, int, uint, string, string) []model.Todo); ok {
-		r0 = rf(page, content, userID, status, date)
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int, int, uint, model.TodoFilter) ([]model.Todo, int64, error)); ok {
+		return rf(page, content, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, uint, model.TodoFilter) []model.Todo); ok {
+		r0 = rf(page, content, userID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Todo)
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(int, int, uint, model.TodoFilter) int64); ok {
+		r1 = rf(page, content, userID, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(int, int, uint, model.TodoFilter) error); ok {
+		r2 = rf(page, content, userID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SearchTodos provides a mock function with given fields: query, page, content, userID
+func (_m *TodoInterface) SearchTodos(query string, page int, content int, userID uint) ([]model.TodoSearchResult, int64, error) {
+	ret := _m.Called(query, page, content, userID)
+
+	var r0 []model.TodoSearchResult
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, int, int, uint) ([]model.TodoSearchResult, int64, error)); ok {
+		return rf(query, page, content, userID)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, int, uint) []model.TodoSearchResult); ok {
+		r0 = rf(query, page, content, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TodoSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, int, uint) int64); ok {
+		r1 = rf(query, page, content, userID)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, int, int, uint) error); ok {
+		r2 = rf(query, page, content, userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SkipOccurrence provides a mock function with given fields: id, userID, date
+func (_m *TodoInterface) SkipOccurrence(id int, userID uint, date string) error {
+	ret := _m.Called(id, userID, date)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, uint, string) error); ok {
+		r0 = rf(id, userID, date)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateOccurrence provides a mock function with given fields: id, userID, date, todo
+func (_m *TodoInterface) UpdateOccurrence(id int, userID uint, date string, todo model.Todo) error {
+	ret := _m.Called(id, userID, date, todo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, uint, string, model.Todo) error); ok {
+		r0 = rf(id, userID, date, todo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
 	return r0
 }
 
 // UpdateTodo provides a mock function with given fields: id, userID, todo
-func (_m *TodoInterface) UpdateTodo(id int, userID uint, todo model.Todo) bool {
+func (_m *TodoInterface) UpdateTodo(id int, userID uint, todo model.Todo) error {
 	ret := _m.Called(id, userID, todo)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(int, uint, model.Todo) bool); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, uint, model.Todo) error); ok {
 		r0 = rf(id, userID, todo)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTodoCategory provides a mock function with given fields: id, userID, categoryID
+func (_m *TodoInterface) UpdateTodoCategory(id int, userID uint, categoryID uint) error {
+	ret := _m.Called(id, userID, categoryID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, uint, uint) error); ok {
+		r0 = rf(id, userID, categoryID)
+	} else {
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
 // UpdateTodoStatus provides a mock function with given fields: id, UserID, status
-func (_m *TodoInterface) UpdateTodoStatus(id int, UserID uint, status string) bool {
+func (_m *TodoInterface) UpdateTodoStatus(id int, UserID uint, status string) error {
 	ret := _m.Called(id, UserID, status)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(int, uint, string) bool); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, uint, string) error); ok {
 		r0 = rf(id, UserID, status)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Error(0)
 	}
 
 	return r0
@@ -13,11 +13,39 @@ type UsersInterface struct {
 	mock.Mock
 }
 
+// IsAdmin provides a mock function with given fields: id
+func (_m *UsersInterface) IsAdmin(id uint) (bool, error) {
+	ret := _m.Called(id)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (bool, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) bool); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Login provides a mock function with given fields: login
-func (_m *UsersInterface) Login(login model.Login) *model.Users {
+func (_m *UsersInterface) Login(login model.Login) (*model.Users, error) {
 	ret := _m.Called(login)
 
 	var r0 *model.Users
+	var r1 error
+	if rf, ok := ret.Get(0).(func(model.Login) (*model.Users, error)); ok {
+		return rf(login)
+	}
 	if rf, ok := ret.Get(0).(func(model.Login) *model.Users); ok {
 		r0 = rf(login)
 	} else {
@@ -26,14 +54,24 @@ func (_m *UsersInterface) Login(login model.Login) *model.Users {
 		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(model.Login) error); ok {
+		r1 = rf(login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // Register provides a mock function with given fields: newUser
-func (_m *UsersInterface) Register(newUser model.Users) *model.Users {
+func (_m *UsersInterface) Register(newUser model.Users) (*model.Users, error) {
 	ret := _m.Called(newUser)
 
 	var r0 *model.Users
+	var r1 error
+	if rf, ok := ret.Get(0).(func(model.Users) (*model.Users, error)); ok {
+		return rf(newUser)
+	}
 	if rf, ok := ret.Get(0).(func(model.Users) *model.Users); ok {
 		r0 = rf(newUser)
 	} else {
@@ -42,7 +80,13 @@ func (_m *UsersInterface) Register(newUser model.Users) *model.Users {
 		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(model.Users) error); ok {
+		r1 = rf(newUser)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewUsersInterface creates a new instance of UsersInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -4,20 +4,54 @@ import (
 	"fmt"
 	"mytodo/config"
 
+	"github.com/glebarez/sqlite"
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// sqlite memakai path ini untuk database di memory
+const sqliteMemory = ":memory:"
+
 func InitModel(config config.ProgramConfig) *gorm.DB {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", config.DBUser, config.DBPassword, config.DBHost, config.DBPort, config.DBName)
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	dialector, err := openDialector(config)
+	if err != nil {
+		logrus.Error("Model: Tidak Dapat Terkoneksi Database, ", err.Error())
+		return nil
+	}
+	// TranslateError mengubah error driver (duplikat, foreign key) menjadi error gorm
+	// agar dbError bisa memetakannya ke error domain
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
 	if err != nil {
 		logrus.Error("Model: Tidak Dapat Terkoneksi Database, ", err.Error())
+		return nil
+	}
+	if config.DBDriver == DriverSQLite && config.DBPath == sqliteMemory {
+		// setiap koneksi sqlite :memory: adalah database baru, jadi pool dibatasi satu koneksi
+		sqlDB, err := db.DB()
+		if err != nil {
+			logrus.Error("Model: Tidak Dapat Terkoneksi Database, ", err.Error())
+			return nil
+		}
+		sqlDB.SetMaxOpenConns(1)
 	}
 	return db
 }
 
-func Migrate(db *gorm.DB) {
-	db.AutoMigrate(&Users{}, &Category{}, &Todo{})
+// openDialector memilih driver gorm berdasarkan config DBDriver
+func openDialector(config config.ProgramConfig) (gorm.Dialector, error) {
+	switch config.DBDriver {
+	case "", DriverMySQL:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", config.DBUser, config.DBPassword, config.DBHost, config.DBPort, config.DBName)
+		return mysql.Open(dsn), nil
+	case DriverPostgres:
+		dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s", config.DBHost, config.DBPort, config.DBUser, config.DBPassword, config.DBName, config.DBSSLMode)
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		// foreign key sqlite mati secara default, busy_timeout menunggu writer lain selesai
+		dsn := config.DBPath + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+		return sqlite.Open(dsn), nil
+	}
+	return nil, fmt.Errorf("unknown database driver %q", config.DBDriver)
 }
@@ -0,0 +1,223 @@
+package model
+
+import (
+	"mytodo/helper"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// batas percobaan kirim sebelum reminder tidak diambil lagi oleh scheduler
+const MaxReminderAttempts = 5
+
+type ReminderInterface interface {
+	AddReminder(newReminder Reminder, userID uint) *Reminder
+	GetReminders(todoID int, userID uint) []Reminder
+	DeleteReminder(id int, todoID int, userID uint) bool
+	ClaimDueReminders(owner string, now time.Time, lease time.Duration, limit int) []Reminder
+	MarkReminderSent(reminder Reminder, sentAt time.Time) bool
+	ReleaseReminder(reminder Reminder, retryAt time.Time, errMsg string) bool
+}
+
+// Reminder adalah pengingat untuk sebuah todo. OffsetMinutes berarti sekian
+// menit sebelum DateTime todo (RemindAt dihitung ulang saat todo berubah),
+// jika kosong RemindAt adalah waktu absolut. LeaseOwner dan LeaseUntil
+// menandai instance yang sedang mengirim reminder tersebut.
+type Reminder struct {
+	gorm.Model
+	TodoID        uint       `json:"todo_id" form:"todo_id" gorm:"index"`
+	Todo          Todo       `json:"-" form:"-"`
+	UserID        uint       `json:"user_id" form:"user_id"`
+	User          Users      `json:"-" form:"-"`
+	OffsetMinutes *int       `json:"offset_minutes" form:"offset_minutes"`
+	RemindAt      time.Time  `json:"remind_at" form:"remind_at" gorm:"type:datetime;index"`
+	SentAt        *time.Time `json:"sent_at" form:"-" gorm:"type:datetime"`
+	Attempts      int        `json:"attempts" form:"-"`
+	LastError     string     `json:"last_error,omitempty" form:"-" gorm:"type:varchar(255)"`
+	LeaseOwner    string     `json:"-" form:"-" gorm:"type:varchar(100);index"`
+	LeaseUntil    *time.Time `json:"-" form:"-" gorm:"type:datetime"`
+}
+
+type ReminderModel struct {
+	db *gorm.DB
+}
+
+func (rm *ReminderModel) InitReminder(db *gorm.DB) {
+	rm.db = db
+}
+
+func NewReminderModel(db *gorm.DB) ReminderInterface {
+	return &ReminderModel{
+		db: db,
+	}
+}
+
+func (rm *ReminderModel) AddReminder(newReminder Reminder, userID uint) *Reminder {
+	todo := Todo{}
+	if err := rm.db.Where("user_id = ?", userID).First(&todo, newReminder.TodoID).Error; err != nil {
+		logrus.Error("Model: Todo Reminder Tidak Ditemukan ", err.Error())
+		return nil
+	}
+	newReminder.UserID = userID
+	if newReminder.OffsetMinutes != nil {
+		remindAt, found := nextRemindAt(todo, *newReminder.OffsetMinutes, time.Now())
+		if !found {
+			logrus.Error("Model: Tidak Ada Kejadian Todo Untuk Reminder")
+			return nil
+		}
+		newReminder.RemindAt = remindAt
+	}
+	if err := rm.db.Create(&newReminder).Error; err != nil {
+		logrus.Error("Model: Error Saat Input Reminder ", err.Error())
+		return nil
+	}
+	return &newReminder
+}
+
+func (rm *ReminderModel) GetReminders(todoID int, userID uint) []Reminder {
+	if err := rm.db.Where("user_id = ?", userID).First(&Todo{}, todoID).Error; err != nil {
+		logrus.Error("Model: Todo Reminder Tidak Ditemukan ", err.Error())
+		return nil
+	}
+	reminders := []Reminder{}
+	if err := rm.db.Where("todo_id = ?", todoID).Order("remind_at, id").Find(&reminders).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Data Reminder ", err.Error())
+		return nil
+	}
+	return reminders
+}
+
+func (rm *ReminderModel) DeleteReminder(id int, todoID int, userID uint) bool {
+	res := rm.db.Where("todo_id = ? AND user_id = ?", todoID, userID).Delete(&Reminder{}, id)
+	if res.Error != nil {
+		logrus.Error("Model: Error Delete Reminder ", res.Error.Error())
+		return false
+	}
+	if res.RowsAffected == 0 {
+		logrus.Error("Model: Reminder Tidak Ditemukan")
+		return false
+	}
+	return true
+}
+
+// ClaimDueReminders mengambil reminder yang sudah jatuh tempo dan memasang
+// lease atas nama owner. UPDATE bersyarat memastikan satu reminder hanya
+// diklaim satu instance, reminder yang lease-nya habis dapat diklaim ulang.
+func (rm *ReminderModel) ClaimDueReminders(owner string, now time.Time, lease time.Duration, limit int) []Reminder {
+	ids := []uint{}
+	err := rm.db.Model(&Reminder{}).
+		Joins("JOIN todos ON todos.id = reminders.todo_id AND todos.deleted_at IS NULL").
+		Where("reminders.sent_at IS NULL AND reminders.remind_at <= ? AND reminders.attempts < ?", now, MaxReminderAttempts).
+		Where("reminders.lease_until IS NULL OR reminders.lease_until < ?", now).
+		Where("todos.status NOT IN ?", []string{StatusDone, StatusCancelled}).
+		Order("reminders.remind_at").
+		Limit(limit).
+		Pluck("reminders.id", &ids).Error
+	if err != nil {
+		logrus.Error("Model: Error Mencari Reminder Jatuh Tempo ", err.Error())
+		return nil
+	}
+	if len(ids) == 0 {
+		return []Reminder{}
+	}
+	leaseUntil := now.Add(lease)
+	err = rm.db.Model(&Reminder{}).
+		Where("id IN ? AND sent_at IS NULL", ids).
+		Where("lease_until IS NULL OR lease_until < ?", now).
+		Updates(map[string]any{"lease_owner": owner, "lease_until": leaseUntil}).Error
+	if err != nil {
+		logrus.Error("Model: Error Klaim Reminder ", err.Error())
+		return nil
+	}
+	reminders := []Reminder{}
+	if err := rm.db.Preload("Todo").Preload("User").Where("id IN ? AND lease_owner = ? AND sent_at IS NULL", ids, owner).Find(&reminders).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Reminder Yang Diklaim ", err.Error())
+		return nil
+	}
+	return reminders
+}
+
+// MarkReminderSent menandai reminder terkirim. Reminder offset pada todo
+// berulang dijadwalkan ulang ke kejadian berikutnya.
+func (rm *ReminderModel) MarkReminderSent(reminder Reminder, sentAt time.Time) bool {
+	updates := map[string]any{
+		"sent_at":     sentAt,
+		"attempts":    0,
+		"last_error":  "",
+		"lease_owner": "",
+		"lease_until": nil,
+	}
+	if reminder.OffsetMinutes != nil && reminder.Todo.RRule != "" {
+		if next, found := nextRemindAt(reminder.Todo, *reminder.OffsetMinutes, reminder.RemindAt); found {
+			updates["sent_at"] = nil
+			updates["remind_at"] = next
+		}
+	}
+	res := rm.db.Model(&Reminder{}).Where("id = ? AND lease_owner = ?", reminder.ID, reminder.LeaseOwner).Updates(updates)
+	if res.Error != nil {
+		logrus.Error("Model: Error Menandai Reminder Terkirim ", res.Error.Error())
+		return false
+	}
+	return res.RowsAffected == 1
+}
+
+// ReleaseReminder melepas lease setelah pengiriman gagal sehingga reminder dicoba lagi pada retryAt
+func (rm *ReminderModel) ReleaseReminder(reminder Reminder, retryAt time.Time, errMsg string) bool {
+	errMsg = truncateRunes(errMsg, 255)
+	res := rm.db.Model(&Reminder{}).Where("id = ? AND lease_owner = ?", reminder.ID, reminder.LeaseOwner).Updates(map[string]any{
+		"attempts":    gorm.Expr("attempts + 1"),
+		"last_error":  errMsg,
+		"lease_owner": "",
+		"lease_until": retryAt,
+	})
+	if res.Error != nil {
+		logrus.Error("Model: Error Melepas Reminder ", res.Error.Error())
+		return false
+	}
+	return res.RowsAffected == 1
+}
+
+// nextRemindAt menghitung waktu reminder offset untuk kejadian pertama yang
+// waktu reminder-nya setelah after. Todo biasa selalu memakai DateTime-nya.
+func nextRemindAt(todo Todo, offsetMinutes int, after time.Time) (time.Time, bool) {
+	offset := time.Duration(offsetMinutes) * time.Minute
+	if todo.RRule == "" {
+		return todo.DateTime.Add(-offset), true
+	}
+	rule, err := helper.ParseRRule(todo.RRule)
+	if err != nil {
+		logrus.Error("Model: Aturan Pengulangan Todo Tidak Valid ", err.Error())
+		return time.Time{}, false
+	}
+	var res time.Time
+	found := false
+	rule.Iterate(todo.DateTime, func(t time.Time) bool {
+		if t.Add(-offset).After(after) {
+			res = t.Add(-offset)
+			found = true
+			return false
+		}
+		return true
+	})
+	return res, found
+}
+
+// rescheduleReminders menghitung ulang reminder offset yang belum terkirim setelah jadwal todo berubah
+func rescheduleReminders(db *gorm.DB, todo Todo) error {
+	reminders := []Reminder{}
+	if err := db.Where("todo_id = ? AND offset_minutes IS NOT NULL AND sent_at IS NULL", todo.ID).Find(&reminders).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, reminder := range reminders {
+		remindAt, found := nextRemindAt(todo, *reminder.OffsetMinutes, now)
+		if !found {
+			continue
+		}
+		if err := db.Model(&Reminder{}).Where("id = ?", reminder.ID).Updates(map[string]any{"remind_at": remindAt, "attempts": 0}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestReminderModel_ReleaseReminder(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	// AutoMigrate Reminder ikut membuat index FULLTEXT todos yang tidak ada di SQLite
+	require.NoError(t, db.Exec("CREATE TABLE reminders (id integer PRIMARY KEY AUTOINCREMENT, created_at datetime, updated_at datetime, deleted_at datetime, todo_id integer, user_id integer, offset_minutes integer, remind_at datetime, sent_at datetime, attempts integer DEFAULT 0, last_error varchar(255), lease_owner varchar(100), lease_until datetime)").Error)
+	reminder := Reminder{TodoID: 1, UserID: 1, RemindAt: time.Now(), LeaseOwner: "instance-1"}
+	require.NoError(t, db.Omit(clause.Associations).Create(&reminder).Error)
+
+	// pesan error notifier dipotong per karakter agar tetap UTF-8 yang valid
+	require.True(t, NewReminderModel(db).ReleaseReminder(reminder, time.Now().Add(time.Minute), strings.Repeat("ü", 300)))
+
+	got := Reminder{}
+	require.NoError(t, db.First(&got, reminder.ID).Error)
+	require.Equal(t, 1, got.Attempts)
+	require.Empty(t, got.LeaseOwner)
+	require.True(t, utf8.ValidString(got.LastError))
+	require.Equal(t, 255, utf8.RuneCountInString(got.LastError))
+}
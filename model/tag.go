@@ -0,0 +1,175 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagInterface interface {
+	AddTag(newTag Tag) error
+	GetTags(page, perpage int, userID uint) ([]Tag, int64)
+	GetTag(id int, userID uint) *Tag
+	UpdateTag(tag Tag, id int, userID uint) error
+	DeleteTag(id int, userID uint) bool
+}
+
+// Tag dimiliki per user dan terhubung ke Todo melalui tabel todo_tags.
+// Nama tag selalu disimpan dalam huruf kecil.
+type Tag struct {
+	gorm.Model
+	Name   string `json:"name" form:"name" gorm:"type:varchar(100);uniqueIndex:idx_tag_user_name"`
+	Color  string `json:"color" form:"color" gorm:"type:varchar(255)"`
+	UserID uint   `json:"user_id" form:"user_id" gorm:"uniqueIndex:idx_tag_user_name"`
+}
+
+type TagModel struct {
+	db *gorm.DB
+}
+
+func (tm *TagModel) InitTag(db *gorm.DB) {
+	tm.db = db
+}
+
+func NewTagModel(db *gorm.DB) TagInterface {
+	return &TagModel{
+		db: db,
+	}
+}
+
+// AddTag mengembalikan ErrConflict jika user sudah memiliki tag dengan nama yang sama
+func (tm *TagModel) AddTag(newTag Tag) error {
+	newTag.Name = NormalizeTagName(newTag.Name)
+	if newTag.Name == "" {
+		logrus.Error("Model: Nama Tag Kosong")
+		return newError(ErrValidation, "tag name is required")
+	}
+	if err := tm.db.Create(&newTag).Error; err != nil {
+		logrus.Error("Model: Error Saat Input Tag ", err.Error())
+		return dbError(err, "tag %s", newTag.Name)
+	}
+	return nil
+}
+
+func (tm *TagModel) GetTags(page, perpage int, userID uint) ([]Tag, int64) {
+	tags := []Tag{}
+	var total int64
+	if err := tm.db.Model(&Tag{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		logrus.Error("Model: Error Menghitung Data Tag ", err.Error())
+		return nil, 0
+	}
+	offset := (page - 1) * perpage
+	if err := tm.db.Limit(perpage).Offset(offset).Where("user_id = ?", userID).Order("name").Find(&tags).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Data Tag ", err.Error())
+		return nil, 0
+	}
+	return tags, total
+}
+
+func (tm *TagModel) GetTag(id int, userID uint) *Tag {
+	tag := Tag{}
+	if err := tm.db.Where("user_id = ?", userID).First(&tag, id).Error; err != nil {
+		logrus.Error("Model: Data Tag Tidak Ditemukan ", err.Error())
+		return nil
+	}
+	return &tag
+}
+
+// UpdateTag mengembalikan ErrNotFound untuk tag milik user lain dan
+// ErrConflict jika nama baru sudah dipakai tag lain milik user
+func (tm *TagModel) UpdateTag(tagUp Tag, id int, userID uint) error {
+	data := Tag{}
+	if err := tm.db.Where("user_id = ?", userID).First(&data, id).Error; err != nil {
+		logrus.Error("Model: Error Update Data Tag ", err.Error())
+		return dbError(err, "tag %d", id)
+	}
+	if name := NormalizeTagName(tagUp.Name); name != "" {
+		data.Name = name
+	}
+	data.Color = tagUp.Color
+	if err := tm.db.Save(&data).Error; err != nil {
+		logrus.Error("Model: Error Update Data Tag ", err.Error())
+		return dbError(err, "tag %s", data.Name)
+	}
+	return nil
+}
+
+func (tm *TagModel) DeleteTag(id int, userID uint) bool {
+	data := tm.GetTag(id, userID)
+	if data == nil {
+		logrus.Error("Model: Error Delete Tag")
+		return false
+	}
+	err := tm.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM todo_tags WHERE tag_id = ?", data.ID).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(data).Error
+	})
+	if err != nil {
+		logrus.Error("Model: Error Delete Tag ", err.Error())
+		return false
+	}
+	return true
+}
+
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// resolveTags mengembalikan tag milik user sesuai nama, tag yang belum ada dibuat otomatis
+func resolveTags(db *gorm.DB, userID uint, names []string) ([]Tag, error) {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = NormalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		unique = append(unique, name)
+	}
+	tags := []Tag{}
+	if len(unique) == 0 {
+		return tags, nil
+	}
+	newTags := make([]Tag, 0, len(unique))
+	for _, name := range unique {
+		newTags = append(newTags, Tag{Name: name, UserID: userID})
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ? AND name IN ?", userID, unique).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// tagFilter membatasi todo yang memiliki salah satu tag (any) atau semua tag (all)
+func tagFilter(db *gorm.DB, userID uint, names []string, matchAll bool) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		if len(names) == 0 {
+			return query
+		}
+		unique := map[string]bool{}
+		for _, name := range names {
+			unique[NormalizeTagName(name)] = true
+		}
+		normalized := make([]string, 0, len(unique))
+		for name := range unique {
+			normalized = append(normalized, name)
+		}
+		sub := db.Session(&gorm.Session{NewDB: true}).
+			Table("todo_tags").
+			Select("todo_tags.todo_id").
+			Joins("JOIN tags ON tags.id = todo_tags.tag_id").
+			Where("tags.user_id = ? AND tags.name IN ?", userID, normalized)
+		if matchAll {
+			sub = sub.Group("todo_tags.todo_id").Having("COUNT(DISTINCT tags.id) = ?", len(normalized))
+		}
+		return query.Where("todos.id IN (?)", sub)
+	}
+}
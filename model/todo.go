@@ -1,6 +1,9 @@
 package model
 
 import (
+	"fmt"
+	"mytodo/helper"
+	"sort"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -8,30 +11,91 @@ import (
 )
 
 type TodoInterface interface {
-	AddTodo(newTodo Todo) bool
-	GetTodos(page, content int, userID uint, status, date string) []Todo
-	GetTodo(id int, userID uint) *Todo
-	UpdateTodo(id int, userID uint, todo Todo) bool
-	UpdateTodoStatus(id int, UserID uint, status string) bool
-	DeleteTodo(id int, userID uint) bool
+	AddTodo(newTodo Todo) (*Todo, error)
+	GetTodos(page, content int, userID uint, filter TodoFilter) ([]Todo, int64, error)
+	GetTodo(id int, userID uint) (*Todo, error)
+	UpdateTodo(id int, userID uint, todo Todo) error
+	UpdateTodoCategory(id int, userID uint, categoryID uint) error
+	UpdateTodoStatus(id int, UserID uint, status string) error
+	DeleteTodo(id int, userID uint) error
+	UpdateOccurrence(id int, userID uint, date string, todo Todo) error
+	SkipOccurrence(id int, userID uint, date string) error
+	GetStatusHistory(id int, userID uint) ([]TodoStatusHistory, error)
+	SearchTodos(query string, page, content int, userID uint) ([]TodoSearchResult, int64, error)
 }
 
 type Todo struct {
 	// ID       uint      `json:"id" form:"id" gorm:"type:primaryKey;autoIncrement:true"`
 	gorm.Model
-	Memo     string    `json:"memo" form:"memo" gorm:"type:varchar(255)"`
+	Memo     string    `json:"memo" form:"memo" gorm:"type:varchar(255);index:idx_todo_memo_fulltext,class:FULLTEXT" validate:"required,max=255"`
 	DateTime time.Time `json:"date_time" form:"date_time" gorm:"datetime"`
+	// Duration adalah perkiraan lama kegiatan dalam menit, 0 berarti tidak diisi
+	Duration int `json:"duration" form:"duration" validate:"gte=0"`
 	// Filename   string
 	Status string
 	// CreatedAt  time.Time `json:"created_at" form:"created_at" gorm:"type:datetime"`
 	// UpdatedAt  time.Time `json:"updated_at" form:"updated_at" gorm:"type:datetime"`
 	// DeletedAt  time.Time `json:"deleted_at" form:"deleted_at" gorm:"type:datetime"`
-	CategoryID uint     `json:"category_id" form:"category_id"`
-	Category   Category `json:"category" form:"category"`
+	// CategoryID nil berarti todo tanpa category, disimpan NULL
+	CategoryID *uint    `json:"category_id" form:"category_id"`
+	Category   Category `json:"category" form:"category" validate:"-"`
 	UserID     uint     `json:"user_id" form:"user_id"`
-	User       Users    `json:"user" form:"user"`
+	User       Users    `json:"user" form:"user" validate:"-"`
+	// CategorySource menandai category dipilih user (manual) atau otomatis
+	// (ai/local) beserta tingkat keyakinannya, AutoCategorize hanya input
+	CategorySource     string  `json:"category_source" form:"-" gorm:"type:varchar(20);default:'manual'"`
+	CategoryConfidence float64 `json:"category_confidence" form:"-"`
+	AutoCategorize     bool    `json:"auto_categorize,omitempty" form:"auto_categorize" gorm:"-"`
+	// ParentID diisi jika todo dibuat dari pemecahan todo lain
+	ParentID *uint `json:"parent_id,omitempty" form:"-" gorm:"index"`
+	// RRule berisi aturan pengulangan RFC 5545, DateTime menjadi DTSTART-nya
+	RRule          string           `json:"rrule" form:"rrule" gorm:"column:rrule;type:varchar(255);default:''"`
+	OccurrenceDate *time.Time       `json:"occurrence_date,omitempty" form:"-" gorm:"-"`
+	NextOccurrence *time.Time       `json:"next_occurrence,omitempty" form:"-" gorm:"-"`
+	Exceptions     []TodoOccurrence `json:"exceptions,omitempty" form:"-" gorm:"-"`
+	// AutoComplete menandai todo Done saat semua item checklist selesai,
+	// RequireItemsDone menolak status Done selama masih ada item terbuka
+	AutoComplete     bool         `json:"auto_complete" form:"auto_complete"`
+	RequireItemsDone bool         `json:"require_items_done" form:"require_items_done"`
+	Progress         TodoProgress `json:"progress" form:"-" gorm:"-"`
+	Items            []TodoItem   `json:"items,omitempty" form:"-" gorm:"-"`
+	StartedAt        *time.Time   `json:"started_at" form:"-" gorm:"type:datetime"`
+	FinishedAt       *time.Time   `json:"finished_at" form:"-" gorm:"type:datetime"`
+	// TagNames adalah input nama tag, tag yang belum ada dibuat otomatis.
+	// Nil berarti tag tidak diubah, slice kosong menghapus semua tag.
+	Tags     []Tag    `json:"tags" form:"-" gorm:"many2many:todo_tags"`
+	TagNames []string `json:"tag_names,omitempty" form:"tag_names" gorm:"-" validate:"dive,max=100"`
 }
 
+var ErrOpenItems = newError(ErrConflict, "todo still has open checklist items")
+
+// TodoSearchResult adalah hasil pencarian todo beserta skor relevansi dan
+// potongan memo/category yang kata kuncinya ditandai <mark>
+type TodoSearchResult struct {
+	Todo            Todo    `json:"todo"`
+	Score           float64 `json:"score"`
+	Snippet         string  `json:"snippet"`
+	CategorySnippet string  `json:"category_snippet,omitempty"`
+}
+
+// panjang maksimal snippet hasil pencarian
+const searchSnippetWidth = 120
+
+// TodoOccurrence menyimpan pengecualian untuk satu kejadian todo berulang,
+// baik dilewati, diubah, maupun status per kejadian. Date adalah tanggal asli
+// kejadian dengan format 2006-01-02.
+type TodoOccurrence struct {
+	gorm.Model
+	TodoID   uint       `json:"todo_id" form:"todo_id" gorm:"uniqueIndex:idx_todo_occurrence"`
+	Date     string     `json:"date" form:"date" gorm:"type:varchar(10);uniqueIndex:idx_todo_occurrence"`
+	Skipped  bool       `json:"skipped" form:"skipped"`
+	Memo     string     `json:"memo" form:"memo" gorm:"type:varchar(255)"`
+	DateTime *time.Time `json:"date_time" form:"date_time" gorm:"type:datetime"`
+	Status   string     `json:"status" form:"status" gorm:"type:varchar(50)"`
+}
+
+const occurrenceDateFormat = "2006-01-02"
+
 type TodoModel struct {
 	db *gorm.DB
 }
@@ -46,40 +110,82 @@ func NewTodoModel(db *gorm.DB) TodoInterface {
 	}
 }
 
-func (tm *TodoModel) AddTodo(newTodo Todo) bool {
-	if err := tm.db.Create(&newTodo).Error; err != nil {
-		logrus.Error("Model: Error Saat Input Todo")
-		return false
+func (tm *TodoModel) AddTodo(newTodo Todo) (*Todo, error) {
+	if newTodo.CategorySource == "" {
+		newTodo.CategorySource = CategorySourceManual
+	}
+	newTodo.CategoryID = todoCategoryID(newTodo.CategoryID)
+	if err := checkCategory(tm.db, newTodo.UserID, newTodo.CategoryID); err != nil {
+		logrus.Error("Model: Category Todo Tidak Valid ", err.Error())
+		return nil, err
 	}
-	return true
+	err := tm.db.Transaction(func(tx *gorm.DB) error {
+		tags, err := resolveTags(tx, newTodo.UserID, newTodo.TagNames)
+		if err != nil {
+			return err
+		}
+		newTodo.Tags = tags
+		return tx.Create(&newTodo).Error
+	})
+	if err != nil {
+		logrus.Error("Model: Error Saat Input Todo ", err.Error())
+		return nil, dbError(err, "todo")
+	}
+	return &newTodo, nil
 }
 
-func (tm *TodoModel) GetTodos(page, content int, userID uint, status, datetime string) []Todo {
+func (tm *TodoModel) GetTodos(page, content int, userID uint, filter TodoFilter) ([]Todo, int64, error) {
 	todo := []Todo{}
+	var total int64
 	offset := (page - 1) * content
-	if status != "" {
-		if err := tm.db.Limit(content).Offset(offset).Where("user_id = ? AND status = ?", userID, status).Find(&todo).Error; err != nil {
-			logrus.Error("Model: Error Mendapatkan Data Todo Status ", err.Error())
-			return nil
+	now := time.Now()
+	if filter.From != nil && filter.To != nil {
+		var err error
+		todo, err = tm.getTodosInRange(userID, filter, now)
+		if err != nil {
+			return nil, 0, err
 		}
-	}
-	if datetime != "" {
-		if err := tm.db.Limit(content).Offset(offset).Where("user_id = ? AND DATE(date_time) = ?", userID, datetime).Find(&todo).Error; err != nil {
-			logrus.Error("Model: Error Mendapatkan Data Todo Status ", err.Error())
-			return nil
+		total = int64(len(todo))
+		todo = paginateTodos(todo, offset, content)
+	} else {
+		query := func() *gorm.DB {
+			query := tm.db.Model(&Todo{}).Scopes(filter.scope(tm.db, userID), filter.rowScope(now))
+			if filter.From != nil {
+				query = query.Where("todos.date_time >= ?", *filter.From)
+			}
+			if filter.To != nil {
+				query = query.Where("todos.date_time <= ?", *filter.To)
+			}
+			return query
 		}
-	}
-	if status == "" && datetime == "" {
-		if err := tm.db.Limit(content).Offset(offset).Where("user_id = ?", userID).Find(&todo).Error; err != nil {
+		if err := query().Count(&total).Error; err != nil {
+			logrus.Error("Model: Error Menghitung Data Todo ", err.Error())
+			return nil, 0, dbError(err, "count todos")
+		}
+		list := query().Preload("Tags")
+		if filter.Cursor {
+			// mode cursor selalu berurutan dari todo terbaru agar halaman berikutnya tidak bergeser
+			if filter.BeforeID != 0 {
+				list = list.Where("todos.id < ?", filter.BeforeID)
+			}
+			list = list.Order("todos.id DESC")
+			offset = 0
+		} else {
+			list = list.Order(filter.orderBy())
+		}
+		if err := list.Limit(content).Offset(offset).Find(&todo).Error; err != nil {
 			logrus.Error("Model: Error Mendapatkan Data Todo ", err.Error())
-			return nil
+			return nil, 0, dbError(err, "list todos")
 		}
 	}
 	for i := 0; i < len(todo); i++ {
+		if todo[i].CategoryID == nil {
+			continue
+		}
 		category := Category{}
-		if err := tm.db.First(&category, todo[i].CategoryID).Error; err != nil {
+		if err := tm.db.First(&category, *todo[i].CategoryID).Error; err != nil {
 			logrus.Error("Model: Error Mendapatkan Data Category Todo ", err.Error())
-			return nil
+			return nil, 0, dbError(err, "category %d", *todo[i].CategoryID)
 		}
 		todo[i].Category = category
 	}
@@ -88,77 +194,569 @@ func (tm *TodoModel) GetTodos(page, content int, userID uint, status, datetime s
 		user := Users{}
 		if err := tm.db.First(&user, userID).Error; err != nil {
 			logrus.Error("Model: Error Mendapatkan Data User Todo ", err.Error())
-			return nil
+			return nil, 0, dbError(err, "user %d", userID)
 		}
 		todo[i].Category.User = user
 		todo[i].User = user
 	}
-	return todo
+
+	ids := make([]uint, 0, len(todo))
+	for i := 0; i < len(todo); i++ {
+		ids = append(ids, todo[i].ID)
+	}
+	progress := todoProgress(tm.db, ids)
+	for i := 0; i < len(todo); i++ {
+		todo[i].Progress = progress[todo[i].ID]
+	}
+	return todo, total, nil
 }
 
-func (tm *TodoModel) GetTodo(id int, userID uint) *Todo {
+// GetTodo mengembalikan ErrNotFound juga untuk todo milik user lain agar
+// keberadaan todo tersebut tidak bocor
+func (tm *TodoModel) GetTodo(id int, userID uint) (*Todo, error) {
 	todo := Todo{}
 	if err := tm.db.Where("user_id = ?", userID).First(&todo, id).Error; err != nil {
 		logrus.Error("Model: Error Mendapatkan Data Todo ", err.Error())
-		return nil
+		return nil, dbError(err, "todo %d", id)
 	}
-	category := Category{}
-	if err := tm.db.Where("id = ?", todo.CategoryID).First(&category).Error; err != nil {
-		logrus.Error("Model: Error Mendapatkan Data Category Todo ", err.Error())
-		return nil
+	if todo.CategoryID != nil {
+		category := Category{}
+		if err := tm.db.Where("id = ?", *todo.CategoryID).First(&category).Error; err != nil {
+			logrus.Error("Model: Error Mendapatkan Data Category Todo ", err.Error())
+			return nil, dbError(err, "category %d", *todo.CategoryID)
+		}
+		todo.Category = category
 	}
-	todo.Category = category
 
 	user := Users{}
 	if err := tm.db.Where("id = ?", userID).First(&user).Error; err != nil {
 		logrus.Error("Model: Error Mendapatkan Data User Todo ", err.Error())
-		return nil
+		return nil, dbError(err, "user %d", userID)
 	}
 	todo.Category.User = user
 	todo.User = user
-	return &todo
+
+	if err := tm.db.Model(&todo).Association("Tags").Find(&todo.Tags); err != nil {
+		logrus.Error("Model: Error Mendapatkan Data Tag Todo ", err.Error())
+		return nil, dbError(err, "todo tags")
+	}
+
+	todo.Items = []TodoItem{}
+	if err := tm.db.Where("todo_id = ?", todo.ID).Order("position, id").Find(&todo.Items).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Data Item Todo ", err.Error())
+		return nil, dbError(err, "todo items")
+	}
+	for _, item := range todo.Items {
+		todo.Progress.Total++
+		if item.Done {
+			todo.Progress.Done++
+		}
+	}
+
+	if todo.RRule != "" {
+		rule, err := helper.ParseRRule(todo.RRule)
+		if err != nil {
+			logrus.Error("Model: Aturan Pengulangan Todo Tidak Valid ", err.Error())
+			return nil, fmt.Errorf("todo %d rrule: %w", id, err)
+		}
+		overrides := tm.getOccurrences(todo.ID)
+		if next, found := nextPendingOccurrence(rule, todo, overrides); found {
+			todo.NextOccurrence = &next
+		}
+		todo.Exceptions = []TodoOccurrence{}
+		for _, occurrence := range overrides {
+			todo.Exceptions = append(todo.Exceptions, occurrence)
+		}
+		sort.Slice(todo.Exceptions, func(i, j int) bool { return todo.Exceptions[i].Date < todo.Exceptions[j].Date })
+	}
+	return &todo, nil
 }
 
-func (tm *TodoModel) UpdateTodo(id int, userID uint, todo Todo) bool {
-	data := tm.GetTodo(id, userID)
-	if data == nil {
+func (tm *TodoModel) UpdateTodo(id int, userID uint, todo Todo) error {
+	data, err := tm.GetTodo(id, userID)
+	if err != nil {
 		logrus.Error("Model: Error Update Todo")
-		return false
+		return err
 	}
 	data.Memo = todo.Memo
 	data.DateTime = todo.DateTime
-	data.CategoryID = todo.CategoryID
-	if err := tm.db.Save(&data).Error; err != nil {
-		logrus.Error("Model: Error Update Todo")
-		return false
+	data.Duration = todo.Duration
+	todo.CategoryID = todoCategoryID(todo.CategoryID)
+	if categoryValue(data.CategoryID) != categoryValue(todo.CategoryID) {
+		if err := checkCategory(tm.db, userID, todo.CategoryID); err != nil {
+			logrus.Error("Model: Category Todo Tidak Valid ", err.Error())
+			return err
+		}
+		data.CategoryID = todo.CategoryID
+		if data.CategoryID == nil {
+			data.Category = Category{}
+		}
+		data.CategorySource = CategorySourceManual
+		data.CategoryConfidence = 0
+	}
+	data.RRule = todo.RRule
+	data.AutoComplete = todo.AutoComplete
+	data.RequireItemsDone = todo.RequireItemsDone
+	err = tm.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tags").Save(&data).Error; err != nil {
+			return err
+		}
+		if err := rescheduleReminders(tx, *data); err != nil {
+			return err
+		}
+		if todo.TagNames == nil {
+			return nil
+		}
+		tags, err := resolveTags(tx, userID, todo.TagNames)
+		if err != nil {
+			return err
+		}
+		return tx.Model(data).Association("Tags").Replace(tags)
+	})
+	if err != nil {
+		logrus.Error("Model: Error Update Todo ", err.Error())
+		return dbError(err, "update todo %d", id)
 	}
-	return true
+	return nil
 }
 
-func (tm *TodoModel) UpdateTodoStatus(id int, userID uint, status string) bool {
-	data := tm.GetTodo(id, userID)
-	if data == nil {
-		logrus.Error("Model: Error Update Todo")
-		return false
+// UpdateTodoCategory mengoreksi category todo, todo yang dikoreksi dianggap
+// dipilih manual sehingga menjadi data latih penuh untuk classifier
+func (tm *TodoModel) UpdateTodoCategory(id int, userID uint, categoryID uint) error {
+	if err := checkCategory(tm.db, userID, &categoryID); err != nil {
+		logrus.Error("Model: Category Todo Tidak Valid ", err.Error())
+		return err
 	}
-	data.Status = status
-	if err := tm.db.Save(&data).Error; err != nil {
+	res := tm.db.Model(&Todo{}).Where("id = ? AND user_id = ?", id, userID).Updates(map[string]any{
+		"category_id":         categoryID,
+		"category_source":     CategorySourceManual,
+		"category_confidence": 0,
+	})
+	if res.Error != nil {
+		logrus.Error("Model: Error Update Category Todo ", res.Error.Error())
+		return dbError(res.Error, "update todo %d category", id)
+	}
+	if res.RowsAffected == 0 {
+		logrus.Error("Model: Error Update Category Todo, Todo Tidak Ditemukan")
+		return newError(ErrNotFound, "todo %d not found", id)
+	}
+	return nil
+}
+
+func (tm *TodoModel) UpdateTodoStatus(id int, userID uint, status string) error {
+	data, err := tm.GetTodo(id, userID)
+	if err != nil {
 		logrus.Error("Model: Error Update Todo")
-		return false
+		return err
 	}
-	return true
+	workflow, err := loadWorkflow(tm.db, userID, categoryValue(data.CategoryID))
+	if err != nil {
+		logrus.Error("Model: Error Mendapatkan Workflow Todo ", err.Error())
+		return err
+	}
+	if status == StatusDone && data.RequireItemsDone && data.Progress.Done < data.Progress.Total {
+		logrus.Error("Model: Todo Masih Memiliki Item Yang Belum Selesai")
+		return ErrOpenItems
+	}
+	if data.RRule != "" {
+		return tm.updateOccurrenceStatus(data, workflow, status)
+	}
+	if err := workflow.checkTransition(data.Status, status); err != nil {
+		logrus.Error("Model: Transisi Status Todo Tidak Valid ", err.Error())
+		return err
+	}
+	now := time.Now()
+	err = tm.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Todo{}).Where("id = ?", data.ID).Updates(statusUpdates(data, status, now)).Error; err != nil {
+			return err
+		}
+		return tx.Create(&TodoStatusHistory{
+			TodoID:     data.ID,
+			UserID:     userID,
+			FromStatus: data.Status,
+			ToStatus:   status,
+			ChangedAt:  now,
+		}).Error
+	})
+	if err != nil {
+		logrus.Error("Model: Error Update Status Todo ", err.Error())
+		return dbError(err, "update todo %d status", id)
+	}
+	return nil
 }
 
-func (tm *TodoModel) DeleteTodo(id int, userID uint) bool {
+func (tm *TodoModel) DeleteTodo(id int, userID uint) error {
 	todo := Todo{}
-	data := tm.GetTodo(id, userID)
-	if data == nil {
+	if _, err := tm.GetTodo(id, userID); err != nil {
 		logrus.Error("Model: Error Delete Todo")
-		return false
+		return err
 	}
 	if err := tm.db.Where("user_id = ?", userID).Delete(&todo, id).Error; err != nil {
 		logrus.Error("Model: Error Delete Todo")
+		return dbError(err, "delete todo %d", id)
+	}
+	return nil
+}
+
+func (tm *TodoModel) UpdateOccurrence(id int, userID uint, date string, todo Todo) error {
+	occurrence, err := tm.findOccurrence(id, userID, date)
+	if err != nil {
+		logrus.Error("Model: Error Update Kejadian Todo")
+		return err
+	}
+	occurrence.Skipped = false
+	occurrence.Memo = todo.Memo
+	if !todo.DateTime.IsZero() {
+		dateTime := todo.DateTime
+		occurrence.DateTime = &dateTime
+	}
+	if err := tm.db.Save(occurrence).Error; err != nil {
+		logrus.Error("Model: Error Update Kejadian Todo ", err.Error())
+		return dbError(err, "update todo %d occurrence %s", id, date)
+	}
+	return nil
+}
+
+func (tm *TodoModel) SkipOccurrence(id int, userID uint, date string) error {
+	occurrence, err := tm.findOccurrence(id, userID, date)
+	if err != nil {
+		logrus.Error("Model: Error Skip Kejadian Todo")
+		return err
+	}
+	occurrence.Skipped = true
+	if err := tm.db.Save(occurrence).Error; err != nil {
+		logrus.Error("Model: Error Skip Kejadian Todo ", err.Error())
+		return dbError(err, "skip todo %d occurrence %s", id, date)
+	}
+	return nil
+}
+
+// findOccurrence mengembalikan pengecualian untuk tanggal kejadian tertentu,
+// atau pengecualian baru jika belum ada. Tanggal harus termasuk kejadian dari rule.
+func (tm *TodoModel) findOccurrence(id int, userID uint, date string) (*TodoOccurrence, error) {
+	data, err := tm.GetTodo(id, userID)
+	if err != nil {
+		logrus.Error("Model: Todo Berulang Tidak Ditemukan")
+		return nil, err
+	}
+	if data.RRule == "" {
+		logrus.Error("Model: Todo Bukan Todo Berulang")
+		return nil, newError(ErrValidation, "todo %d is not recurring", id)
+	}
+	rule, err := helper.ParseRRule(data.RRule)
+	if err != nil {
+		logrus.Error("Model: Aturan Pengulangan Todo Tidak Valid ", err.Error())
+		return nil, fmt.Errorf("todo %d rrule: %w", id, err)
+	}
+	day, err := time.ParseInLocation(occurrenceDateFormat, date, data.DateTime.Location())
+	if err != nil {
+		logrus.Error("Model: Format Tanggal Kejadian Tidak Valid ", err.Error())
+		return nil, newError(ErrValidation, "invalid occurrence date %q", date)
+	}
+	if len(rule.Between(data.DateTime, day, day.AddDate(0, 0, 1).Add(-time.Nanosecond))) == 0 {
+		logrus.Error("Model: Tanggal Bukan Kejadian Todo Berulang")
+		return nil, newError(ErrNotFound, "todo %d has no occurrence on %s", id, date)
+	}
+	occurrence := TodoOccurrence{}
+	if err := tm.db.Where("todo_id = ? AND date = ?", data.ID, date).FirstOrInit(&occurrence, TodoOccurrence{TodoID: data.ID, Date: date}).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Kejadian Todo ", err.Error())
+		return nil, dbError(err, "todo %d occurrence %s", id, date)
+	}
+	return &occurrence, nil
+}
+
+func (tm *TodoModel) GetStatusHistory(id int, userID uint) ([]TodoStatusHistory, error) {
+	history := []TodoStatusHistory{}
+	if err := tm.db.Where("user_id = ?", userID).First(&Todo{}, id).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Data Todo ", err.Error())
+		return nil, dbError(err, "todo %d", id)
+	}
+	if err := tm.db.Where("todo_id = ?", id).Order("changed_at, id").Find(&history).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Riwayat Status Todo ", err.Error())
+		return nil, dbError(err, "todo %d status history", id)
+	}
+	return history, nil
+}
+
+// SearchTodos mencari todo berdasarkan memo dan nama category memakai
+// pencarian full text dari dialect database, diurutkan dari yang paling relevan
+func (tm *TodoModel) SearchTodos(query string, page, content int, userID uint) ([]TodoSearchResult, int64, error) {
+	res := []TodoSearchResult{}
+	terms := helper.SearchTerms(query)
+	if len(terms) == 0 {
+		return res, 0, nil
+	}
+	dialect := DialectOf(tm.db)
+	memo := dialect.Search("todos.memo", terms)
+	category := dialect.Search("categories.category", terms)
+	args := append(append([]any{}, memo.Args...), category.Args...)
+	matches := func() *gorm.DB {
+		return tm.db.Model(&Todo{}).
+			Joins("LEFT JOIN categories ON categories.id = todos.category_id AND categories.deleted_at IS NULL").
+			Where("todos.user_id = ?", userID).
+			Where(memo.Match+" OR "+category.Match, args...)
+	}
+	var total int64
+	if err := matches().Count(&total).Error; err != nil {
+		logrus.Error("Model: Error Menghitung Data Todo Pencarian ", err.Error())
+		return nil, 0, dbError(err, "count search todos")
+	}
+	offset := (page - 1) * content
+	rows := []struct {
+		ID    uint
+		Score float64
+	}{}
+	err := matches().
+		Select("todos.id, "+memo.Score+" + COALESCE("+category.Score+", 0) AS score", args...).
+		Order("score DESC, todos.id DESC").
+		Limit(content).
+		Offset(offset).
+		Scan(&rows).Error
+	if err != nil {
+		logrus.Error("Model: Error Mencari Data Todo ", err.Error())
+		return nil, 0, dbError(err, "search todos")
+	}
+	if len(rows) == 0 {
+		return res, total, nil
+	}
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	todos := []Todo{}
+	if err := tm.db.Preload("Category").Preload("Tags").Where("id IN ?", ids).Find(&todos).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Data Todo Pencarian ", err.Error())
+		return nil, 0, dbError(err, "search todos")
+	}
+	byID := map[uint]Todo{}
+	for _, todo := range todos {
+		byID[todo.ID] = todo
+	}
+	for _, row := range rows {
+		todo, found := byID[row.ID]
+		if !found {
+			continue
+		}
+		result := TodoSearchResult{Todo: todo, Score: row.Score}
+		result.Snippet, _ = helper.Highlight(todo.Memo, terms, searchSnippetWidth)
+		if snippet, matched := helper.Highlight(todo.Category.Category, terms, searchSnippetWidth); matched {
+			result.CategorySnippet = snippet
+		}
+		res = append(res, result)
+	}
+	return res, total, nil
+}
+
+// updateOccurrenceStatus menerapkan status pada kejadian yang sedang berjalan.
+// Status Done atau Cancelled menutup kejadian tersebut sehingga kejadian
+// berikutnya menjadi aktif. Jika tidak ada kejadian tersisa, status todo induk
+// ikut diperbarui.
+func (tm *TodoModel) updateOccurrenceStatus(data *Todo, workflow *Workflow, status string) error {
+	rule, err := helper.ParseRRule(data.RRule)
+	if err != nil {
+		logrus.Error("Model: Aturan Pengulangan Todo Tidak Valid ", err.Error())
+		return fmt.Errorf("todo %d rrule: %w", data.ID, err)
+	}
+	overrides := tm.getOccurrences(data.ID)
+	current, found := nextPendingOccurrence(rule, *data, overrides)
+	if !found {
+		logrus.Error("Model: Tidak Ada Kejadian Todo Yang Tersisa")
+		return newError(ErrConflict, "todo %d has no pending occurrence", data.ID)
+	}
+	key := current.Format(occurrenceDateFormat)
+	occurrence, found := overrides[key]
+	if !found {
+		occurrence = TodoOccurrence{TodoID: data.ID, Date: key}
+	}
+	from := occurrence.Status
+	if from == "" {
+		from = data.Status
+	}
+	if err := workflow.checkTransition(from, status); err != nil {
+		logrus.Error("Model: Transisi Status Kejadian Todo Tidak Valid ", err.Error())
+		return err
+	}
+	now := time.Now()
+	err = tm.db.Transaction(func(tx *gorm.DB) error {
+		occurrence.Status = status
+		if err := tx.Save(&occurrence).Error; err != nil {
+			return err
+		}
+		history := TodoStatusHistory{
+			TodoID:         data.ID,
+			UserID:         data.UserID,
+			OccurrenceDate: key,
+			FromStatus:     from,
+			ToStatus:       status,
+			ChangedAt:      now,
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return err
+		}
+		// checklist dipakai ulang untuk kejadian berikutnya
+		if status == StatusDone {
+			if err := tx.Model(&TodoItem{}).Where("todo_id = ?", data.ID).Update("done", false).Error; err != nil {
+				return err
+			}
+		}
+		overrides[key] = occurrence
+		if _, found := nextPendingOccurrence(rule, *data, overrides); found {
+			if status == StatusInProgress && data.StartedAt == nil {
+				return tx.Model(&Todo{}).Where("id = ?", data.ID).Update("started_at", now).Error
+			}
+			return nil
+		}
+		return tx.Model(&Todo{}).Where("id = ?", data.ID).Updates(statusUpdates(data, status, now)).Error
+	})
+	if err != nil {
+		logrus.Error("Model: Error Update Status Kejadian Todo ", err.Error())
+		return dbError(err, "update todo %d occurrence status", data.ID)
+	}
+	return nil
+}
+
+// statusUpdates menyiapkan kolom yang berubah saat status todo berpindah
+func statusUpdates(data *Todo, status string, now time.Time) map[string]any {
+	updates := map[string]any{"status": status}
+	if status == StatusInProgress && data.StartedAt == nil {
+		updates["started_at"] = now
+	}
+	if isClosedStatus(status) {
+		updates["finished_at"] = now
+	} else {
+		updates["finished_at"] = nil
+	}
+	return updates
+}
+
+// getTodosInRange menggabungkan todo biasa di rentang [From, To] dengan
+// kejadian todo berulang di rentang yang sama, lalu mengurutkannya
+func (tm *TodoModel) getTodosInRange(userID uint, filter TodoFilter, now time.Time) ([]Todo, error) {
+	from, to := *filter.From, *filter.To
+	single := []Todo{}
+	query := tm.db.Preload("Tags").Scopes(filter.scope(tm.db, userID), filter.rowScope(now)).
+		Where("COALESCE(todos.rrule, '') = '' AND todos.date_time BETWEEN ? AND ?", from, to)
+	if err := query.Find(&single).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Data Todo Tanggal ", err.Error())
+		return nil, dbError(err, "list todos")
+	}
+	masters := []Todo{}
+	query = tm.db.Preload("Tags").Scopes(filter.scope(tm.db, userID)).
+		Where("COALESCE(todos.rrule, '') <> '' AND todos.date_time <= ?", to)
+	if err := query.Find(&masters).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Data Todo Berulang ", err.Error())
+		return nil, dbError(err, "list recurring todos")
+	}
+	res := single
+	for _, master := range masters {
+		for _, occurrence := range tm.expandOccurrences(master, from, to) {
+			if filter.matches(occurrence, now) {
+				res = append(res, occurrence)
+			}
+		}
+	}
+	filter.sortTodos(res)
+	return res, nil
+}
+
+// expandOccurrences menghasilkan salinan todo untuk setiap kejadian di rentang
+// [from, to] setelah pengecualian diterapkan
+func (tm *TodoModel) expandOccurrences(master Todo, from, to time.Time) []Todo {
+	res := []Todo{}
+	rule, err := helper.ParseRRule(master.RRule)
+	if err != nil {
+		logrus.Error("Model: Aturan Pengulangan Todo Tidak Valid ", err.Error())
+		return res
+	}
+	overrides := tm.getOccurrences(master.ID)
+	inRange := func(t time.Time) bool { return !t.Before(from) && !t.After(to) }
+	for _, original := range rule.Between(master.DateTime, from, to) {
+		if occurrence, found := applyOccurrence(master, original, overrides); found && inRange(occurrence.DateTime) {
+			res = append(res, occurrence)
+		}
+	}
+	// kejadian dari tanggal lain yang dipindahkan ke rentang ini
+	for _, override := range overrides {
+		if override.Skipped || override.DateTime == nil || !inRange(*override.DateTime) {
+			continue
+		}
+		day, err := time.ParseInLocation(occurrenceDateFormat, override.Date, master.DateTime.Location())
+		if err != nil {
+			continue
+		}
+		hour, minute, second := master.DateTime.Clock()
+		original := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, day.Location())
+		if inRange(original) {
+			continue
+		}
+		if occurrence, found := applyOccurrence(master, original, overrides); found {
+			res = append(res, occurrence)
+		}
+	}
+	return res
+}
+
+func (tm *TodoModel) getOccurrences(todoID uint) map[string]TodoOccurrence {
+	occurrences := []TodoOccurrence{}
+	if err := tm.db.Where("todo_id = ?", todoID).Find(&occurrences).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Kejadian Todo ", err.Error())
+	}
+	res := map[string]TodoOccurrence{}
+	for _, occurrence := range occurrences {
+		res[occurrence.Date] = occurrence
+	}
+	return res
+}
+
+func applyOccurrence(master Todo, original time.Time, overrides map[string]TodoOccurrence) (Todo, bool) {
+	occurrence := master
+	occurrence.DateTime = original
+	occurrence.OccurrenceDate = &original
+	occurrence.NextOccurrence = nil
+	occurrence.Exceptions = nil
+	if override, found := overrides[original.Format(occurrenceDateFormat)]; found {
+		if override.Skipped {
+			return occurrence, false
+		}
+		if override.Memo != "" {
+			occurrence.Memo = override.Memo
+		}
+		if override.DateTime != nil {
+			occurrence.DateTime = *override.DateTime
+		}
+		if override.Status != "" {
+			occurrence.Status = override.Status
+		}
+	}
+	return occurrence, true
+}
+
+// nextPendingOccurrence mencari kejadian paling awal yang belum selesai dan tidak dilewati
+func nextPendingOccurrence(rule *helper.RRule, master Todo, overrides map[string]TodoOccurrence) (time.Time, bool) {
+	var res time.Time
+	found := false
+	rule.Iterate(master.DateTime, func(t time.Time) bool {
+		override := overrides[t.Format(occurrenceDateFormat)]
+		if override.Skipped || isClosedStatus(override.Status) {
+			return true
+		}
+		res = t
+		found = true
 		return false
+	})
+	return res, found
+}
+
+func paginateTodos(todo []Todo, offset, content int) []Todo {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(todo) {
+		return []Todo{}
+	}
+	end := offset + content
+	if content <= 0 || end > len(todo) {
+		end = len(todo)
 	}
-	return true
+	return todo[offset:end]
 }
@@ -2,52 +2,306 @@ package model
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"mytodo/ai"
+	"strings"
 	"time"
+	"unicode/utf8"
 
-	"github.com/labstack/echo/v4"
-	"github.com/sashabaranov/go-openai"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type TodoAIInterface interface {
-	GetResponseAPI(c echo.Context, key string, todoAI TodoAI) (openai.ChatCompletionResponse, error)
+	SuggestTodos(ctx context.Context, todoAI TodoAI, userID uint) ([]TodoSuggestion, error)
+	SuggestTodosStream(ctx context.Context, todoAI TodoAI, userID uint, onDelta StreamFunc) ([]TodoSuggestion, error)
+	AcceptSuggestions(suggestions []TodoSuggestion, userID uint) []Todo
+	PlanTodos(ctx context.Context, todoAI TodoAI, userID uint, upcoming []Todo, categories []Category) (*TodoPlan, error)
+	AddThread(newThread TodoAIThread, userID uint) *TodoAIThread
+	GetThreads(page, perpage int, userID uint) ([]TodoAIThread, int64)
+	GetThread(id int, userID uint) *TodoAIThread
+	DeleteThread(id int, userID uint) error
+	SendMessage(ctx context.Context, threadID int, userID uint, content string) (*TodoAIMessage, error)
+	CategorizeTodo(ctx context.Context, memo string, useAI bool, userID uint) (*CategoryPrediction, error)
+	ReviewWeek(ctx context.Context, start time.Time, todos []Todo, useAI, refresh bool, userID uint) (*WeeklyReview, error)
+	BreakdownTodo(ctx context.Context, todo Todo, lang string, userID uint) (*TodoBreakdown, error)
+	AcceptBreakdown(todo Todo, steps []TodoStep, mode string, userID uint) *BreakdownResult
 }
 
 type TodoAI struct {
-	Todo string    `json:"todo" form:"todo"`
-	Time time.Time `json:"time" form:"time"`
+	Todo string    `json:"todo" form:"todo" query:"todo" validate:"max=1000"`
+	Time time.Time `json:"time" form:"time" query:"time"`
+}
+
+// TodoSuggestion adalah satu todo yang disarankan AI, Duration dalam menit
+// dan Category adalah nama category milik user (dibuat jika belum ada)
+type TodoSuggestion struct {
+	Memo     string    `json:"memo"`
+	DateTime time.Time `json:"date_time"`
+	Duration int       `json:"duration"`
+	Category string    `json:"category"`
+}
+
+// batas jumlah saran dan percobaan meminta ulang jika jawaban AI tidak valid
+const (
+	MaxTodoSuggestions    = 10
+	maxSuggestionAttempts = 3
+)
+
+var ErrInvalidAIOutput = errors.New("ai returned invalid output")
+
+// StreamFunc menerima potongan jawaban AI. Attempt bertambah saat jawaban
+// sebelumnya tidak valid dan diminta ulang, potongan lama harus dibuang.
+type StreamFunc func(attempt int, delta string) error
+
+// Validate memeriksa saran sebelum dikembalikan ke user atau disimpan sebagai todo
+func (s TodoSuggestion) Validate() error {
+	switch {
+	case strings.TrimSpace(s.Memo) == "":
+		return errors.New("memo is required")
+	case utf8.RuneCountInString(s.Memo) > 255:
+		return errors.New("memo must be at most 255 characters")
+	case s.DateTime.IsZero():
+		return errors.New("date_time is required")
+	case s.Duration < 0 || s.Duration > 24*60:
+		return errors.New("duration must be between 0 and 1440 minutes")
+	case strings.TrimSpace(s.Category) == "":
+		return errors.New("category is required")
+	case utf8.RuneCountInString(s.Category) > 255:
+		return errors.New("category must be at most 255 characters")
+	}
+	return nil
 }
 
 type TodoAIModel struct {
-	db *gorm.DB
+	db       *gorm.DB
+	provider ai.Provider
+	pricing  AIPricing
 }
 
-func (tm *TodoAIModel) InitTodo(db *gorm.DB) {
+func (tm *TodoAIModel) InitTodo(db *gorm.DB, provider ai.Provider) {
 	tm.db = db
+	tm.provider = provider
 }
 
-func NewTodoAIModel(db *gorm.DB) TodoAIInterface {
+func NewTodoAIModel(db *gorm.DB, provider ai.Provider, pricing AIPricing) TodoAIInterface {
 	return &TodoAIModel{
-		db: db,
-	}
-}
-
-func (tm *TodoAIModel) GetResponseAPI(c echo.Context, key string, todo TodoAI) (openai.ChatCompletionResponse, error) {
-	content := fmt.Sprintf("Data ini berdasarkan pengisian dari data json. Berikan rekomendasi kegiatan sesuai dengan kriteria dan waktu yang ditentukan. Jika salah satu inputan kosong tetap buatkan rekomendasi dengan inputan yang ada. Berikan jawaban terbaik. `{'kegiatan':%s, 'waktu':%s }`", todo.Todo, todo.Time)
-	client := openai.NewClient(key)
-
-	res, err := client.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: openai.GPT3Dot5Turbo,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: content,
-				},
-			},
-		},
-	)
-	return res, err
+		db:       db,
+		provider: provider,
+		pricing:  pricing,
+	}
+}
+
+// fitur AI yang dicatat di pemakaian
+const (
+	featureSuggest    = "suggest"
+	featurePlan       = "plan"
+	featureThread     = "thread"
+	featureSummary    = "summary"
+	featureCategorize = "categorize"
+	featureReview     = "review"
+	featureBreakdown  = "breakdown"
+)
+
+const suggestionPrompt = `Kamu membantu user menyusun todo list. Berikan rekomendasi kegiatan sesuai dengan kriteria dan waktu yang ditentukan. Jika salah satu inputan kosong tetap buatkan rekomendasi dengan inputan yang ada.
+Jawab HANYA dengan JSON tanpa teks lain, dengan bentuk:
+{"suggestions":[{"memo":string,"date_time":string RFC3339,"duration":integer menit,"category":string}]}
+Berikan 1 sampai %d saran, memo maksimal 255 karakter, duration 0 sampai 1440. Contoh:
+%s`
+
+// SuggestTodos meminta saran todo dalam bentuk JSON
+func (tm *TodoAIModel) SuggestTodos(ctx context.Context, todo TodoAI, userID uint) ([]TodoSuggestion, error) {
+	return tm.suggestTodos(ctx, todo, userID, nil)
+}
+
+// SuggestTodosStream sama seperti SuggestTodos dengan jawaban AI dikirim ke onDelta selama dibuat
+func (tm *TodoAIModel) SuggestTodosStream(ctx context.Context, todo TodoAI, userID uint, onDelta StreamFunc) ([]TodoSuggestion, error) {
+	return tm.suggestTodos(ctx, todo, userID, onDelta)
+}
+
+func (tm *TodoAIModel) suggestTodos(ctx context.Context, todo TodoAI, userID uint, onDelta StreamFunc) ([]TodoSuggestion, error) {
+	now := time.Now()
+	example := suggestionExample(todo, now)
+	input, _ := json.Marshal(map[string]string{
+		"kegiatan": todo.Todo,
+		"waktu":    formatAITime(todo.Time),
+		"sekarang": now.Format(time.RFC3339),
+	})
+	messages := []ai.Message{
+		{Role: ai.RoleSystem, Content: fmt.Sprintf(suggestionPrompt, MaxTodoSuggestions, example)},
+		{Role: ai.RoleUser, Content: string(input)},
+	}
+	suggestions := []TodoSuggestion{}
+	err := tm.completeJSON(ctx, userID, featureSuggest, ai.Request{Messages: messages}, onDelta, func(content string) error {
+		res, err := parseSuggestions(content)
+		suggestions = res
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// completeJSON meminta jawaban JSON dan memanggil parse untuk memeriksanya.
+// Jawaban yang tidak valid dikirim balik ke model beserta alasannya untuk
+// diperbaiki, sampai maxSuggestionAttempts kali. Jika onDelta diisi jawaban
+// diminta lewat stream. Setiap permintaan dicatat sebagai pemakaian userID,
+// hanya jawaban yang valid yang boleh disimpan di cache provider.
+func (tm *TodoAIModel) completeJSON(ctx context.Context, userID uint, feature string, req ai.Request, onDelta StreamFunc, parse func(content string) error) error {
+	if tm.provider == nil {
+		return ai.ErrDisabled
+	}
+	req.CacheScope = aiCacheScope(userID, feature)
+	for attempt := 1; attempt <= maxSuggestionAttempts; attempt++ {
+		var res ai.Response
+		var err error
+		start := time.Now()
+		if onDelta == nil {
+			res, err = tm.provider.Complete(ctx, req)
+		} else {
+			res, err = tm.provider.Stream(ctx, req, func(delta string) error {
+				return onDelta(attempt, delta)
+			})
+		}
+		if err != nil {
+			recordAIUsage(tm.db, tm.pricing, userID, feature, res, time.Since(start), err, nil)
+			return err
+		}
+		err = parse(res.Content)
+		recordAIUsage(tm.db, tm.pricing, userID, feature, res, time.Since(start), nil, err)
+		if err == nil {
+			res.Confirm()
+			return nil
+		}
+		logrus.Warn("Model: Jawaban AI Tidak Valid, Percobaan ", attempt, " ", err.Error())
+		req.Messages = append(req.Messages,
+			ai.Message{Role: ai.RoleAssistant, Content: res.Content},
+			ai.Message{Role: ai.RoleUser, Content: "Jawaban tidak valid: " + err.Error() + ". Ulangi dengan JSON yang sesuai bentuk di atas saja."},
+		)
+	}
+	return ErrInvalidAIOutput
+}
+
+// aiCacheScope membatasi cache jawaban AI ke satu user dan fitur
+func aiCacheScope(userID uint, feature string) string {
+	return fmt.Sprintf("%d/%s", userID, feature)
+}
+
+// AcceptSuggestions menyimpan saran yang dipilih user sebagai todo dalam
+// satu transaksi, category dicari berdasarkan nama dan dibuat jika belum ada
+func (tm *TodoAIModel) AcceptSuggestions(suggestions []TodoSuggestion, userID uint) []Todo {
+	todos := []Todo{}
+	err := tm.db.Transaction(func(tx *gorm.DB) error {
+		categories := map[string]uint{}
+		for _, suggestion := range suggestions {
+			name := strings.TrimSpace(suggestion.Category)
+			key := strings.ToLower(name)
+			if _, found := categories[key]; !found {
+				category := Category{}
+				if err := tx.Where("user_id = ? AND LOWER(category) = ?", userID, key).Attrs(Category{Category: name, UserID: userID}).FirstOrCreate(&category).Error; err != nil {
+					return err
+				}
+				categories[key] = category.ID
+			}
+			categoryID := categories[key]
+			todo := Todo{
+				Memo:       strings.TrimSpace(suggestion.Memo),
+				DateTime:   suggestion.DateTime,
+				Duration:   suggestion.Duration,
+				CategoryID: &categoryID,
+				UserID:     userID,
+				Status:     StatusTodo,
+			}
+			if err := tx.Create(&todo).Error; err != nil {
+				return err
+			}
+			todos = append(todos, todo)
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Error("Model: Error Menyimpan Saran Todo ", err.Error())
+		return nil
+	}
+	return todos
+}
+
+// parseSuggestions membaca jawaban AI, blok kode markdown di sekitar JSON diabaikan
+func parseSuggestions(content string) ([]TodoSuggestion, error) {
+	data := struct {
+		Suggestions []TodoSuggestion `json:"suggestions"`
+	}{}
+	if err := decodeAIJSON(content, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Suggestions) == 0 || len(data.Suggestions) > MaxTodoSuggestions {
+		return nil, fmt.Errorf("suggestions must contain 1 to %d items", MaxTodoSuggestions)
+	}
+	for i, suggestion := range data.Suggestions {
+		if err := suggestion.Validate(); err != nil {
+			return nil, fmt.Errorf("suggestions[%d]: %w", i, err)
+		}
+	}
+	return data.Suggestions, nil
+}
+
+// decodeAIJSON membaca jawaban JSON AI ke dest, blok kode markdown di sekitar
+// JSON diabaikan dan field yang tidak dikenal dianggap tidak valid
+func decodeAIJSON(content string, dest any) error {
+	decoder := json.NewDecoder(strings.NewReader(stripCodeFence(content)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dest); err != nil {
+		return fmt.Errorf("invalid json: %w", err)
+	}
+	return nil
+}
+
+func stripCodeFence(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "```") {
+		return content
+	}
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimPrefix(content, "json")
+	content = strings.TrimSuffix(strings.TrimSpace(content), "```")
+	return strings.TrimSpace(content)
+}
+
+// suggestionExample adalah contoh jawaban yang valid untuk input user
+func suggestionExample(todo TodoAI, now time.Time) string {
+	example, _ := json.Marshal(map[string][]TodoSuggestion{
+		"suggestions": {exampleSuggestion(todo, now)},
+	})
+	return string(example)
+}
+
+func exampleSuggestion(todo TodoAI, now time.Time) TodoSuggestion {
+	memo := truncateRunes(strings.TrimSpace(todo.Todo), 255)
+	if memo == "" {
+		memo = "Olahraga pagi"
+	}
+	dateTime := todo.Time
+	if dateTime.IsZero() {
+		dateTime = now.Truncate(time.Hour).Add(time.Hour)
+	}
+	return TodoSuggestion{Memo: memo, DateTime: dateTime, Duration: 60, Category: "Umum"}
+}
+
+// truncateRunes memotong s menjadi paling banyak n karakter tanpa memotong rune
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+func formatAITime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
 }
@@ -0,0 +1,179 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"mytodo/ai"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// aiTestDB membuka SQLite in-memory yang hanya berisi tabel pemakaian AI
+func aiTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&AIUsage{}))
+	return db
+}
+
+func TestTodoAIModel_SuggestTodos(t *testing.T) {
+	at := time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)
+	test := []struct {
+		name     string
+		replies  []string
+		memo     string
+		outcomes []string
+		wantErr  error
+	}{
+		{
+			name:     "Valid answer",
+			replies:  []string{`{"suggestions":[{"memo":"Jogging","date_time":"2026-10-19T07:00:00Z","duration":30,"category":"Olahraga"}]}`},
+			memo:     "Jogging",
+			outcomes: []string{OutcomeSuccess},
+		},
+		{
+			name: "Invalid answer is asked again",
+			replies: []string{
+				`Tentu, ini sarannya: jogging pagi`,
+				`{"suggestions":[{"memo":"Jogging","date_time":"2026-10-19T07:00:00Z","duration":2000,"category":"Olahraga"}]}`,
+				"```json\n{\"suggestions\":[{\"memo\":\"Jogging\",\"date_time\":\"2026-10-19T07:00:00Z\",\"duration\":30,\"category\":\"Olahraga\"}]}\n```",
+			},
+			memo:     "Jogging",
+			outcomes: []string{OutcomeInvalid, OutcomeInvalid, OutcomeSuccess},
+		},
+		{
+			name:     "Invalid answer after every attempt",
+			replies:  []string{`{"suggestions":[]}`, `{"suggestions":[]}`, `{"suggestions":[]}`},
+			outcomes: []string{OutcomeInvalid, OutcomeInvalid, OutcomeInvalid},
+			wantErr:  ErrInvalidAIOutput,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			db := aiTestDB(t)
+			requests := []ai.Request{}
+			provider := &ai.FakeProvider{Reply: func(req ai.Request) (string, error) {
+				requests = append(requests, req)
+				return tc.replies[len(requests)-1], nil
+			}}
+			model := NewTodoAIModel(db, provider, AIPricing{})
+
+			suggestions, err := model.SuggestTodos(context.Background(), TodoAI{Todo: "olahraga", Time: at}, 1)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, suggestions, 1)
+				require.Equal(t, tc.memo, suggestions[0].Memo)
+				require.True(t, at.Equal(suggestions[0].DateTime))
+			}
+			require.Len(t, requests, len(tc.replies))
+			// alasan jawaban tidak valid dikirim balik ke model
+			for i := 1; i < len(requests); i++ {
+				last := requests[i].Messages[len(requests[i].Messages)-1]
+				require.Equal(t, ai.RoleUser, last.Role)
+				require.True(t, strings.HasPrefix(last.Content, "Jawaban tidak valid: "), last.Content)
+			}
+
+			usages := []AIUsage{}
+			require.NoError(t, db.Order("id").Find(&usages).Error)
+			outcomes := []string{}
+			for _, usage := range usages {
+				require.Equal(t, featureSuggest, usage.Feature)
+				outcomes = append(outcomes, usage.Outcome)
+			}
+			require.Equal(t, tc.outcomes, outcomes)
+		})
+	}
+}
+
+func TestTodoAIModel_SuggestTodos_CachesOnlyValidAnswers(t *testing.T) {
+	valid := `{"suggestions":[{"memo":"Jogging","date_time":"2026-10-19T07:00:00Z","duration":30,"category":"Olahraga"}]}`
+	replies := []string{"bukan json", "bukan json", "bukan json", valid, valid}
+	calls := 0
+	fake := &ai.FakeProvider{Reply: func(req ai.Request) (string, error) {
+		calls++
+		return replies[calls-1], nil
+	}}
+	provider := ai.NewResilientProvider(fake, ai.ResilientConfig{CacheTTL: time.Minute, CacheSize: 10})
+	model := NewTodoAIModel(aiTestDB(t), provider, AIPricing{})
+	todo := TodoAI{Todo: "olahraga", Time: time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)}
+
+	_, err := model.SuggestTodos(context.Background(), todo, 1)
+	require.ErrorIs(t, err, ErrInvalidAIOutput)
+	// jawaban tidak valid tidak diulang dari cache
+	suggestions, err := model.SuggestTodos(context.Background(), todo, 1)
+	require.NoError(t, err)
+	require.Equal(t, "Jogging", suggestions[0].Memo)
+	require.Equal(t, 4, calls)
+
+	suggestions, err = model.SuggestTodos(context.Background(), todo, 1)
+	require.NoError(t, err)
+	require.Equal(t, "Jogging", suggestions[0].Memo)
+	require.Equal(t, 4, calls)
+
+	// user lain tidak memakai cache user pertama
+	_, err = model.SuggestTodos(context.Background(), todo, 2)
+	require.NoError(t, err)
+	require.Equal(t, 5, calls)
+}
+
+func TestTodoAIModel_SuggestTodos_FakeFollowsPromptExample(t *testing.T) {
+	model := NewTodoAIModel(aiTestDB(t), &ai.FakeProvider{}, AIPricing{})
+	suggestions, err := model.SuggestTodos(context.Background(), TodoAI{}, 1)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+	require.Equal(t, "Olahraga pagi", suggestions[0].Memo)
+}
+
+func TestTodoSuggestion_Validate(t *testing.T) {
+	at := time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)
+	// batas memo dan category dihitung per karakter seperti varchar(255)
+	suggestion := TodoSuggestion{Memo: strings.Repeat("é", 255), DateTime: at, Duration: 30, Category: strings.Repeat("ü", 255)}
+	require.NoError(t, suggestion.Validate())
+	suggestion.Memo += "é"
+	require.Error(t, suggestion.Validate())
+
+	example := exampleSuggestion(TodoAI{Todo: strings.Repeat("é", 300), Time: at}, at)
+	require.True(t, utf8.ValidString(example.Memo))
+	require.Equal(t, 255, utf8.RuneCountInString(example.Memo))
+	require.NoError(t, example.Validate())
+}
+
+func TestRecordAIUsage_TruncatesErrorByRune(t *testing.T) {
+	db := aiTestDB(t)
+	recordAIUsage(db, AIPricing{}, 1, featureSuggest, ai.Response{}, time.Second, errors.New(strings.Repeat("é", 300)), nil)
+
+	usage := AIUsage{}
+	require.NoError(t, db.First(&usage).Error)
+	require.Equal(t, OutcomeError, usage.Outcome)
+	require.True(t, utf8.ValidString(usage.Error))
+	require.Equal(t, 255, utf8.RuneCountInString(usage.Error))
+}
+
+func TestTodoAIModel_SummarizeThread_EmptySummary(t *testing.T) {
+	db := aiTestDB(t)
+	require.NoError(t, db.AutoMigrate(&TodoAIThread{}, &TodoAIMessage{}))
+	thread := TodoAIThread{UserID: 1, Title: "Rencana", Summary: "Ringkasan lama"}
+	require.NoError(t, db.Create(&thread).Error)
+	old := []TodoAIMessage{{ThreadID: thread.ID, Role: ai.RoleUser, Content: "Jadwalkan olahraga"}}
+	require.NoError(t, db.Create(&old).Error)
+	provider := &ai.FakeProvider{Reply: func(req ai.Request) (string, error) { return "  ", nil }}
+	model := NewTodoAIModel(db, provider, AIPricing{}).(*TodoAIModel)
+
+	err := model.summarizeThread(context.Background(), &thread, old)
+	require.ErrorIs(t, err, ErrInvalidAIOutput)
+	// pesan lama tetap masuk konteks karena belum diringkas
+	got := TodoAIThread{}
+	require.NoError(t, db.First(&got, thread.ID).Error)
+	require.Equal(t, "Ringkasan lama", got.Summary)
+	require.Zero(t, got.SummarizedUpTo)
+	require.Equal(t, "Ringkasan lama", thread.Summary)
+	require.Zero(t, thread.SummarizedUpTo)
+}
@@ -0,0 +1,284 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mytodo/ai"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// TodoAIThread adalah percakapan AI milik user. Pesan lama yang sudah keluar
+// dari jendela konteks diringkas ke Summary, SummarizedUpTo adalah id pesan
+// terakhir yang sudah masuk ringkasan.
+type TodoAIThread struct {
+	gorm.Model
+	UserID         uint            `json:"user_id" form:"-" gorm:"index"`
+	Title          string          `json:"title" form:"title" gorm:"type:varchar(255)"`
+	Summary        string          `json:"summary,omitempty" form:"-" gorm:"type:text"`
+	SummarizedUpTo uint            `json:"-" form:"-"`
+	Messages       []TodoAIMessage `json:"messages,omitempty" form:"-" gorm:"foreignKey:ThreadID"`
+}
+
+// TodoAIMessage adalah satu pesan di thread, Suggestions hanya diisi pada
+// jawaban assistant
+type TodoAIMessage struct {
+	gorm.Model
+	ThreadID    uint             `json:"thread_id" gorm:"index"`
+	Role        string           `json:"role" gorm:"type:varchar(20)"`
+	Content     string           `json:"content" gorm:"type:text"`
+	Suggestions []TodoSuggestion `json:"suggestions,omitempty" gorm:"serializer:json;type:text"`
+}
+
+var ErrThreadNotFound = errors.New("thread not found")
+
+// perkiraan token riwayat yang dikirim ke AI dan panjang judul otomatis
+const (
+	threadContextTokens = 3000
+	threadTitleLength   = 50
+)
+
+const threadPrompt = `Kamu membantu user menyusun dan mengubah todo list lewat percakapan. Pakai riwayat percakapan untuk memahami maksud user, misalnya "pindahkan ke sore" berarti mengubah saran sebelumnya.
+Jawab HANYA dengan JSON tanpa teks lain, dengan bentuk:
+{"reply":string,"suggestions":[{"memo":string,"date_time":string RFC3339,"duration":integer menit,"category":string}]}
+reply adalah jawaban singkat untuk user. suggestions berisi 0 sampai %d saran todo terbaru, memo maksimal 255 karakter, duration 0 sampai 1440.
+Waktu sekarang %s. Contoh:
+%s`
+
+const summaryPrompt = `Ringkas percakapan berikut antara user dan asisten todo list dalam maksimal 5 kalimat. Simpan detail penting seperti nama kegiatan, jadwal dan keputusan user.`
+
+func (tm *TodoAIModel) AddThread(newThread TodoAIThread, userID uint) *TodoAIThread {
+	thread := TodoAIThread{
+		UserID: userID,
+		Title:  strings.TrimSpace(newThread.Title),
+	}
+	if err := tm.db.Create(&thread).Error; err != nil {
+		logrus.Error("Model: Error Saat Input Thread AI ", err.Error())
+		return nil
+	}
+	return &thread
+}
+
+func (tm *TodoAIModel) GetThreads(page, perpage int, userID uint) ([]TodoAIThread, int64) {
+	threads := []TodoAIThread{}
+	var total int64
+	if err := tm.db.Model(&TodoAIThread{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		logrus.Error("Model: Error Menghitung Data Thread AI ", err.Error())
+		return nil, 0
+	}
+	offset := (page - 1) * perpage
+	if err := tm.db.Where("user_id = ?", userID).Order("updated_at DESC, id DESC").Limit(perpage).Offset(offset).Find(&threads).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Data Thread AI ", err.Error())
+		return nil, 0
+	}
+	return threads, total
+}
+
+func (tm *TodoAIModel) GetThread(id int, userID uint) *TodoAIThread {
+	thread := TodoAIThread{}
+	err := tm.db.Preload("Messages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("user_id = ?", userID).First(&thread, id).Error
+	if err != nil {
+		logrus.Error("Model: Thread AI Tidak Ditemukan ", err.Error())
+		return nil
+	}
+	return &thread
+}
+
+// DeleteThread menghapus thread beserta pesannya, ErrThreadNotFound jika
+// thread tidak ada atau milik user lain
+func (tm *TodoAIModel) DeleteThread(id int, userID uint) error {
+	err := tm.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ?", userID).Delete(&TodoAIThread{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrThreadNotFound
+		}
+		return tx.Where("thread_id = ?", id).Delete(&TodoAIMessage{}).Error
+	})
+	if err != nil {
+		logrus.Error("Model: Error Delete Thread AI ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// SendMessage mengirim pesan user beserta riwayat thread ke AI lalu menyimpan
+// pesan dan jawabannya. Pesan hanya disimpan jika AI berhasil menjawab.
+func (tm *TodoAIModel) SendMessage(ctx context.Context, threadID int, userID uint, content string) (*TodoAIMessage, error) {
+	thread := TodoAIThread{}
+	if err := tm.db.Where("user_id = ?", userID).First(&thread, threadID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrThreadNotFound
+		}
+		return nil, err
+	}
+	history := []TodoAIMessage{}
+	if err := tm.db.Where("thread_id = ? AND id > ?", thread.ID, thread.SummarizedUpTo).Order("id").Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	// pesan dari yang terbaru dimasukkan selama masih muat, sisanya diringkas
+	budget := threadContextTokens - estimateTokens(content) - estimateTokens(thread.Summary)
+	start := len(history)
+	for start > 0 && budget-estimateTokens(history[start-1].Content) >= 0 {
+		budget -= estimateTokens(history[start-1].Content)
+		start--
+	}
+	if start > 0 {
+		if err := tm.summarizeThread(ctx, &thread, history[:start]); err != nil {
+			return nil, err
+		}
+		history = history[start:]
+	}
+
+	now := time.Now()
+	example := threadExample(content, now)
+	system := fmt.Sprintf(threadPrompt, MaxTodoSuggestions, now.Format(time.RFC3339), example)
+	if thread.Summary != "" {
+		system += "\nRingkasan percakapan sebelumnya: " + thread.Summary
+	}
+	messages := []ai.Message{{Role: ai.RoleSystem, Content: system}}
+	for _, msg := range history {
+		messages = append(messages, ai.Message{Role: msg.Role, Content: msg.promptContent()})
+	}
+	messages = append(messages, ai.Message{Role: ai.RoleUser, Content: content})
+
+	reply := TodoAIMessage{}
+	err := tm.completeJSON(ctx, userID, featureThread, ai.Request{Messages: messages}, nil, func(content string) error {
+		res, err := parseThreadReply(content)
+		reply = res
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tm.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&TodoAIMessage{ThreadID: thread.ID, Role: ai.RoleUser, Content: content}).Error; err != nil {
+			return err
+		}
+		reply.ThreadID = thread.ID
+		reply.Role = ai.RoleAssistant
+		if err := tx.Create(&reply).Error; err != nil {
+			return err
+		}
+		if thread.Title == "" {
+			thread.Title = threadTitle(content)
+		}
+		return tx.Model(&thread).Updates(map[string]any{"title": thread.Title, "updated_at": time.Now()}).Error
+	})
+	if err != nil {
+		logrus.Error("Model: Error Menyimpan Pesan Thread AI ", err.Error())
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// summarizeThread menggabungkan ringkasan lama dengan pesan yang keluar dari
+// jendela konteks. Ringkasan kosong ditolak agar pesan lama tidak hilang dari
+// konteks, SummarizedUpTo hanya maju jika ringkasan tersimpan.
+func (tm *TodoAIModel) summarizeThread(ctx context.Context, thread *TodoAIThread, old []TodoAIMessage) error {
+	if tm.provider == nil {
+		return ai.ErrDisabled
+	}
+	transcript := strings.Builder{}
+	if thread.Summary != "" {
+		transcript.WriteString("Ringkasan sebelumnya: " + thread.Summary + "\n")
+	}
+	for _, msg := range old {
+		transcript.WriteString(msg.Role + ": " + msg.Content + "\n")
+	}
+	req := ai.Request{
+		Messages: []ai.Message{
+			{Role: ai.RoleSystem, Content: summaryPrompt},
+			{Role: ai.RoleUser, Content: transcript.String()},
+		},
+		CacheScope: aiCacheScope(thread.UserID, featureSummary),
+	}
+	start := time.Now()
+	res, err := tm.provider.Complete(ctx, req)
+	summary := strings.TrimSpace(res.Content)
+	var parseErr error
+	if err == nil && summary == "" {
+		parseErr = errors.New("summary is empty")
+	}
+	recordAIUsage(tm.db, tm.pricing, thread.UserID, featureSummary, res, time.Since(start), err, parseErr)
+	if err != nil {
+		return err
+	}
+	if parseErr != nil {
+		logrus.Warn("Model: Ringkasan Thread AI Kosong")
+		return fmt.Errorf("%w: %w", ErrInvalidAIOutput, parseErr)
+	}
+	res.Confirm()
+	thread.Summary = summary
+	thread.SummarizedUpTo = old[len(old)-1].ID
+	return tm.db.Model(thread).Updates(map[string]any{"summary": thread.Summary, "summarized_up_to": thread.SummarizedUpTo}).Error
+}
+
+// promptContent mengembalikan jawaban assistant dalam bentuk JSON yang sama dengan
+// yang diminta dari AI agar formatnya konsisten di riwayat
+func (m TodoAIMessage) promptContent() string {
+	if m.Role != ai.RoleAssistant {
+		return m.Content
+	}
+	suggestions := m.Suggestions
+	if suggestions == nil {
+		suggestions = []TodoSuggestion{}
+	}
+	res, _ := json.Marshal(map[string]any{"reply": m.Content, "suggestions": suggestions})
+	return string(res)
+}
+
+func parseThreadReply(content string) (TodoAIMessage, error) {
+	data := struct {
+		Reply       string           `json:"reply"`
+		Suggestions []TodoSuggestion `json:"suggestions"`
+	}{}
+	if err := decodeAIJSON(content, &data); err != nil {
+		return TodoAIMessage{}, err
+	}
+	if strings.TrimSpace(data.Reply) == "" {
+		return TodoAIMessage{}, errors.New("reply is required")
+	}
+	if len(data.Suggestions) > MaxTodoSuggestions {
+		return TodoAIMessage{}, fmt.Errorf("suggestions must contain at most %d items", MaxTodoSuggestions)
+	}
+	for i, suggestion := range data.Suggestions {
+		if err := suggestion.Validate(); err != nil {
+			return TodoAIMessage{}, fmt.Errorf("suggestions[%d]: %w", i, err)
+		}
+	}
+	return TodoAIMessage{Content: strings.TrimSpace(data.Reply), Suggestions: data.Suggestions}, nil
+}
+
+// threadExample adalah contoh jawaban yang valid untuk pesan user
+func threadExample(content string, now time.Time) string {
+	example, _ := json.Marshal(map[string]any{
+		"reply":       "Baik, berikut saran todo yang sudah disesuaikan.",
+		"suggestions": []TodoSuggestion{exampleSuggestion(TodoAI{Todo: threadTitle(content)}, now)},
+	})
+	return string(example)
+}
+
+func threadTitle(content string) string {
+	title := []rune(strings.Join(strings.Fields(content), " "))
+	if len(title) > threadTitleLength {
+		return string(title[:threadTitleLength]) + "…"
+	}
+	return string(title)
+}
+
+// estimateTokens memperkirakan token dari panjang teks, kira-kira 4 karakter per token
+func estimateTokens(s string) int {
+	return len([]rune(s))/4 + 1
+}
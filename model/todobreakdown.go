@@ -0,0 +1,207 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mytodo/ai"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// TodoStep adalah satu langkah hasil pemecahan todo, Duration dalam menit
+type TodoStep struct {
+	Order    int    `json:"order"`
+	Memo     string `json:"memo"`
+	Duration int    `json:"duration"`
+}
+
+type TodoBreakdown struct {
+	TodoID        uint       `json:"todo_id"`
+	Lang          string     `json:"lang"`
+	Steps         []TodoStep `json:"steps"`
+	TotalDuration int        `json:"total_duration"`
+}
+
+// BreakdownResult berisi item checklist atau child todo yang dibuat dari langkah
+type BreakdownResult struct {
+	Mode  string     `json:"mode"`
+	Items []TodoItem `json:"items,omitempty"`
+	Todos []Todo     `json:"todos,omitempty"`
+}
+
+// bahasa prompt dan cara menyimpan langkah
+const (
+	LangID = "id"
+	LangEN = "en"
+
+	BreakdownModeItems = "items"
+	BreakdownModeTodos = "todos"
+
+	MaxBreakdownSteps = 10
+)
+
+var breakdownPrompts = map[string]string{
+	LangID: `Kamu membantu user memecah todo besar menjadi langkah-langkah kecil yang berurutan. Tulis langkah dalam bahasa Indonesia.
+Jawab HANYA dengan JSON tanpa teks lain, dengan bentuk:
+{"steps":[{"order":integer mulai dari 1,"memo":string,"duration":integer perkiraan menit}]}
+Berikan 1 sampai %d langkah, memo maksimal 255 karakter, duration 1 sampai 1440. Contoh:
+%s`,
+	LangEN: `You help the user split a big todo into small ordered steps. Write the steps in English.
+Answer ONLY with JSON and no other text, in this shape:
+{"steps":[{"order":integer starting at 1,"memo":string,"duration":integer estimated minutes}]}
+Give 1 to %d steps, memo at most 255 characters, duration 1 to 1440. Example:
+%s`,
+}
+
+// contoh langkah untuk setiap bahasa, dipakai sebagai contoh jawaban AI
+var breakdownExampleSteps = map[string][]string{
+	LangID: {"Siapkan bahan untuk %s", "Kerjakan %s", "Periksa hasil %s"},
+	LangEN: {"Prepare for %s", "Work on %s", "Review %s"},
+}
+
+// ValidLang memeriksa bahasa prompt yang didukung
+func ValidLang(lang string) bool {
+	_, found := breakdownPrompts[lang]
+	return found
+}
+
+// Validate memeriksa langkah sebelum dikembalikan ke user atau disimpan
+func (s TodoStep) Validate() error {
+	switch {
+	case strings.TrimSpace(s.Memo) == "":
+		return errors.New("memo is required")
+	case utf8.RuneCountInString(s.Memo) > 255:
+		return errors.New("memo must be at most 255 characters")
+	case s.Duration < 1 || s.Duration > 24*60:
+		return errors.New("duration must be between 1 and 1440 minutes")
+	}
+	return nil
+}
+
+// BreakdownTodo meminta AI memecah memo todo menjadi langkah berurutan dalam bahasa lang
+func (tm *TodoAIModel) BreakdownTodo(ctx context.Context, todo Todo, lang string, userID uint) (*TodoBreakdown, error) {
+	prompt, found := breakdownPrompts[lang]
+	if !found {
+		return nil, fmt.Errorf("unsupported language %q", lang)
+	}
+	example := breakdownExample(todo, lang)
+	input, _ := json.Marshal(map[string]any{
+		"memo":     todo.Memo,
+		"duration": todo.Duration,
+		"category": todo.Category.Category,
+	})
+	messages := []ai.Message{
+		{Role: ai.RoleSystem, Content: fmt.Sprintf(prompt, MaxBreakdownSteps, example)},
+		{Role: ai.RoleUser, Content: string(input)},
+	}
+	breakdown := &TodoBreakdown{TodoID: todo.ID, Lang: lang}
+	err := tm.completeJSON(ctx, userID, featureBreakdown, ai.Request{Messages: messages}, nil, func(content string) error {
+		steps, err := parseBreakdown(content)
+		breakdown.Steps = steps
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range breakdown.Steps {
+		breakdown.TotalDuration += step.Duration
+	}
+	return breakdown, nil
+}
+
+// AcceptBreakdown menyimpan langkah sebagai item checklist todo atau sebagai
+// child todo yang dijadwalkan berurutan mulai dari jadwal todo induk
+func (tm *TodoAIModel) AcceptBreakdown(todo Todo, steps []TodoStep, mode string, userID uint) *BreakdownResult {
+	res := &BreakdownResult{Mode: mode}
+	err := tm.db.Transaction(func(tx *gorm.DB) error {
+		switch mode {
+		case BreakdownModeItems:
+			var last int
+			if err := tx.Model(&TodoItem{}).Where("todo_id = ?", todo.ID).Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+				return err
+			}
+			for i, step := range steps {
+				item := TodoItem{TodoID: todo.ID, Title: strings.TrimSpace(step.Memo), Duration: step.Duration, Position: last + i + 1}
+				if err := tx.Create(&item).Error; err != nil {
+					return err
+				}
+				res.Items = append(res.Items, item)
+			}
+		case BreakdownModeTodos:
+			start := todo.DateTime
+			for _, step := range steps {
+				child := Todo{
+					Memo:           strings.TrimSpace(step.Memo),
+					DateTime:       start,
+					Duration:       step.Duration,
+					Status:         StatusTodo,
+					CategoryID:     todo.CategoryID,
+					CategorySource: CategorySourceManual,
+					UserID:         userID,
+					ParentID:       &todo.ID,
+				}
+				if err := tx.Create(&child).Error; err != nil {
+					return err
+				}
+				res.Todos = append(res.Todos, child)
+				start = start.Add(time.Duration(step.Duration) * time.Minute)
+			}
+		default:
+			return fmt.Errorf("unknown breakdown mode %q", mode)
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Error("Model: Error Menyimpan Langkah Todo ", err.Error())
+		return nil
+	}
+	return res
+}
+
+// parseBreakdown membaca langkah dari AI, order harus berurutan mulai dari 1
+func parseBreakdown(content string) ([]TodoStep, error) {
+	data := struct {
+		Steps []TodoStep `json:"steps"`
+	}{}
+	if err := decodeAIJSON(content, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Steps) == 0 || len(data.Steps) > MaxBreakdownSteps {
+		return nil, fmt.Errorf("steps must contain 1 to %d items", MaxBreakdownSteps)
+	}
+	for i, step := range data.Steps {
+		if step.Order != i+1 {
+			return nil, fmt.Errorf("steps[%d] must have order %d", i, i+1)
+		}
+		if err := step.Validate(); err != nil {
+			return nil, fmt.Errorf("steps[%d]: %w", i, err)
+		}
+	}
+	return data.Steps, nil
+}
+
+// breakdownExample membagi Duration todo (atau 90 menit) ke tiga langkah
+func breakdownExample(todo Todo, lang string) string {
+	memo := truncateRunes(strings.TrimSpace(todo.Memo), 200)
+	total := todo.Duration
+	if total < 3 {
+		total = 90
+	}
+	templates := breakdownExampleSteps[lang]
+	steps := []TodoStep{}
+	for i, template := range templates {
+		duration := total / len(templates)
+		if i == len(templates)-1 {
+			duration = total - duration*(len(templates)-1)
+		}
+		steps = append(steps, TodoStep{Order: i + 1, Memo: fmt.Sprintf(template, memo), Duration: duration})
+	}
+	example, _ := json.Marshal(map[string][]TodoStep{"steps": steps})
+	return string(example)
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBreakdown(t *testing.T) {
+	test := []struct {
+		name    string
+		content string
+		steps   int
+		wantErr bool
+	}{
+		{
+			name:    "Valid steps",
+			content: `{"steps":[{"order":1,"memo":"Tulis naskah","duration":30},{"order":2,"memo":"Latihan","duration":15}]}`,
+			steps:   2,
+		},
+		{
+			name:    "Valid steps inside code fence",
+			content: "```json\n{\"steps\":[{\"order\":1,\"memo\":\"Tulis naskah\",\"duration\":30}]}\n```",
+			steps:   1,
+		},
+		{name: "Empty steps", content: `{"steps":[]}`, wantErr: true},
+		{name: "Order not sequential", content: `{"steps":[{"order":2,"memo":"Latihan","duration":15}]}`, wantErr: true},
+		{name: "Duration out of range", content: `{"steps":[{"order":1,"memo":"Latihan","duration":2000}]}`, wantErr: true},
+		{name: "Empty memo", content: `{"steps":[{"order":1,"memo":" ","duration":15}]}`, wantErr: true},
+		{name: "Unknown field", content: `{"steps":[{"order":1,"memo":"Latihan","duration":15,"priority":1}]}`, wantErr: true},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			steps, err := parseBreakdown(tc.content)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, steps, tc.steps)
+		})
+	}
+}
+
+func TestBreakdownExample(t *testing.T) {
+	for _, lang := range []string{LangID, LangEN} {
+		steps, err := parseBreakdown(breakdownExample(Todo{Memo: "Prepare sprint demo", Duration: 100}, lang))
+		require.NoError(t, err)
+		total := 0
+		for _, step := range steps {
+			total += step.Duration
+		}
+		require.Equal(t, 100, total)
+	}
+	steps, err := parseBreakdown(breakdownExample(Todo{Memo: "Prepare sprint demo"}, LangID))
+	require.NoError(t, err)
+	require.Equal(t, "Siapkan bahan untuk Prepare sprint demo", steps[0].Memo)
+	require.Equal(t, 30, steps[0].Duration)
+
+	// memo panjang dipotong per karakter, bukan per byte
+	steps, err = parseBreakdown(breakdownExample(Todo{Memo: strings.Repeat("é", 250)}, LangID))
+	require.NoError(t, err)
+	require.True(t, utf8.ValidString(steps[0].Memo))
+	require.Equal(t, "Siapkan bahan untuk "+strings.Repeat("é", 200), steps[0].Memo)
+}
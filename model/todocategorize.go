@@ -0,0 +1,210 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mytodo/ai"
+	"strings"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sumber category todo
+const (
+	CategorySourceManual = "manual"
+	CategorySourceAI     = "ai"
+	CategorySourceLocal  = "local"
+)
+
+// CategoryPrediction adalah category yang dipilih untuk memo beserta tingkat
+// keyakinan 0 sampai 1 dan sumbernya (ai atau local)
+type CategoryPrediction struct {
+	CategoryID uint    `json:"category_id"`
+	Category   string  `json:"category"`
+	Confidence float64 `json:"confidence"`
+	Source     string  `json:"source"`
+}
+
+var ErrNoCategory = errors.New("user has no category")
+
+// jumlah todo terakhir yang dipakai melatih classifier lokal dan bobot todo
+// yang category-nya dipilih otomatis dan belum dikoreksi user
+const (
+	maxCategorizeSamples = 1000
+	autoCategoryWeight   = 0.5
+)
+
+const categorizePrompt = `Kamu mengelompokkan todo ke salah satu category milik user. Pilih category yang paling cocok dengan memo.
+Jawab HANYA dengan JSON tanpa teks lain, dengan bentuk:
+{"category_id":integer id category dari daftar,"confidence":angka 0 sampai 1}
+Contoh:
+%s`
+
+// kata umum yang tidak membantu menentukan category
+var categorizeStopwords = map[string]bool{
+	"dan": true, "di": true, "ke": true, "dari": true, "yang": true, "untuk": true, "dengan": true, "pada": true,
+	"the": true, "and": true, "to": true, "of": true, "for": true, "in": true, "on": true, "at": true, "with": true,
+}
+
+// CategorizeTodo memilih category user yang paling cocok untuk memo. Jika
+// useAI true provider AI dipakai lebih dulu, saat AI mati atau gagal dipakai
+// classifier Naive Bayes lokal yang dilatih dari todo user sebelumnya.
+// ErrNoCategory dikembalikan jika user belum punya category.
+func (tm *TodoAIModel) CategorizeTodo(ctx context.Context, memo string, useAI bool, userID uint) (*CategoryPrediction, error) {
+	categories := []Category{}
+	if err := tm.db.Where("user_id = ?", userID).Order("id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		return nil, ErrNoCategory
+	}
+	samples := []Todo{}
+	err := tm.db.Select("memo", "category_id", "category_source").
+		Where("user_id = ? AND category_id <> 0", userID).
+		Order("id DESC").Limit(maxCategorizeSamples).Find(&samples).Error
+	if err != nil {
+		return nil, err
+	}
+	local := classifyCategory(memo, categories, samples)
+	if !useAI || tm.provider == nil || len(categories) == 1 {
+		return &local, nil
+	}
+
+	names := []map[string]any{}
+	for _, category := range categories {
+		names = append(names, map[string]any{"id": category.ID, "category": category.Category})
+	}
+	input, _ := json.Marshal(map[string]any{"memo": memo, "kategori": names})
+	example, _ := json.Marshal(map[string]any{"category_id": local.CategoryID, "confidence": local.Confidence})
+	messages := []ai.Message{
+		{Role: ai.RoleSystem, Content: fmt.Sprintf(categorizePrompt, example)},
+		{Role: ai.RoleUser, Content: string(input)},
+	}
+	prediction := CategoryPrediction{}
+	err = tm.completeJSON(ctx, userID, featureCategorize, ai.Request{Messages: messages}, nil, func(content string) error {
+		res, err := parseCategoryPrediction(content, categories)
+		prediction = res
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		logrus.Warn("Model: Kategori AI Gagal, Memakai Classifier Lokal ", err.Error())
+		return &local, nil
+	}
+	return &prediction, nil
+}
+
+func parseCategoryPrediction(content string, categories []Category) (CategoryPrediction, error) {
+	data := struct {
+		CategoryID uint    `json:"category_id"`
+		Confidence float64 `json:"confidence"`
+	}{}
+	if err := decodeAIJSON(content, &data); err != nil {
+		return CategoryPrediction{}, err
+	}
+	if data.Confidence < 0 || data.Confidence > 1 {
+		return CategoryPrediction{}, errors.New("confidence must be between 0 and 1")
+	}
+	for _, category := range categories {
+		if category.ID == data.CategoryID {
+			return CategoryPrediction{
+				CategoryID: category.ID,
+				Category:   category.Category,
+				Confidence: roundConfidence(data.Confidence),
+				Source:     CategorySourceAI,
+			}, nil
+		}
+	}
+	return CategoryPrediction{}, fmt.Errorf("category_id %d is not in the list", data.CategoryID)
+}
+
+// classifyCategory adalah multinomial Naive Bayes dengan Laplace smoothing.
+// Nama category ikut dihitung sebagai satu contoh agar user baru tetap
+// mendapat hasil dari kata kunci, todo yang category-nya otomatis dan belum
+// dikoreksi diberi bobot lebih kecil.
+func classifyCategory(memo string, categories []Category, samples []Todo) CategoryPrediction {
+	docs := map[uint]float64{}
+	words := map[uint]map[string]float64{}
+	totals := map[uint]float64{}
+	vocabulary := map[string]bool{}
+	learn := func(categoryID uint, text string, weight float64) {
+		if words[categoryID] == nil {
+			words[categoryID] = map[string]float64{}
+		}
+		docs[categoryID] += weight
+		for _, token := range categorizeTokens(text) {
+			words[categoryID][token] += weight
+			totals[categoryID] += weight
+			vocabulary[token] = true
+		}
+	}
+	known := map[uint]bool{}
+	for _, category := range categories {
+		known[category.ID] = true
+		learn(category.ID, category.Category, 1)
+	}
+	var n float64
+	for _, sample := range samples {
+		categoryID := categoryValue(sample.CategoryID)
+		if !known[categoryID] {
+			continue
+		}
+		weight := 1.0
+		if sample.CategorySource != "" && sample.CategorySource != CategorySourceManual {
+			weight = autoCategoryWeight
+		}
+		learn(categoryID, sample.Memo, weight)
+	}
+	for _, weight := range docs {
+		n += weight
+	}
+
+	tokens := categorizeTokens(memo)
+	scores := make([]float64, len(categories))
+	best := 0
+	for i, category := range categories {
+		score := math.Log(docs[category.ID] / n)
+		for _, token := range tokens {
+			score += math.Log((words[category.ID][token] + 1) / (totals[category.ID] + float64(len(vocabulary))))
+		}
+		scores[i] = score
+		if score > scores[best] {
+			best = i
+		}
+	}
+	// confidence adalah probabilitas posterior category terpilih
+	var sum float64
+	for _, score := range scores {
+		sum += math.Exp(score - scores[best])
+	}
+	return CategoryPrediction{
+		CategoryID: categories[best].ID,
+		Category:   categories[best].Category,
+		Confidence: roundConfidence(1 / sum),
+		Source:     CategorySourceLocal,
+	}
+}
+
+func categorizeTokens(text string) []string {
+	tokens := []string{}
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, field := range fields {
+		if len([]rune(field)) < 2 || categorizeStopwords[field] {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+func roundConfidence(confidence float64) float64 {
+	return math.Round(confidence*1000) / 1000
+}
@@ -0,0 +1,167 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TodoFilter menampung semua filter dan urutan untuk GetTodos. Semua filter
+// digabung (AND) dalam satu query. Jika From dan To sama-sama diisi, todo
+// berulang dipecah menjadi kejadian di rentang tersebut.
+type TodoFilter struct {
+	Status      string
+	CategoryID  uint
+	From        *time.Time
+	To          *time.Time
+	Overdue     bool
+	Tags        []string
+	TagMatchAll bool
+	Text        string
+	// Sort berisi nama field, awalan "-" berarti urutan menurun
+	Sort []string
+	// Cursor mengaktifkan mode cursor: todo diurutkan dari id terbesar dan
+	// hanya todo dengan id lebih kecil dari BeforeID yang diambil
+	Cursor   bool
+	BeforeID uint
+}
+
+// TodoSortFields adalah field yang boleh dipakai pada parameter sort
+var TodoSortFields = []string{"id", "date_time", "created_at", "updated_at", "status", "memo"}
+
+var todoDefaultSort = []string{"date_time"}
+
+// ParseTodoSort memecah nilai seperti "date_time,-created_at" dan menolak field yang tidak dikenal
+func ParseTodoSort(value string) ([]string, error) {
+	var res []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !isTodoSortField(strings.TrimPrefix(field, "-")) {
+			return nil, fmt.Errorf("unknown sort field %s, allowed: %s", strings.TrimPrefix(field, "-"), strings.Join(TodoSortFields, ", "))
+		}
+		res = append(res, field)
+	}
+	return res, nil
+}
+
+func isTodoSortField(field string) bool {
+	for _, known := range TodoSortFields {
+		if known == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (f TodoFilter) sortFields() []string {
+	if len(f.Sort) == 0 {
+		return todoDefaultSort
+	}
+	return f.Sort
+}
+
+// scope menerapkan filter yang berlaku untuk baris todo maupun todo berulang induk
+func (f TodoFilter) scope(db *gorm.DB, userID uint) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		query = query.Where("todos.user_id = ?", userID)
+		if f.CategoryID != 0 {
+			query = query.Where("todos.category_id = ?", f.CategoryID)
+		}
+		if f.Text != "" {
+			contains := DialectOf(db).Contains("todos.memo", f.Text)
+			query = query.Where(contains.SQL, contains.Args...)
+		}
+		return query.Scopes(tagFilter(db, userID, f.Tags, f.TagMatchAll))
+	}
+}
+
+// rowScope menerapkan filter status dan overdue langsung pada kolom todo
+func (f TodoFilter) rowScope(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		if f.Status != "" {
+			query = query.Where("todos.status = ?", f.Status)
+		}
+		if f.Overdue {
+			query = query.Where("todos.date_time < ? AND todos.status NOT IN ?", now, []string{StatusDone, StatusCancelled})
+		}
+		return query
+	}
+}
+
+// matches adalah padanan rowScope untuk kejadian todo berulang yang dihitung di memori
+func (f TodoFilter) matches(todo Todo, now time.Time) bool {
+	if f.Status != "" && todo.Status != f.Status {
+		return false
+	}
+	if f.Overdue && (!todo.DateTime.Before(now) || isClosedStatus(todo.Status)) {
+		return false
+	}
+	return true
+}
+
+func (f TodoFilter) orderBy() string {
+	parts := []string{}
+	for _, field := range f.sortFields() {
+		if name, desc := strings.CutPrefix(field, "-"); desc {
+			parts = append(parts, "todos."+name+" DESC")
+		} else {
+			parts = append(parts, "todos."+name+" ASC")
+		}
+	}
+	return strings.Join(append(parts, "todos.id ASC"), ", ")
+}
+
+// sortTodos mengurutkan hasil di memori dengan aturan yang sama seperti orderBy
+func (f TodoFilter) sortTodos(todo []Todo) {
+	fields := f.sortFields()
+	sort.SliceStable(todo, func(i, j int) bool {
+		for _, field := range fields {
+			name, desc := strings.CutPrefix(field, "-")
+			cmp := compareTodoField(todo[i], todo[j], name)
+			if cmp == 0 {
+				continue
+			}
+			return (cmp < 0) != desc
+		}
+		return todo[i].ID < todo[j].ID
+	})
+}
+
+func compareTodoField(a, b Todo, field string) int {
+	switch field {
+	case "id":
+		return compareUint(a.ID, b.ID)
+	case "date_time":
+		return a.DateTime.Compare(b.DateTime)
+	case "created_at":
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case "updated_at":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	case "status":
+		return strings.Compare(a.Status, b.Status)
+	case "memo":
+		return strings.Compare(a.Memo, b.Memo)
+	}
+	return 0
+}
+
+func compareUint(a, b uint) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// escapeLike memakai "!" sebagai karakter escape karena backslash diperlakukan berbeda antar database
+func escapeLike(value string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+}
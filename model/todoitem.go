@@ -0,0 +1,180 @@
+package model
+
+import (
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TodoItemInterface interface {
+	AddItem(newItem TodoItem, userID uint) (*TodoItem, error)
+	GetItems(todoID int, userID uint) ([]TodoItem, error)
+	UpdateItem(id int, todoID int, userID uint, item TodoItemUpdate) error
+	DeleteItem(id int, todoID int, userID uint) error
+}
+
+// TodoItem adalah item checklist di bawah sebuah Todo
+type TodoItem struct {
+	gorm.Model
+	TodoID   uint   `json:"todo_id" form:"todo_id" gorm:"index"`
+	Title    string `json:"title" form:"title" gorm:"type:varchar(255)"`
+	Position int    `json:"position" form:"position"`
+	Done     bool   `json:"done" form:"done"`
+	// Duration adalah perkiraan menit, diisi dari pemecahan todo
+	Duration int `json:"duration" form:"duration"`
+}
+
+// TodoItemUpdate adalah data update item, field yang tidak dikirim tidak diubah
+type TodoItemUpdate struct {
+	Title    string `json:"title" form:"title"`
+	Position int    `json:"position" form:"position"`
+	Done     *bool  `json:"done" form:"done"`
+	Duration int    `json:"duration" form:"duration"`
+}
+
+type TodoProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+type TodoItemModel struct {
+	db *gorm.DB
+}
+
+func (im *TodoItemModel) InitTodoItem(db *gorm.DB) {
+	im.db = db
+}
+
+func NewTodoItemModel(db *gorm.DB) TodoItemInterface {
+	return &TodoItemModel{
+		db: db,
+	}
+}
+
+func (im *TodoItemModel) AddItem(newItem TodoItem, userID uint) (*TodoItem, error) {
+	if _, err := im.getTodo(int(newItem.TodoID), userID); err != nil {
+		return nil, err
+	}
+	if newItem.Position == 0 {
+		var last int
+		if err := im.db.Model(&TodoItem{}).Where("todo_id = ?", newItem.TodoID).Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+			logrus.Error("Model: Error Mendapatkan Posisi Item Todo ", err.Error())
+			return nil, dbError(err, "todo %d item position", newItem.TodoID)
+		}
+		newItem.Position = last + 1
+	}
+	if err := im.db.Create(&newItem).Error; err != nil {
+		logrus.Error("Model: Error Saat Input Item Todo ", err.Error())
+		return nil, dbError(err, "todo item")
+	}
+	return &newItem, nil
+}
+
+func (im *TodoItemModel) GetItems(todoID int, userID uint) ([]TodoItem, error) {
+	if _, err := im.getTodo(todoID, userID); err != nil {
+		return nil, err
+	}
+	items := []TodoItem{}
+	if err := im.db.Where("todo_id = ?", todoID).Order("position, id").Find(&items).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Data Item Todo ", err.Error())
+		return nil, dbError(err, "todo %d items", todoID)
+	}
+	return items, nil
+}
+
+// UpdateItem menyimpan perubahan item, jika todo memakai AutoComplete dan
+// semua item sudah selesai status todo ikut diubah menjadi Done dalam
+// transaksi yang sama. Transisi yang ditolak workflow membatalkan update item.
+func (im *TodoItemModel) UpdateItem(id int, todoID int, userID uint, itemUp TodoItemUpdate) error {
+	todo, err := im.getTodo(todoID, userID)
+	if err != nil {
+		return err
+	}
+	item := TodoItem{}
+	if err := im.db.Where("todo_id = ?", todoID).First(&item, id).Error; err != nil {
+		logrus.Error("Model: Item Todo Tidak Ditemukan ", err.Error())
+		return dbError(err, "todo %d item %d", todoID, id)
+	}
+	if itemUp.Title != "" {
+		item.Title = itemUp.Title
+	}
+	if itemUp.Position != 0 {
+		item.Position = itemUp.Position
+	}
+	if itemUp.Duration != 0 {
+		item.Duration = itemUp.Duration
+	}
+	if itemUp.Done != nil {
+		item.Done = *itemUp.Done
+	}
+	return im.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&item).Error; err != nil {
+			logrus.Error("Model: Error Update Item Todo ", err.Error())
+			return dbError(err, "update todo %d item %d", todoID, id)
+		}
+		if !item.Done || !todo.AutoComplete || todo.Status == StatusDone {
+			return nil
+		}
+		progress := todoProgress(tx, []uint{todo.ID})[todo.ID]
+		if progress.Done < progress.Total {
+			return nil
+		}
+		todoModel := TodoModel{db: tx}
+		if err := todoModel.UpdateTodoStatus(todoID, userID, StatusDone); err != nil {
+			logrus.Error("Model: Error Auto Complete Todo ", err.Error())
+			return err
+		}
+		return nil
+	})
+}
+
+func (im *TodoItemModel) DeleteItem(id int, todoID int, userID uint) error {
+	if _, err := im.getTodo(todoID, userID); err != nil {
+		return err
+	}
+	res := im.db.Where("todo_id = ?", todoID).Delete(&TodoItem{}, id)
+	if res.Error != nil {
+		logrus.Error("Model: Error Delete Item Todo ", res.Error.Error())
+		return dbError(res.Error, "delete todo %d item %d", todoID, id)
+	}
+	if res.RowsAffected == 0 {
+		logrus.Error("Model: Item Todo Tidak Ditemukan")
+		return newError(ErrNotFound, "todo %d item %d not found", todoID, id)
+	}
+	return nil
+}
+
+// getTodo mengembalikan ErrNotFound juga untuk todo milik user lain
+func (im *TodoItemModel) getTodo(todoID int, userID uint) (*Todo, error) {
+	todo := Todo{}
+	if err := im.db.Where("user_id = ?", userID).First(&todo, todoID).Error; err != nil {
+		logrus.Error("Model: Todo Item Tidak Ditemukan ", err.Error())
+		return nil, dbError(err, "todo %d", todoID)
+	}
+	return &todo, nil
+}
+
+// todoProgress menghitung jumlah item selesai dan total item untuk setiap todo
+func todoProgress(db *gorm.DB, todoIDs []uint) map[uint]TodoProgress {
+	res := map[uint]TodoProgress{}
+	if len(todoIDs) == 0 {
+		return res
+	}
+	rows := []struct {
+		TodoID uint
+		Done   int
+		Total  int
+	}{}
+	err := db.Model(&TodoItem{}).
+		Select("todo_id, SUM(CASE WHEN done THEN 1 ELSE 0 END) AS done, COUNT(*) AS total").
+		Where("todo_id IN ?", todoIDs).
+		Group("todo_id").
+		Scan(&rows).Error
+	if err != nil {
+		logrus.Error("Model: Error Menghitung Progress Todo ", err.Error())
+		return res
+	}
+	for _, row := range rows {
+		res[row.TodoID] = TodoProgress{Done: row.Done, Total: row.Total}
+	}
+	return res
+}
@@ -0,0 +1,200 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mytodo/ai"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TodoPlan adalah jadwal yang disusun AI dari todo user yang sudah ada.
+// Conflicts dihitung server, berisi todo yang menempati waktu yang diminta
+// sehingga saran digeser.
+type TodoPlan struct {
+	Suggestions []TodoSuggestion `json:"suggestions"`
+	Conflicts   []PlanConflict   `json:"conflicts"`
+}
+
+type PlanConflict struct {
+	TodoID   uint      `json:"todo_id"`
+	Memo     string    `json:"memo"`
+	DateTime time.Time `json:"date_time"`
+	Reason   string    `json:"reason"`
+}
+
+// durasi yang dianggap untuk todo tanpa Duration dan jumlah todo yang dikirim ke AI
+const (
+	defaultPlanDuration = 30
+	maxPlanContextTodos = 50
+)
+
+const planPrompt = `Kamu membantu user menjadwalkan kegiatan baru di antara todo yang sudah ada. Berikan rekomendasi jadwal sesuai kriteria dan waktu yang diminta.
+Jadwal saran TIDAK BOLEH bertabrakan dengan todo yang sudah ada (date_time sampai date_time + duration menit). Gunakan nama category yang sudah ada jika cocok.
+Jawab HANYA dengan JSON tanpa teks lain, dengan bentuk:
+{"suggestions":[{"memo":string,"date_time":string RFC3339,"duration":integer menit,"category":string}]}
+Berikan 1 sampai %d saran, memo maksimal 255 karakter, duration 0 sampai 1440. Contoh:
+%s`
+
+// PlanTodos menyusun saran todo yang tidak bertabrakan dengan upcoming.
+// Saran yang masih bertabrakan dianggap jawaban tidak valid dan diminta ulang,
+// todo yang dilewati saran dicatat sebagai conflicts.
+func (tm *TodoAIModel) PlanTodos(ctx context.Context, todo TodoAI, userID uint, upcoming []Todo, categories []Category) (*TodoPlan, error) {
+	now := time.Now()
+	busy := busyTodos(upcoming)
+	// saran hanya diperiksa terhadap todo yang ikut dikirim ke model
+	if len(busy) > maxPlanContextTodos {
+		busy = busy[:maxPlanContextTodos]
+	}
+	example := planExample(todo, busy, categories, now)
+
+	existing := []map[string]any{}
+	for _, t := range busy {
+		existing = append(existing, map[string]any{
+			"todo_id":   t.ID,
+			"memo":      t.Memo,
+			"date_time": t.DateTime.Format(time.RFC3339),
+			"duration":  planDuration(t.Duration),
+			"category":  t.Category.Category,
+		})
+	}
+	names := []string{}
+	for _, category := range categories {
+		names = append(names, category.Category)
+	}
+	input, _ := json.Marshal(map[string]any{
+		"kegiatan": todo.Todo,
+		"waktu":    formatAITime(todo.Time),
+		"sekarang": now.Format(time.RFC3339),
+		"jadwal":   existing,
+		"kategori": names,
+	})
+	messages := []ai.Message{
+		{Role: ai.RoleSystem, Content: fmt.Sprintf(planPrompt, MaxTodoSuggestions, example)},
+		{Role: ai.RoleUser, Content: string(input)},
+	}
+	plan := &TodoPlan{}
+	err := tm.completeJSON(ctx, userID, featurePlan, ai.Request{Messages: messages}, nil, func(content string) error {
+		res, err := parsePlan(content, busy)
+		plan.Suggestions = res
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.Conflicts = planConflicts(todo.Time, plan.Suggestions, busy)
+	return plan, nil
+}
+
+func parsePlan(content string, busy []Todo) ([]TodoSuggestion, error) {
+	plan := struct {
+		Suggestions []TodoSuggestion `json:"suggestions"`
+	}{}
+	if err := decodeAIJSON(content, &plan); err != nil {
+		return nil, err
+	}
+	if len(plan.Suggestions) == 0 || len(plan.Suggestions) > MaxTodoSuggestions {
+		return nil, fmt.Errorf("suggestions must contain 1 to %d items", MaxTodoSuggestions)
+	}
+	for i, suggestion := range plan.Suggestions {
+		if err := suggestion.Validate(); err != nil {
+			return nil, fmt.Errorf("suggestions[%d]: %w", i, err)
+		}
+		if t, found := overlappingTodo(suggestion.DateTime, suggestion.Duration, busy); found {
+			return nil, fmt.Errorf("suggestions[%d] overlaps todo %d %q at %s", i, t.ID, t.Memo, t.DateTime.Format(time.RFC3339))
+		}
+	}
+	return plan.Suggestions, nil
+}
+
+// planConflicts mencari todo yang beririsan dengan waktu yang dilewati setiap
+// saran, yaitu dari waktu yang diminta sampai jadwal saran. Jika saran tidak
+// digeser ke belakang, yang diperiksa adalah slot waktu yang diminta.
+func planConflicts(requested time.Time, suggestions []TodoSuggestion, busy []Todo) []PlanConflict {
+	res := []PlanConflict{}
+	if requested.IsZero() {
+		return res
+	}
+	found := map[uint]bool{}
+	for _, suggestion := range suggestions {
+		end := suggestion.DateTime
+		if !end.After(requested) {
+			end = requested.Add(time.Duration(planDuration(suggestion.Duration)) * time.Minute)
+		}
+		for _, t := range busy {
+			tEnd := t.DateTime.Add(time.Duration(planDuration(t.Duration)) * time.Minute)
+			if found[t.ID] || !requested.Before(tEnd) || !t.DateTime.Before(end) {
+				continue
+			}
+			found[t.ID] = true
+			res = append(res, PlanConflict{
+				TodoID:   t.ID,
+				Memo:     t.Memo,
+				DateTime: t.DateTime,
+				Reason:   "Jadwal bertabrakan dengan todo ini, saran digeser",
+			})
+		}
+	}
+	return res
+}
+
+// busyTodos mengambil todo yang masih memakai waktu user, urut berdasarkan jadwal
+func busyTodos(todos []Todo) []Todo {
+	res := []Todo{}
+	for _, t := range todos {
+		if t.Status == StatusDone || t.Status == StatusCancelled {
+			continue
+		}
+		res = append(res, t)
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].DateTime.Before(res[j].DateTime) })
+	return res
+}
+
+func planDuration(minutes int) int {
+	if minutes <= 0 {
+		return defaultPlanDuration
+	}
+	return minutes
+}
+
+// overlappingTodo mencari todo yang jadwalnya beririsan dengan start sampai start + duration
+func overlappingTodo(start time.Time, duration int, busy []Todo) (Todo, bool) {
+	end := start.Add(time.Duration(planDuration(duration)) * time.Minute)
+	for _, t := range busy {
+		tEnd := t.DateTime.Add(time.Duration(planDuration(t.Duration)) * time.Minute)
+		if start.Before(tEnd) && t.DateTime.Before(end) {
+			return t, true
+		}
+	}
+	return Todo{}, false
+}
+
+// planExample adalah contoh jawaban yang valid, memakai slot kosong pertama
+// mulai dari waktu yang diminta dengan langkah 30 menit
+func planExample(todo TodoAI, busy []Todo, categories []Category, now time.Time) string {
+	memo := truncateRunes(strings.TrimSpace(todo.Todo), 255)
+	if memo == "" {
+		memo = "Olahraga pagi"
+	}
+	start := todo.Time
+	if start.IsZero() {
+		start = now.Truncate(time.Hour).Add(time.Hour)
+	}
+	for {
+		if _, found := overlappingTodo(start, 60, busy); !found {
+			break
+		}
+		start = start.Add(defaultPlanDuration * time.Minute)
+	}
+	category := "Umum"
+	if len(categories) > 0 {
+		category = categories[0].Category
+	}
+	example, _ := json.Marshal(map[string][]TodoSuggestion{
+		"suggestions": {{Memo: memo, DateTime: start, Duration: 60, Category: category}},
+	})
+	return string(example)
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"mytodo/ai"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTodoAIModel_PlanTodos(t *testing.T) {
+	at := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	todo := func(id uint, memo string, dateTime time.Time, duration int, status string) Todo {
+		res := Todo{Memo: memo, DateTime: dateTime, Duration: duration, Status: status}
+		res.ID = id
+		return res
+	}
+	upcoming := []Todo{
+		todo(1, "Rapat tim", at, 60, StatusTodo),
+		todo(2, "Review kode", at.Add(time.Hour), 30, StatusInProgress),
+		todo(3, "Sudah selesai", at.Add(2*time.Hour), 60, StatusDone),
+		todo(4, "Makan siang", at.Add(3*time.Hour), 60, StatusTodo),
+	}
+	test := []struct {
+		name      string
+		reply     string
+		requested time.Time
+		start     time.Time
+		conflicts []uint
+	}{
+		{
+			name:      "Suggestion moved after busy todos",
+			reply:     `{"suggestions":[{"memo":"Olahraga","date_time":"2026-10-19T11:00:00Z","duration":60,"category":"Umum"}]}`,
+			requested: at,
+			start:     at.Add(2 * time.Hour),
+			conflicts: []uint{1, 2},
+		},
+		{
+			name:      "Suggestion moved before requested slot",
+			reply:     `{"suggestions":[{"memo":"Olahraga","date_time":"2026-10-19T07:30:00Z","duration":60,"category":"Umum"}]}`,
+			requested: at.Add(3 * time.Hour),
+			start:     at.Add(-90 * time.Minute),
+			conflicts: []uint{4},
+		},
+		{
+			name:      "Requested slot is free",
+			reply:     `{"suggestions":[{"memo":"Olahraga","date_time":"2026-10-19T11:00:00Z","duration":60,"category":"Umum"}]}`,
+			requested: at.Add(2 * time.Hour),
+			start:     at.Add(2 * time.Hour),
+			conflicts: []uint{},
+		},
+		{
+			name:      "Without requested time nothing is avoided",
+			reply:     `{"suggestions":[{"memo":"Olahraga","date_time":"2026-10-19T11:00:00Z","duration":60,"category":"Umum"}]}`,
+			start:     at.Add(2 * time.Hour),
+			conflicts: []uint{},
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := &ai.FakeProvider{Reply: func(req ai.Request) (string, error) { return tc.reply, nil }}
+			model := NewTodoAIModel(aiTestDB(t), provider, AIPricing{})
+
+			plan, err := model.PlanTodos(context.Background(), TodoAI{Todo: "Olahraga", Time: tc.requested}, 1, upcoming, nil)
+			require.NoError(t, err)
+			require.Len(t, plan.Suggestions, 1)
+			require.True(t, tc.start.Equal(plan.Suggestions[0].DateTime))
+			ids := []uint{}
+			for _, conflict := range plan.Conflicts {
+				ids = append(ids, conflict.TodoID)
+			}
+			require.Equal(t, tc.conflicts, ids)
+		})
+	}
+}
+
+func TestTodoAIModel_PlanTodos_OnlyPromptTodosAreBusy(t *testing.T) {
+	at := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	upcoming := []Todo{}
+	for i := 0; i < maxPlanContextTodos+10; i++ {
+		todo := Todo{Memo: fmt.Sprintf("Todo %d", i), DateTime: at.Add(time.Duration(i) * time.Hour), Duration: 60, Status: StatusTodo}
+		todo.ID = uint(i + 1)
+		upcoming = append(upcoming, todo)
+	}
+	// saran bertabrakan dengan todo yang tidak ikut dikirim ke model
+	start := upcoming[maxPlanContextTodos+5].DateTime
+	reply := fmt.Sprintf(`{"suggestions":[{"memo":"Olahraga","date_time":%q,"duration":30,"category":"Umum"}]}`, start.Format(time.RFC3339))
+	calls := 0
+	provider := &ai.FakeProvider{Reply: func(req ai.Request) (string, error) {
+		calls++
+		return reply, nil
+	}}
+	model := NewTodoAIModel(aiTestDB(t), provider, AIPricing{})
+
+	plan, err := model.PlanTodos(context.Background(), TodoAI{Todo: "Olahraga"}, 1, upcoming, nil)
+	require.NoError(t, err)
+	require.Len(t, plan.Suggestions, 1)
+	require.Equal(t, 1, calls)
+}
+
+func TestParsePlan(t *testing.T) {
+	busy := []Todo{{Memo: "Rapat tim", DateTime: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), Duration: 60}}
+	busy[0].ID = 1
+
+	_, err := parsePlan(`{"suggestions":[{"memo":"Olahraga","date_time":"2026-10-19T09:30:00Z","duration":60,"category":"Umum"}]}`, busy)
+	require.ErrorContains(t, err, "overlaps todo 1")
+
+	// conflicts tidak lagi diminta dari AI, todo_id karangan tidak diterima
+	_, err = parsePlan(`{"suggestions":[{"memo":"Olahraga","date_time":"2026-10-19T10:00:00Z","duration":60,"category":"Umum"}],"conflicts":[{"todo_id":99}]}`, busy)
+	require.Error(t, err)
+
+	suggestions, err := parsePlan(`{"suggestions":[{"memo":"Olahraga","date_time":"2026-10-19T10:00:00Z","duration":60,"category":"Umum"}]}`, busy)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+}
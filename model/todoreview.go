@@ -0,0 +1,335 @@
+package model
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mytodo/ai"
+	"mytodo/helper"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// WeeklyReview adalah rangkuman satu minggu ISO. Stats selalu dihitung dari
+// todo, hanya Narrative yang berasal dari AI (atau template lokal saat AI
+// tidak bisa dipakai) dan disimpan di cache selama Stats tidak berubah.
+type WeeklyReview struct {
+	Week            string          `json:"week"`
+	Start           time.Time       `json:"start"`
+	End             time.Time       `json:"end"`
+	Stats           ReviewStats     `json:"stats"`
+	Narrative       ReviewNarrative `json:"narrative"`
+	NarrativeSource string          `json:"narrative_source"`
+	Cached          bool            `json:"cached"`
+}
+
+// ReviewStats, CompletionRate adalah todo Done dibagi todo yang tidak Cancelled
+type ReviewStats struct {
+	Total          int              `json:"total"`
+	Completed      int              `json:"completed"`
+	Cancelled      int              `json:"cancelled"`
+	Open           int              `json:"open"`
+	Overdue        int              `json:"overdue"`
+	CompletionRate float64          `json:"completion_rate"`
+	ByStatus       map[string]int   `json:"by_status"`
+	ByCategory     []CategoryReview `json:"by_category"`
+	CompletedTodos []ReviewTodo     `json:"completed_todos"`
+	OverdueTodos   []ReviewTodo     `json:"overdue_todos"`
+}
+
+type CategoryReview struct {
+	CategoryID     *uint   `json:"category_id"`
+	Category       string  `json:"category"`
+	Total          int     `json:"total"`
+	Completed      int     `json:"completed"`
+	Overdue        int     `json:"overdue"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+type ReviewTodo struct {
+	TodoID         uint       `json:"todo_id"`
+	Memo           string     `json:"memo"`
+	DateTime       time.Time  `json:"date_time"`
+	OccurrenceDate *time.Time `json:"occurrence_date,omitempty"`
+	Status         string     `json:"status"`
+	Category       string     `json:"category"`
+}
+
+type ReviewNarrative struct {
+	Finished string   `json:"finished"`
+	Slipped  string   `json:"slipped"`
+	Focus    []string `json:"focus"`
+}
+
+// WeeklyReviewCache menyimpan narasi AI per user dan minggu, Fingerprint
+// adalah hash Stats saat narasi dibuat
+type WeeklyReviewCache struct {
+	gorm.Model
+	UserID      uint            `gorm:"uniqueIndex:idx_weekly_review"`
+	Week        string          `gorm:"type:varchar(10);uniqueIndex:idx_weekly_review"`
+	Fingerprint string          `gorm:"type:varchar(64)"`
+	Narrative   ReviewNarrative `gorm:"serializer:json;type:text"`
+}
+
+// sumber narasi review
+const (
+	NarrativeSourceAI    = "ai"
+	NarrativeSourceLocal = "local"
+)
+
+// batas jumlah fokus minggu depan dan contoh todo yang disebut di narasi lokal
+const (
+	maxReviewFocus     = 5
+	reviewNarrativeTop = 3
+	uncategorizedName  = "Tanpa Kategori"
+)
+
+const reviewPrompt = `Kamu membuat review mingguan todo list user dalam bahasa Indonesia yang ramah dan singkat. Angka di input sudah dihitung, JANGAN mengubah atau menghitung ulang angka.
+Jawab HANYA dengan JSON tanpa teks lain, dengan bentuk:
+{"finished":string apa yang sudah selesai,"slipped":string apa yang terlambat atau tertunda,"focus":[string rekomendasi fokus minggu depan]}
+focus berisi 1 sampai %d item. Contoh:
+%s`
+
+// ReviewWeek membuat review minggu yang dimulai start dari todos. Narasi dari
+// cache dipakai jika Stats tidak berubah dan refresh false. Jika useAI false
+// (misalnya kuota habis) atau AI dimatikan, narasi memakai template lokal.
+func (tm *TodoAIModel) ReviewWeek(ctx context.Context, start time.Time, todos []Todo, useAI, refresh bool, userID uint) (*WeeklyReview, error) {
+	now := time.Now()
+	end := start.AddDate(0, 0, 7)
+	review := &WeeklyReview{
+		Week:  helper.FormatISOWeek(start),
+		Start: start,
+		End:   end,
+		Stats: buildReviewStats(todos, start, end, now),
+	}
+	fingerprint := reviewFingerprint(review.Stats)
+
+	cache := WeeklyReviewCache{}
+	err := tm.db.Where("user_id = ? AND week = ?", userID, review.Week).First(&cache).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil && !refresh && cache.Fingerprint == fingerprint {
+		review.Narrative = cache.Narrative
+		review.NarrativeSource = NarrativeSourceAI
+		review.Cached = true
+		return review, nil
+	}
+
+	local := localNarrative(review.Stats)
+	if !useAI || tm.provider == nil {
+		review.Narrative = local
+		review.NarrativeSource = NarrativeSourceLocal
+		return review, nil
+	}
+	input, _ := json.Marshal(map[string]any{"minggu": review.Week, "statistik": review.Stats})
+	example, _ := json.Marshal(local)
+	messages := []ai.Message{
+		{Role: ai.RoleSystem, Content: fmt.Sprintf(reviewPrompt, maxReviewFocus, example)},
+		{Role: ai.RoleUser, Content: string(input)},
+	}
+	err = tm.completeJSON(ctx, userID, featureReview, ai.Request{Messages: messages, NoCache: refresh}, nil, func(content string) error {
+		res, err := parseReviewNarrative(content)
+		review.Narrative = res
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	review.NarrativeSource = NarrativeSourceAI
+
+	// upsert agar dua request bersamaan di minggu yang sama tidak bentrok di unique index
+	cache = WeeklyReviewCache{UserID: userID, Week: review.Week, Fingerprint: fingerprint, Narrative: review.Narrative}
+	if err := tm.db.Clauses(upsert([]string{"user_id", "week"}, "fingerprint", "narrative", "updated_at")).Create(&cache).Error; err != nil {
+		logrus.Error("Model: Error Menyimpan Cache Review Mingguan ", err.Error())
+	}
+	return review, nil
+}
+
+// buildReviewStats menghitung angka review dari todo minggu [start, end).
+// Todo terlambat adalah todo yang belum Done atau Cancelled dan jadwalnya
+// sudah lewat sebelum now atau sebelum minggu berakhir.
+func buildReviewStats(todos []Todo, start, end, now time.Time) ReviewStats {
+	stats := ReviewStats{
+		ByStatus:       map[string]int{},
+		ByCategory:     []CategoryReview{},
+		CompletedTodos: []ReviewTodo{},
+		OverdueTodos:   []ReviewTodo{},
+	}
+	for _, status := range TodoStatuses {
+		stats.ByStatus[status] = 0
+	}
+	deadline := end
+	if now.Before(deadline) {
+		deadline = now
+	}
+	categories := map[uint]*CategoryReview{}
+	cancelled := map[uint]int{}
+	for _, t := range todos {
+		if t.DateTime.Before(start) || !t.DateTime.Before(end) {
+			continue
+		}
+		status := t.Status
+		if status == "" {
+			status = StatusTodo
+		}
+		categoryID := categoryValue(t.CategoryID)
+		category := categories[categoryID]
+		if category == nil {
+			name := t.Category.Category
+			if t.CategoryID == nil || name == "" {
+				name = uncategorizedName
+			}
+			category = &CategoryReview{CategoryID: t.CategoryID, Category: name}
+			categories[categoryID] = category
+		}
+		item := ReviewTodo{
+			TodoID:         t.ID,
+			Memo:           t.Memo,
+			DateTime:       t.DateTime,
+			OccurrenceDate: t.OccurrenceDate,
+			Status:         status,
+			Category:       category.Category,
+		}
+		stats.Total++
+		stats.ByStatus[status]++
+		category.Total++
+		switch {
+		case status == StatusDone:
+			stats.Completed++
+			category.Completed++
+			stats.CompletedTodos = append(stats.CompletedTodos, item)
+		case status == StatusCancelled:
+			stats.Cancelled++
+			cancelled[categoryID]++
+		case t.DateTime.Before(deadline):
+			stats.Open++
+			stats.Overdue++
+			category.Overdue++
+			stats.OverdueTodos = append(stats.OverdueTodos, item)
+		default:
+			stats.Open++
+		}
+	}
+	stats.CompletionRate = completionRate(stats.Completed, stats.Total-stats.Cancelled)
+	for categoryID, category := range categories {
+		category.CompletionRate = completionRate(category.Completed, category.Total-cancelled[categoryID])
+		stats.ByCategory = append(stats.ByCategory, *category)
+	}
+	sort.Slice(stats.ByCategory, func(i, j int) bool {
+		if stats.ByCategory[i].Total != stats.ByCategory[j].Total {
+			return stats.ByCategory[i].Total > stats.ByCategory[j].Total
+		}
+		return stats.ByCategory[i].Category < stats.ByCategory[j].Category
+	})
+	byTime := func(items []ReviewTodo) {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].DateTime.Before(items[j].DateTime) })
+	}
+	byTime(stats.CompletedTodos)
+	byTime(stats.OverdueTodos)
+	return stats
+}
+
+func completionRate(completed, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(float64(completed)/float64(total)*1000) / 1000
+}
+
+func reviewFingerprint(stats ReviewStats) string {
+	data, _ := json.Marshal(stats)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// localNarrative adalah narasi sederhana dari Stats, dipakai saat AI tidak
+// bisa dipakai dan sebagai contoh jawaban untuk AI
+func localNarrative(stats ReviewStats) ReviewNarrative {
+	res := ReviewNarrative{Focus: []string{}}
+	if stats.Total == 0 {
+		res.Finished = "Tidak ada todo di minggu ini."
+		res.Slipped = "Tidak ada todo yang terlambat."
+		res.Focus = append(res.Focus, "Susun todo untuk minggu depan")
+		return res
+	}
+	res.Finished = fmt.Sprintf("Selesai %d dari %d todo (%.0f%%).", stats.Completed, stats.Total-stats.Cancelled, stats.CompletionRate*100)
+	if len(stats.CompletedTodos) > 0 {
+		res.Finished += " Termasuk " + reviewMemos(stats.CompletedTodos) + "."
+	}
+	if stats.Overdue == 0 {
+		res.Slipped = "Tidak ada todo yang terlambat."
+	} else {
+		res.Slipped = fmt.Sprintf("%d todo terlambat: %s.", stats.Overdue, reviewMemos(stats.OverdueTodos))
+	}
+	for _, memo := range uniqueMemos(stats.OverdueTodos) {
+		if len(res.Focus) == reviewNarrativeTop {
+			break
+		}
+		res.Focus = append(res.Focus, "Selesaikan "+memo)
+	}
+	// category dengan penyelesaian terendah yang masih punya todo terlambat
+	var weakest *CategoryReview
+	for i, category := range stats.ByCategory {
+		if category.Overdue > 0 && (weakest == nil || category.CompletionRate < weakest.CompletionRate) {
+			weakest = &stats.ByCategory[i]
+		}
+	}
+	if weakest != nil {
+		res.Focus = append(res.Focus, "Beri perhatian lebih pada category "+weakest.Category)
+	}
+	if len(res.Focus) == 0 {
+		res.Focus = append(res.Focus, "Pertahankan ritme minggu ini")
+	}
+	return res
+}
+
+// reviewMemos menyebut beberapa memo pertama, kejadian todo berulang hanya disebut sekali
+func reviewMemos(todos []ReviewTodo) string {
+	memos := uniqueMemos(todos)
+	if len(memos) > reviewNarrativeTop {
+		memos = append(memos[:reviewNarrativeTop], fmt.Sprintf("dan %d lainnya", len(memos)-reviewNarrativeTop))
+	}
+	return strings.Join(memos, ", ")
+}
+
+func uniqueMemos(todos []ReviewTodo) []string {
+	res := []string{}
+	seen := map[string]bool{}
+	for _, t := range todos {
+		if !seen[t.Memo] {
+			seen[t.Memo] = true
+			res = append(res, t.Memo)
+		}
+	}
+	return res
+}
+
+func parseReviewNarrative(content string) (ReviewNarrative, error) {
+	res := ReviewNarrative{}
+	if err := decodeAIJSON(content, &res); err != nil {
+		return res, err
+	}
+	switch {
+	case strings.TrimSpace(res.Finished) == "":
+		return res, errors.New("finished is required")
+	case strings.TrimSpace(res.Slipped) == "":
+		return res, errors.New("slipped is required")
+	case len(res.Focus) == 0 || len(res.Focus) > maxReviewFocus:
+		return res, fmt.Errorf("focus must contain 1 to %d items", maxReviewFocus)
+	}
+	for i, focus := range res.Focus {
+		if strings.TrimSpace(focus) == "" {
+			return res, fmt.Errorf("focus[%d] is empty", i)
+		}
+	}
+	return res, nil
+}
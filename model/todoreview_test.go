@@ -0,0 +1,119 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildReviewStats(t *testing.T) {
+	start := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+	at := func(day, hour int) time.Time { return start.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour) }
+	kerja := Category{Category: "Kerja"}
+	kerja.ID = 1
+	rumah := Category{Category: "Rumah"}
+	rumah.ID = 2
+	todo := func(id uint, memo string, dateTime time.Time, status string, category Category) Todo {
+		res := Todo{Memo: memo, DateTime: dateTime, Status: status, CategoryID: todoCategoryID(&category.ID), Category: category}
+		res.ID = id
+		return res
+	}
+	todos := []Todo{
+		todo(1, "Rapat tim", at(0, 9), StatusDone, kerja),
+		todo(2, "Kirim laporan", at(1, 9), StatusInProgress, kerja),
+		todo(3, "Review kode", at(4, 9), StatusTodo, kerja),
+		todo(4, "Cuci mobil", at(2, 8), StatusCancelled, rumah),
+		todo(5, "Bayar listrik", at(0, 20), StatusDone, rumah),
+		todo(6, "Tanpa category", at(1, 10), "", Category{}),
+		todo(7, "Minggu lalu", at(-1, 9), StatusTodo, kerja),
+		todo(8, "Minggu depan", at(7, 0), StatusTodo, kerja),
+	}
+
+	test := []struct {
+		name     string
+		now      time.Time
+		overdue  []uint
+		open     int
+		expected map[uint]CategoryReview
+	}{
+		{
+			name:    "Week in progress, only past todos are overdue",
+			now:     at(3, 0),
+			overdue: []uint{2, 6},
+			open:    3,
+			expected: map[uint]CategoryReview{
+				1: {CategoryID: &kerja.ID, Category: "Kerja", Total: 3, Completed: 1, Overdue: 1, CompletionRate: 0.333},
+				2: {CategoryID: &rumah.ID, Category: "Rumah", Total: 2, Completed: 1, Overdue: 0, CompletionRate: 1},
+				0: {Category: uncategorizedName, Total: 1, Completed: 0, Overdue: 1, CompletionRate: 0},
+			},
+		},
+		{
+			name:    "Past week, every open todo is overdue",
+			now:     end.AddDate(0, 1, 0),
+			overdue: []uint{2, 6, 3},
+			open:    3,
+			expected: map[uint]CategoryReview{
+				1: {CategoryID: &kerja.ID, Category: "Kerja", Total: 3, Completed: 1, Overdue: 2, CompletionRate: 0.333},
+				2: {CategoryID: &rumah.ID, Category: "Rumah", Total: 2, Completed: 1, Overdue: 0, CompletionRate: 1},
+				0: {Category: uncategorizedName, Total: 1, Completed: 0, Overdue: 1, CompletionRate: 0},
+			},
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			stats := buildReviewStats(todos, start, end, tc.now)
+			require.Equal(t, 6, stats.Total)
+			require.Equal(t, 2, stats.Completed)
+			require.Equal(t, 1, stats.Cancelled)
+			require.Equal(t, tc.open, stats.Open)
+			require.Equal(t, len(tc.overdue), stats.Overdue)
+			require.Equal(t, 0.4, stats.CompletionRate)
+			require.Equal(t, map[string]int{StatusTodo: 2, StatusInProgress: 1, StatusBlocked: 0, StatusDone: 2, StatusCancelled: 1}, stats.ByStatus)
+
+			overdue := []uint{}
+			for _, item := range stats.OverdueTodos {
+				overdue = append(overdue, item.TodoID)
+			}
+			require.Equal(t, tc.overdue, overdue)
+			require.Equal(t, []uint{1, 5}, []uint{stats.CompletedTodos[0].TodoID, stats.CompletedTodos[1].TodoID})
+
+			require.Len(t, stats.ByCategory, len(tc.expected))
+			require.Equal(t, "Kerja", stats.ByCategory[0].Category)
+			for _, category := range stats.ByCategory {
+				require.Equal(t, tc.expected[categoryValue(category.CategoryID)], category)
+			}
+		})
+	}
+}
+
+func TestLocalNarrative(t *testing.T) {
+	empty := buildReviewStats(nil, time.Now(), time.Now().AddDate(0, 0, 7), time.Now())
+	require.Equal(t, "Tidak ada todo di minggu ini.", localNarrative(empty).Finished)
+	require.Equal(t, 0.0, empty.CompletionRate)
+
+	stats := ReviewStats{
+		Total:          4,
+		Completed:      1,
+		Overdue:        1,
+		CompletionRate: 0.25,
+		CompletedTodos: []ReviewTodo{{Memo: "Rapat tim"}},
+		OverdueTodos:   []ReviewTodo{{Memo: "Kirim laporan"}},
+		ByCategory: []CategoryReview{
+			{Category: "Kerja", Overdue: 1, CompletionRate: 0.25},
+			{Category: "Rumah", CompletionRate: 0},
+		},
+	}
+	res := localNarrative(stats)
+	require.Equal(t, "Selesai 1 dari 4 todo (25%). Termasuk Rapat tim.", res.Finished)
+	require.Equal(t, "1 todo terlambat: Kirim laporan.", res.Slipped)
+	require.Equal(t, []string{"Selesaikan Kirim laporan", "Beri perhatian lebih pada category Kerja"}, res.Focus)
+
+	_, err := parseReviewNarrative(`{"finished":"a","slipped":"b","focus":["c"]}`)
+	require.NoError(t, err)
+	_, err = parseReviewNarrative(`{"finished":"a","slipped":"b","focus":[]}`)
+	require.Error(t, err)
+	_, err = parseReviewNarrative(`{"finished":"a","slipped":"b","focus":["c"],"score":1}`)
+	require.Error(t, err)
+}
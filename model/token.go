@@ -0,0 +1,177 @@
+package model
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TokenInterface interface {
+	SaveRefreshToken(token RefreshToken) bool
+	GetRefreshToken(hash string) *RefreshToken
+	RotateRefreshToken(old RefreshToken, newToken RefreshToken) bool
+	RevokeTokenFamily(family string) bool
+	RevokeSession(jti string, userID uint, expiresAt time.Time) bool
+	RevokeAllTokens(userID uint) bool
+	IsAccessTokenRevoked(jti string) bool
+}
+
+// RefreshToken menyimpan hash dari refresh token. Semua token hasil rotasi dari
+// satu login berbagi Family yang sama, sehingga pemakaian ulang token lama dapat
+// mencabut seluruh sesi tersebut.
+type RefreshToken struct {
+	gorm.Model
+	UserID          uint       `gorm:"index"`
+	TokenHash       string     `gorm:"type:varchar(64);uniqueIndex"`
+	Family          string     `gorm:"type:varchar(64);index"`
+	AccessJTI       string     `gorm:"type:varchar(64);index"`
+	AccessExpiresAt time.Time  `gorm:"type:datetime"`
+	ExpiresAt       time.Time  `gorm:"type:datetime"`
+	RevokedAt       *time.Time `gorm:"type:datetime"`
+	ReplacedBy      string     `gorm:"type:varchar(64)"`
+}
+
+// RevokedToken berisi jti access token yang sudah dicabut sebelum kedaluwarsa
+type RevokedToken struct {
+	gorm.Model
+	JTI       string    `gorm:"type:varchar(64);uniqueIndex"`
+	UserID    uint      `gorm:"index"`
+	ExpiresAt time.Time `gorm:"type:datetime;index"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" form:"refresh_token"`
+}
+
+type TokenModel struct {
+	db *gorm.DB
+}
+
+func (tm *TokenModel) InitToken(db *gorm.DB) {
+	tm.db = db
+}
+
+func NewTokenModel(db *gorm.DB) TokenInterface {
+	return &TokenModel{
+		db: db,
+	}
+}
+
+func (tm *TokenModel) SaveRefreshToken(token RefreshToken) bool {
+	if err := tm.db.Create(&token).Error; err != nil {
+		logrus.Error("Model: Error Saat Input Refresh Token ", err.Error())
+		return false
+	}
+	return true
+}
+
+func (tm *TokenModel) GetRefreshToken(hash string) *RefreshToken {
+	token := RefreshToken{}
+	if err := tm.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		logrus.Error("Model: Refresh Token Tidak Ditemukan ", err.Error())
+		return nil
+	}
+	return &token
+}
+
+func (tm *TokenModel) RotateRefreshToken(old RefreshToken, newToken RefreshToken) bool {
+	err := tm.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", old.ID).
+			Updates(map[string]any{"revoked_at": now, "replaced_by": newToken.TokenHash})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(&newToken).Error
+	})
+	if err != nil {
+		logrus.Error("Model: Error Saat Rotasi Refresh Token ", err.Error())
+		return false
+	}
+	return true
+}
+
+func (tm *TokenModel) RevokeTokenFamily(family string) bool {
+	tokens := []RefreshToken{}
+	if err := tm.db.Where("family = ?", family).Find(&tokens).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Refresh Token Family ", err.Error())
+		return false
+	}
+	return tm.revokeTokens(tokens)
+}
+
+func (tm *TokenModel) RevokeSession(jti string, userID uint, expiresAt time.Time) bool {
+	if !tm.revokeAccessToken(tm.db, jti, userID, expiresAt) {
+		return false
+	}
+	token := RefreshToken{}
+	if err := tm.db.Where("access_jti = ? AND user_id = ?", jti, userID).First(&token).Error; err != nil {
+		logrus.Error("Model: Refresh Token Sesi Tidak Ditemukan ", err.Error())
+		return true
+	}
+	return tm.RevokeTokenFamily(token.Family)
+}
+
+func (tm *TokenModel) RevokeAllTokens(userID uint) bool {
+	tokens := []RefreshToken{}
+	if err := tm.db.Where("user_id = ?", userID).Find(&tokens).Error; err != nil {
+		logrus.Error("Model: Error Mendapatkan Refresh Token User ", err.Error())
+		return false
+	}
+	return tm.revokeTokens(tokens)
+}
+
+func (tm *TokenModel) IsAccessTokenRevoked(jti string) bool {
+	var count int64
+	if err := tm.db.Model(&RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		logrus.Error("Model: Error Cek Token Dicabut ", err.Error())
+		return true
+	}
+	return count > 0
+}
+
+func (tm *TokenModel) revokeTokens(tokens []RefreshToken) bool {
+	now := time.Now()
+	err := tm.db.Transaction(func(tx *gorm.DB) error {
+		for _, token := range tokens {
+			if token.AccessJTI != "" && token.AccessExpiresAt.After(now) {
+				if !tm.revokeAccessToken(tx, token.AccessJTI, token.UserID, token.AccessExpiresAt) {
+					return gorm.ErrInvalidTransaction
+				}
+			}
+			if token.RevokedAt == nil {
+				if err := tx.Model(&RefreshToken{}).Where("id = ?", token.ID).Update("revoked_at", now).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Error("Model: Error Mencabut Refresh Token ", err.Error())
+		return false
+	}
+	return true
+}
+
+func (tm *TokenModel) revokeAccessToken(db *gorm.DB, jti string, userID uint, expiresAt time.Time) bool {
+	revoked := RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked).Error; err != nil {
+		logrus.Error("Model: Error Mencabut Access Token ", err.Error())
+		return false
+	}
+	if err := db.Unscoped().Where("expires_at < ?", time.Now()).Delete(&RevokedToken{}).Error; err != nil {
+		logrus.Error("Model: Error Membersihkan Token Kedaluwarsa ", err.Error())
+	}
+	return true
+}
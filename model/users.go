@@ -1,67 +1,141 @@
 package model
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mytodo/helper"
+
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type UsersInterface interface {
-	Register(newUser Users) *Users
-	Login(login Login) *Users
+	Register(newUser Users) (*Users, error)
+	Login(login Login) (*Users, error)
+	IsAdmin(id uint) (bool, error)
 }
 
+// Role user, role admin diberikan langsung lewat database
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type Users struct {
 	// ID        uint      `json:"id" form:"id" gorm:"type:primaryKey;autoIncrement:true"`
 	gorm.Model
-	Name     string `json:"name" form:"name" gorm:"type:varchar(255)"`
-	Email    string `json:"email" form:"email" gorm:"type:varchar(255);uniqueIndex"`
-	Password string `json:"password" form:"password" gorm:"type:varchar(255)"`
+	Name     string `json:"name" form:"name" gorm:"type:varchar(255)" validate:"required,max=255"`
+	Email    string `json:"email" form:"email" gorm:"type:varchar(255);uniqueIndex" validate:"required,email,max=255"`
+	Password string `json:"password" form:"password" gorm:"type:varchar(255)" validate:"required,min=8"`
+	Role     string `json:"role" form:"-" gorm:"type:varchar(20);default:'user'"`
 	// CreatedAt time.Time `json:"created_at" form:"created_at" gorm:"type:datetime"`
 	// UpdatedAt time.Time `json:"updated_at" form:"updated_at" gorm:"type:datetime"`
 	// DeletedAt time.Time `json:"deleted_at" form:"deleted_at" gorm:"type:datetime"`
 }
 
+// MarshalJSON memastikan password tidak pernah ikut terkirim di response,
+// termasuk saat Users ter-embed di Todo maupun Category.
+func (u Users) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		gorm.Model
+		Name  string `json:"name"`
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}{
+		Model: u.Model,
+		Name:  u.Name,
+		Email: u.Email,
+		Role:  u.Role,
+	})
+}
+
 type Login struct {
-	Email    string `json:"email" form:"email" gorm:"type:varchar(255)"`
-	Password string `json:"password" form:"password" gorm:"type:varchar(255)"`
+	Email    string `json:"email" form:"email" gorm:"type:varchar(255)" validate:"required"`
+	Password string `json:"password" form:"password" gorm:"type:varchar(255)" validate:"required"`
 }
 
 type UsersModel struct {
-	db *gorm.DB
+	db   *gorm.DB
+	cost int
 }
 
 func (um *UsersModel) InitUsers(db *gorm.DB) {
 	um.db = db
 }
 
-func NewUsersModel(db *gorm.DB) UsersInterface {
+func NewUsersModel(db *gorm.DB, cost int) UsersInterface {
 	return &UsersModel{
-		db: db,
+		db:   db,
+		cost: cost,
 	}
 }
 
-func (um *UsersModel) Register(newUser Users) *Users {
-	if err := um.db.Create(&newUser).Error; err != nil {
-		logrus.Error("Model: Error Saat Input Data User", err.Error())
-		return nil
+// ErrInvalidCredentials dikembalikan Login untuk email yang tidak terdaftar
+// maupun password yang salah, sehingga email terdaftar tidak bisa ditebak
+var ErrInvalidCredentials = newError(ErrNotFound, "invalid login credentials")
+
+func (um *UsersModel) Register(newUser Users) (*Users, error) {
+	if len(newUser.Password) > helper.MaxPasswordBytes {
+		return nil, newError(ErrValidation, "password must be at most %d bytes", helper.MaxPasswordBytes)
+	}
+	hash, err := helper.HashPassword(newUser.Password, um.cost)
+	if err != nil {
+		logrus.Error("Model: Error Saat Hash Password User ", err.Error())
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+	newUser.Password = hash
+	newUser.Role = RoleUser
+	err = um.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newUser).Error; err != nil {
+			return dbError(err, "user with email %s", newUser.Email)
+		}
+		category := Category{
+			Category: "Kegiatan Saya",
+			Color:    "#3f48cc",
+			UserID:   newUser.ID,
+		}
+		return dbError(tx.Create(&category).Error, "default category")
+	})
+	if err != nil {
+		logrus.Error("Model: Error Saat Input Data User ", err.Error())
+		return nil, err
+	}
+	return &newUser, nil
+}
+
+func (um *UsersModel) Login(login Login) (*Users, error) {
+	users := Users{}
+	if err := um.db.Where("email = ?", login.Email).First(&users).Error; err != nil {
+		logrus.Error("Model: User Tidak Ditemukan ", err.Error())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, dbError(err, "user")
 	}
-	category := Category{
-		Category: "Kegiatan Saya",
-		Color:    "#3f48cc",
-		UserID:   newUser.ID,
+	match, needRehash := helper.CheckPassword(users.Password, login.Password, um.cost)
+	if !match {
+		logrus.Error("Model: Password User Tidak Sesuai")
+		return nil, ErrInvalidCredentials
 	}
-	if err := um.db.Create(&category).Error; err != nil {
-		logrus.Error("Model: Error Saat Input Data User", err.Error())
-		return nil
+	if needRehash {
+		hash, err := helper.HashPassword(login.Password, um.cost)
+		if err != nil {
+			logrus.Error("Model: Error Saat Hash Ulang Password User ", err.Error())
+			return &users, nil
+		}
+		if err := um.db.Model(&users).Update("password", hash).Error; err != nil {
+			logrus.Error("Model: Error Saat Update Hash Password User ", err.Error())
+		}
 	}
-	return &newUser
+	return &users, nil
 }
 
-func (um *UsersModel) Login(login Login) *Users {
+func (um *UsersModel) IsAdmin(id uint) (bool, error) {
 	users := Users{}
-	if err := um.db.Where("email = ? AND password = ?", login.Email, login.Password).First(&users).Error; err != nil {
+	if err := um.db.Select("id", "role").First(&users, id).Error; err != nil {
 		logrus.Error("Model: User Tidak Ditemukan ", err.Error())
-		return nil
+		return false, dbError(err, "user %d", id)
 	}
-	return &users
+	return users.Role == RoleAdmin, nil
 }
@@ -0,0 +1,222 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	StatusTodo       = "Todo"
+	StatusInProgress = "InProgress"
+	StatusBlocked    = "Blocked"
+	StatusDone       = "Done"
+	StatusCancelled  = "Cancelled"
+)
+
+var TodoStatuses = []string{StatusTodo, StatusInProgress, StatusBlocked, StatusDone, StatusCancelled}
+
+// DefaultTransitions dipakai jika user maupun category belum punya workflow sendiri
+var DefaultTransitions = map[string][]string{
+	StatusTodo:       {StatusInProgress, StatusBlocked, StatusDone, StatusCancelled},
+	StatusInProgress: {StatusTodo, StatusBlocked, StatusDone, StatusCancelled},
+	StatusBlocked:    {StatusTodo, StatusInProgress, StatusCancelled},
+	StatusDone:       {StatusTodo, StatusInProgress},
+	StatusCancelled:  {StatusTodo},
+}
+
+var (
+	ErrIllegalTransition = newError(ErrConflict, "illegal status transition")
+	ErrInvalidWorkflow   = newError(ErrValidation, "invalid workflow")
+)
+
+type WorkflowInterface interface {
+	GetWorkflow(userID uint, categoryID uint) *Workflow
+	SetWorkflow(userID uint, workflow Workflow) error
+	ResetWorkflow(userID uint, categoryID uint) bool
+}
+
+// StatusTransition adalah satu transisi yang diizinkan. CategoryID bernilai 0
+// untuk workflow default milik user.
+type StatusTransition struct {
+	gorm.Model
+	UserID     uint   `gorm:"index:idx_status_transition"`
+	CategoryID uint   `gorm:"index:idx_status_transition"`
+	FromStatus string `gorm:"type:varchar(50)"`
+	ToStatus   string `gorm:"type:varchar(50)"`
+}
+
+type Workflow struct {
+	CategoryID  uint                `json:"category_id" form:"category_id"`
+	Scope       string              `json:"scope" form:"-"`
+	Statuses    []string            `json:"statuses" form:"-"`
+	Transitions map[string][]string `json:"transitions" form:"transitions"`
+}
+
+// TodoStatusHistory mencatat setiap perubahan status todo
+type TodoStatusHistory struct {
+	gorm.Model
+	TodoID         uint      `json:"todo_id" form:"todo_id" gorm:"index"`
+	UserID         uint      `json:"user_id" form:"user_id"`
+	OccurrenceDate string    `json:"occurrence_date,omitempty" form:"occurrence_date" gorm:"type:varchar(10)"`
+	FromStatus     string    `json:"from_status" form:"from_status" gorm:"type:varchar(50)"`
+	ToStatus       string    `json:"to_status" form:"to_status" gorm:"type:varchar(50)"`
+	ChangedAt      time.Time `json:"changed_at" form:"changed_at" gorm:"type:datetime"`
+}
+
+type TodoStatusRequest struct {
+	Status string `json:"status" form:"status"`
+}
+
+func (w Workflow) Allows(from, to string) bool {
+	for _, status := range w.Transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (w Workflow) checkTransition(from, to string) error {
+	if w.Allows(from, to) {
+		return nil
+	}
+	allowed := strings.Join(w.Transitions[from], ", ")
+	if allowed == "" {
+		allowed = "none"
+	}
+	return fmt.Errorf("%w from %s to %s, allowed: %s", ErrIllegalTransition, from, to, allowed)
+}
+
+func IsTodoStatus(status string) bool {
+	for _, known := range TodoStatuses {
+		if known == status {
+			return true
+		}
+	}
+	return false
+}
+
+// isClosedStatus menandai status yang mengakhiri satu kejadian todo
+func isClosedStatus(status string) bool {
+	return status == StatusDone || status == StatusCancelled
+}
+
+type WorkflowModel struct {
+	db *gorm.DB
+}
+
+func (wm *WorkflowModel) InitWorkflow(db *gorm.DB) {
+	wm.db = db
+}
+
+func NewWorkflowModel(db *gorm.DB) WorkflowInterface {
+	return &WorkflowModel{
+		db: db,
+	}
+}
+
+func (wm *WorkflowModel) GetWorkflow(userID uint, categoryID uint) *Workflow {
+	workflow, err := loadWorkflow(wm.db, userID, categoryID)
+	if err != nil {
+		logrus.Error("Model: Error Mendapatkan Workflow ", err.Error())
+		return nil
+	}
+	return workflow
+}
+
+func (wm *WorkflowModel) SetWorkflow(userID uint, workflow Workflow) error {
+	for from, targets := range workflow.Transitions {
+		if !IsTodoStatus(from) {
+			return fmt.Errorf("%w: unknown status %s", ErrInvalidWorkflow, from)
+		}
+		for _, to := range targets {
+			if !IsTodoStatus(to) || to == from {
+				return fmt.Errorf("%w: invalid transition %s to %s", ErrInvalidWorkflow, from, to)
+			}
+		}
+	}
+	if len(workflow.Transitions) == 0 {
+		return fmt.Errorf("%w: transitions are required", ErrInvalidWorkflow)
+	}
+	if workflow.CategoryID != 0 {
+		if err := wm.db.Where("user_id = ?", userID).First(&Category{}, workflow.CategoryID).Error; err != nil {
+			logrus.Error("Model: Category Workflow Tidak Ditemukan ", err.Error())
+			return err
+		}
+	}
+	err := wm.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ? AND category_id = ?", userID, workflow.CategoryID).Delete(&StatusTransition{}).Error; err != nil {
+			return err
+		}
+		transitions := []StatusTransition{}
+		for from, targets := range workflow.Transitions {
+			for _, to := range targets {
+				transitions = append(transitions, StatusTransition{
+					UserID:     userID,
+					CategoryID: workflow.CategoryID,
+					FromStatus: from,
+					ToStatus:   to,
+				})
+			}
+		}
+		if len(transitions) == 0 {
+			return nil
+		}
+		return tx.Create(&transitions).Error
+	})
+	if err != nil {
+		logrus.Error("Model: Error Simpan Workflow ", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (wm *WorkflowModel) ResetWorkflow(userID uint, categoryID uint) bool {
+	if err := wm.db.Unscoped().Where("user_id = ? AND category_id = ?", userID, categoryID).Delete(&StatusTransition{}).Error; err != nil {
+		logrus.Error("Model: Error Reset Workflow ", err.Error())
+		return false
+	}
+	return true
+}
+
+// loadWorkflow mencari workflow milik category, lalu milik user, lalu default
+func loadWorkflow(db *gorm.DB, userID uint, categoryID uint) (*Workflow, error) {
+	scopes := []struct {
+		name       string
+		categoryID uint
+	}{
+		{name: "category", categoryID: categoryID},
+		{name: "user", categoryID: 0},
+	}
+	for _, scope := range scopes {
+		if scope.name == "category" && categoryID == 0 {
+			continue
+		}
+		transitions := []StatusTransition{}
+		if err := db.Where("user_id = ? AND category_id = ?", userID, scope.categoryID).Find(&transitions).Error; err != nil {
+			return nil, err
+		}
+		if len(transitions) == 0 {
+			continue
+		}
+		workflow := &Workflow{
+			CategoryID:  scope.categoryID,
+			Scope:       scope.name,
+			Statuses:    TodoStatuses,
+			Transitions: map[string][]string{},
+		}
+		for _, transition := range transitions {
+			workflow.Transitions[transition.FromStatus] = append(workflow.Transitions[transition.FromStatus], transition.ToStatus)
+		}
+		return workflow, nil
+	}
+	return &Workflow{
+		Scope:       "default",
+		Statuses:    TodoStatuses,
+		Transitions: DefaultTransitions,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"errors"
+	"mytodo/config"
+	"mytodo/helper"
+	"mytodo/model"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	mid "github.com/labstack/echo/v4/middleware"
+)
+
+// JWTMiddleware memvalidasi access token lalu menolak token yang jti-nya sudah dicabut
+func JWTMiddleware(cfg config.ProgramConfig, tm model.TokenInterface) echo.MiddlewareFunc {
+	jwtAuth := mid.JWT([]byte(cfg.Secret))
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return jwtAuth(func(c echo.Context) error {
+			claims := helper.ExtractToken("user", c)
+			jti, _ := claims["jti"].(string)
+			if jti == "" || tm.IsAccessTokenRevoked(jti) {
+				return c.JSON(http.StatusUnauthorized, helper.FormatResponse("Token Has Been Revoked", nil))
+			}
+			return next(c)
+		})
+	}
+}
+
+// AdminMiddleware hanya meneruskan user dengan role admin, dipasang setelah JWTMiddleware
+func AdminMiddleware(um model.UsersInterface) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := helper.ExtractToken("user", c)
+			id, _ := claims["id"].(float64)
+			admin, err := um.IsAdmin(uint(id))
+			if err != nil && !errors.Is(err, model.ErrNotFound) {
+				return err
+			}
+			if !admin {
+				return c.JSON(http.StatusForbidden, helper.FormatResponse("Admin Access Required", nil))
+			}
+			return next(c)
+		}
+	}
+}
@@ -3,19 +3,22 @@ package routes
 import (
 	"mytodo/config"
 	"mytodo/controller"
+	"mytodo/model"
 
 	"github.com/labstack/echo/v4"
-	mid "github.com/labstack/echo/v4/middleware"
 )
 
-func RouteUsers(e *echo.Echo, uc controller.UsersControllerInterface) {
+func RouteUsers(e *echo.Echo, uc controller.UsersControllerInterface, cfg config.ProgramConfig, tm model.TokenInterface) {
 	e.POST("/signup", uc.Register())
 	e.POST("/auth", uc.Login())
+	e.POST("/auth/refresh", uc.Refresh())
+	e.POST("/auth/logout", uc.Logout(), JWTMiddleware(cfg, tm))
+	e.POST("/auth/logout-all", uc.LogoutAll(), JWTMiddleware(cfg, tm))
 }
 
-func RouteCategory(e *echo.Echo, cc controller.CategoryControllerInterface, cfg config.ProgramConfig) {
+func RouteCategory(e *echo.Echo, cc controller.CategoryControllerInterface, cfg config.ProgramConfig, tm model.TokenInterface) {
 	auth := e.Group("/category")
-	auth.Use(mid.JWT([]byte(cfg.Secret)))
+	auth.Use(JWTMiddleware(cfg, tm))
 	auth.GET("", cc.GetCategories())
 	auth.GET("/:id", cc.GetCategory())
 	auth.POST("", cc.AddCategory())
@@ -23,19 +26,73 @@ func RouteCategory(e *echo.Echo, cc controller.CategoryControllerInterface, cfg
 	auth.DELETE("/:id", cc.DeleteCategory())
 }
 
-func RouteTodo(e *echo.Echo, tc controller.TodoControllerInterface, cfg config.ProgramConfig) {
+func RouteTag(e *echo.Echo, tc controller.TagControllerInterface, cfg config.ProgramConfig, tm model.TokenInterface) {
+	auth := e.Group("/tag")
+	auth.Use(JWTMiddleware(cfg, tm))
+	auth.GET("", tc.GetTags())
+	auth.GET("/:id", tc.GetTag())
+	auth.POST("", tc.AddTag())
+	auth.PUT("/:id", tc.UpdateTag())
+	auth.DELETE("/:id", tc.DeleteTag())
+}
+
+func RouteTodo(e *echo.Echo, tc controller.TodoControllerInterface, cfg config.ProgramConfig, tm model.TokenInterface) {
 	auth := e.Group("/todo")
-	auth.Use(mid.JWT([]byte(cfg.Secret)))
+	auth.Use(JWTMiddleware(cfg, tm))
 	auth.GET("", tc.GetTodos())
+	auth.GET("/search", tc.SearchTodos())
 	auth.GET("/:id", tc.GetTodo())
 	auth.POST("", tc.AddTodo())
 	auth.PUT("/:id", tc.UpdateTodo())
 	auth.PUT("/status/:id", tc.UpdateTodoStatus())
+	auth.PUT("/category/:id", tc.UpdateTodoCategory())
 	auth.DELETE("/:id", tc.DeleteTodo())
+	auth.GET("/:id/history", tc.GetStatusHistory())
+	auth.POST("/:id/breakdown", tc.BreakdownTodo())
+	auth.POST("/:id/breakdown/accept", tc.AcceptBreakdown())
+	auth.PUT("/:id/occurrences/:date", tc.UpdateOccurrence())
+	auth.DELETE("/:id/occurrences/:date", tc.SkipOccurrence())
+}
+
+func RouteTodoItem(e *echo.Echo, ic controller.TodoItemControllerInterface, cfg config.ProgramConfig, tm model.TokenInterface) {
+	auth := e.Group("/todo/:id/items")
+	auth.Use(JWTMiddleware(cfg, tm))
+	auth.GET("", ic.GetItems())
+	auth.POST("", ic.AddItem())
+	auth.PUT("/:itemId", ic.UpdateItem())
+	auth.DELETE("/:itemId", ic.DeleteItem())
+}
+
+func RouteReminder(e *echo.Echo, rc controller.ReminderControllerInterface, cfg config.ProgramConfig, tm model.TokenInterface) {
+	auth := e.Group("/todo/:id/reminders")
+	auth.Use(JWTMiddleware(cfg, tm))
+	auth.GET("", rc.GetReminders())
+	auth.POST("", rc.AddReminder())
+	auth.DELETE("/:reminderId", rc.DeleteReminder())
+}
+
+func RouteWorkflow(e *echo.Echo, wc controller.WorkflowControllerInterface, cfg config.ProgramConfig, tm model.TokenInterface) {
+	auth := e.Group("/workflow")
+	auth.Use(JWTMiddleware(cfg, tm))
+	auth.GET("", wc.GetWorkflow())
+	auth.PUT("", wc.SetWorkflow())
+	auth.DELETE("", wc.ResetWorkflow())
 }
 
-func RouteTodoAI(e *echo.Echo, tc controller.TodoAIControllerInterface, cfg config.ProgramConfig) {
+func RouteTodoAI(e *echo.Echo, tc controller.TodoAIControllerInterface, cfg config.ProgramConfig, tm model.TokenInterface, um model.UsersInterface) {
 	auth := e.Group("/todoai")
-	auth.Use(mid.JWT([]byte(cfg.Secret)))
+	auth.Use(JWTMiddleware(cfg, tm))
 	auth.POST("", tc.TodoAI())
+	auth.GET("/stream", tc.StreamTodoAI())
+	auth.POST("/stream", tc.StreamTodoAI())
+	auth.POST("/accept", tc.AcceptSuggestions())
+	auth.POST("/plan", tc.PlanTodos())
+	auth.GET("/review", tc.GetWeeklyReview())
+	auth.POST("/threads", tc.AddThread())
+	auth.GET("/threads", tc.GetThreads())
+	auth.GET("/threads/:id", tc.GetThread())
+	auth.DELETE("/threads/:id", tc.DeleteThread())
+	auth.POST("/threads/:id/messages", tc.SendMessage())
+	auth.GET("/usage", tc.GetUsage())
+	auth.GET("/usage/admin", tc.GetUsageAggregate(), AdminMiddleware(um))
 }
@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"mytodo/config"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Notification adalah isi reminder yang dikirim ke user
+type Notification struct {
+	ReminderID uint      `json:"reminder_id"`
+	TodoID     uint      `json:"todo_id"`
+	UserID     uint      `json:"user_id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Memo       string    `json:"memo"`
+	DateTime   time.Time `json:"date_time"`
+	RemindAt   time.Time `json:"remind_at"`
+}
+
+// Notifier mengirim reminder. Error berarti reminder akan dicoba lagi,
+// sehingga implementasi harus aman jika pesan yang sama terkirim dua kali.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// NewNotifier membuat notifier dari config, beberapa notifier dipisah koma
+func NewNotifier(cfg config.ProgramConfig) (Notifier, error) {
+	notifiers := MultiNotifier{}
+	for _, name := range strings.Split(cfg.ReminderNotifier, ",") {
+		switch strings.TrimSpace(name) {
+		case "", "log":
+			notifiers = append(notifiers, LogNotifier{})
+		case "smtp":
+			if cfg.SMTPHost == "" || cfg.SMTPFrom == "" {
+				return nil, errors.New("notifier: smtp needs SMTPHOST and SMTPFROM")
+			}
+			notifiers = append(notifiers, &SMTPNotifier{
+				Addr: fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort),
+				Host: cfg.SMTPHost,
+				User: cfg.SMTPUser,
+				Pass: cfg.SMTPPassword,
+				From: cfg.SMTPFrom,
+			})
+		case "webhook":
+			if cfg.WebhookURL == "" {
+				return nil, errors.New("notifier: webhook needs WEBHOOKURL")
+			}
+			notifiers = append(notifiers, &WebhookNotifier{URL: cfg.WebhookURL, Client: &http.Client{Timeout: 10 * time.Second}})
+		default:
+			return nil, fmt.Errorf("notifier: unknown notifier %q, allowed: log, smtp, webhook", name)
+		}
+	}
+	if len(notifiers) == 1 {
+		return notifiers[0], nil
+	}
+	return notifiers, nil
+}
+
+// LogNotifier hanya menulis reminder ke log, berguna untuk development
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, n Notification) error {
+	logrus.Infof("Reminder: todo %d milik user %d (%s) jadwal %s", n.TodoID, n.UserID, n.Memo, n.DateTime.Format(time.RFC3339))
+	return nil
+}
+
+// SMTPNotifier mengirim reminder lewat email
+type SMTPNotifier struct {
+	Addr string
+	Host string
+	User string
+	Pass string
+	From string
+	// send dapat diganti saat test
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func (s *SMTPNotifier) Notify(ctx context.Context, n Notification) error {
+	if n.Email == "" {
+		return errors.New("notifier: user has no email")
+	}
+	var auth smtp.Auth
+	if s.User != "" {
+		auth = smtp.PlainAuth("", s.User, s.Pass, s.Host)
+	}
+	body := fmt.Sprintf("Halo %s,\r\n\r\nTodo \"%s\" dijadwalkan pada %s.\r\n", n.Name, n.Memo, n.DateTime.Format("02 Jan 2006 15:04"))
+	msg := []byte("From: " + s.From + "\r\n" +
+		"To: " + n.Email + "\r\n" +
+		"Subject: " + mailSubject("Pengingat: "+n.Memo) + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n" + body)
+	send := s.send
+	if send == nil {
+		send = smtp.SendMail
+	}
+	return send(s.Addr, auth, s.From, []string{n.Email}, msg)
+}
+
+// mailSubject membuang baris baru agar memo tidak bisa menyisipkan header,
+// teks non-ASCII di-encode sesuai RFC 2047
+func mailSubject(subject string) string {
+	subject = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(subject)
+	return mime.QEncoding.Encode("utf-8", subject)
+}
+
+// WebhookNotifier mengirim reminder sebagai JSON lewat HTTP POST, status selain 2xx dianggap gagal
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// penerima dapat memakai key ini untuk membuang kiriman ganda
+	req.Header.Set("Idempotency-Key", fmt.Sprintf("reminder-%d-%d", n.ReminderID, n.RemindAt.Unix()))
+	res, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("notifier: webhook returned status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// MultiNotifier mengirim ke semua notifier, gagal jika salah satu gagal
+type MultiNotifier []Notifier
+
+func (m MultiNotifier) Notify(ctx context.Context, n Notification) error {
+	errs := []error{}
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"mytodo/model"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// jumlah reminder yang diklaim dalam satu putaran
+const reminderBatchSize = 50
+
+// Scheduler memeriksa reminder jatuh tempo secara berkala dan mengirimkannya.
+// Beberapa instance dapat berjalan bersamaan karena setiap reminder diklaim
+// dengan lease sebelum dikirim. Reminder yang sudah terkirim tetapi gagal
+// ditandai akan dikirim ulang setelah lease habis (at-least-once).
+type Scheduler struct {
+	model    model.ReminderInterface
+	notifier Notifier
+	interval time.Duration
+	lease    time.Duration
+	owner    string
+	now      func() time.Time
+}
+
+func NewScheduler(m model.ReminderInterface, n Notifier, interval, lease time.Duration) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		model:    m,
+		notifier: n,
+		interval: interval,
+		lease:    lease,
+		owner:    fmt.Sprintf("%s-%d-%d", host, os.Getpid(), time.Now().UnixNano()),
+		now:      time.Now,
+	}
+}
+
+// Start menjalankan scheduler sampai ctx dibatalkan, panggil sebagai goroutine
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce mengirim semua reminder yang jatuh tempo dan mengembalikan jumlah yang terkirim
+func (s *Scheduler) RunOnce(ctx context.Context) int {
+	now := s.now()
+	reminders := s.model.ClaimDueReminders(s.owner, now, s.lease, reminderBatchSize)
+	sent := 0
+	for _, reminder := range reminders {
+		if ctx.Err() != nil {
+			return sent
+		}
+		notifyCtx, cancel := context.WithTimeout(ctx, s.lease/2)
+		err := s.notifier.Notify(notifyCtx, notificationOf(reminder))
+		cancel()
+		if err != nil {
+			logrus.Error("Scheduler: Gagal Mengirim Reminder ", reminder.ID, " ", err.Error())
+			s.model.ReleaseReminder(reminder, s.now().Add(retryDelay(reminder.Attempts)), err.Error())
+			continue
+		}
+		if !s.model.MarkReminderSent(reminder, s.now()) {
+			// lease sudah diambil instance lain, reminder mungkin terkirim dua kali
+			logrus.Warn("Scheduler: Reminder Terkirim Tetapi Gagal Ditandai ", reminder.ID)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
+// retryDelay memberi jeda bertambah untuk setiap kegagalan: 1, 2, 4, 8 lalu 16 menit
+func retryDelay(attempts int) time.Duration {
+	return time.Minute << min(attempts, 4)
+}
+
+func notificationOf(reminder model.Reminder) Notification {
+	// untuk reminder offset, jadwal kejadian dihitung dari RemindAt agar benar pada todo berulang
+	dateTime := reminder.Todo.DateTime
+	if reminder.OffsetMinutes != nil {
+		dateTime = reminder.RemindAt.Add(time.Duration(*reminder.OffsetMinutes) * time.Minute)
+	}
+	return Notification{
+		ReminderID: reminder.ID,
+		TodoID:     reminder.TodoID,
+		UserID:     reminder.UserID,
+		Name:       reminder.User.Name,
+		Email:      reminder.User.Email,
+		Memo:       reminder.Todo.Memo,
+		DateTime:   dateTime,
+		RemindAt:   reminder.RemindAt,
+	}
+}
@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"mytodo/config"
+	"mytodo/model"
+	"mytodo/model/mocks"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNotifier struct {
+	err  error
+	sent []Notification
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, n Notification) error {
+	f.sent = append(f.sent, n)
+	return f.err
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	now := time.Date(2023, 11, 6, 8, 45, 0, 0, time.UTC)
+	offset := 15
+	reminder := model.Reminder{
+		TodoID:        3,
+		UserID:        1,
+		OffsetMinutes: &offset,
+		RemindAt:      now,
+		LeaseOwner:    "instance-a",
+		Todo:          model.Todo{Memo: "Rapat"},
+		User:          model.Users{Name: "Budi", Email: "budi@mail.com"},
+	}
+	reminder.ID = 7
+
+	test := []struct {
+		name         string
+		notifyErr    error
+		mock         func(*mocks.ReminderInterface)
+		expectedSent int
+	}{
+		{
+			name: "Sent reminder is marked",
+			mock: func(m *mocks.ReminderInterface) {
+				m.On("ClaimDueReminders", "instance-a", now, 2*time.Minute, reminderBatchSize).Return([]model.Reminder{reminder})
+				m.On("MarkReminderSent", reminder, now).Return(true)
+			},
+			expectedSent: 1,
+		},
+		{
+			name:      "Failed reminder is released for retry",
+			notifyErr: errors.New("smtp down"),
+			mock: func(m *mocks.ReminderInterface) {
+				m.On("ClaimDueReminders", "instance-a", now, 2*time.Minute, reminderBatchSize).Return([]model.Reminder{reminder})
+				m.On("ReleaseReminder", reminder, now.Add(time.Minute), "smtp down").Return(true)
+			},
+			expectedSent: 0,
+		},
+		{
+			name: "Reminder whose lease was lost is not counted",
+			mock: func(m *mocks.ReminderInterface) {
+				m.On("ClaimDueReminders", "instance-a", now, 2*time.Minute, reminderBatchSize).Return([]model.Reminder{reminder})
+				m.On("MarkReminderSent", reminder, now).Return(false)
+			},
+			expectedSent: 0,
+		},
+		{
+			name: "Nothing due",
+			mock: func(m *mocks.ReminderInterface) {
+				m.On("ClaimDueReminders", "instance-a", now, 2*time.Minute, reminderBatchSize).Return([]model.Reminder{})
+			},
+			expectedSent: 0,
+		},
+	}
+	for _, tc := range test {
+		t.Run(tc.name, func(t *testing.T) {
+			reminderMockModel := new(mocks.ReminderInterface)
+			tc.mock(reminderMockModel)
+			notifier := &fakeNotifier{err: tc.notifyErr}
+
+			s := NewScheduler(reminderMockModel, notifier, time.Minute, 2*time.Minute)
+			s.owner = "instance-a"
+			s.now = func() time.Time { return now }
+
+			require.Equal(t, tc.expectedSent, s.RunOnce(context.Background()))
+			reminderMockModel.AssertExpectations(t)
+			for _, n := range notifier.sent {
+				require.Equal(t, "budi@mail.com", n.Email)
+				require.Equal(t, now.Add(15*time.Minute), n.DateTime)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	received := Notification{}
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NotEmpty(t, r.Header.Get("Idempotency-Key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	notifier := &WebhookNotifier{URL: server.URL, Client: server.Client()}
+	require.NoError(t, notifier.Notify(context.Background(), Notification{ReminderID: 1, Memo: "Rapat"}))
+	require.Equal(t, "Rapat", received.Memo)
+
+	status = http.StatusBadGateway
+	require.Error(t, notifier.Notify(context.Background(), Notification{ReminderID: 1}))
+}
+
+func TestSMTPNotifier_Subject(t *testing.T) {
+	var msg string
+	notifier := &SMTPNotifier{From: "todo@example.com", send: func(addr string, a smtp.Auth, from string, to []string, body []byte) error {
+		msg = string(body)
+		return nil
+	}}
+	err := notifier.Notify(context.Background(), Notification{Email: "budi@example.com", Memo: "Rapat\r\nBcc: x@example.com\rKopi ☕"})
+	require.NoError(t, err)
+	headers, _, found := strings.Cut(msg, "\r\n\r\n")
+	require.True(t, found)
+	require.NotContains(t, headers, "\r\nBcc:")
+	require.Contains(t, headers, "\r\nSubject: =?utf-8?q?")
+	subject := headers[strings.Index(headers, "Subject: ")+len("Subject: "):]
+	subject, _, _ = strings.Cut(subject, "\r\n")
+	decoded, err := new(mime.WordDecoder).DecodeHeader(subject)
+	require.NoError(t, err)
+	require.Equal(t, "Pengingat: Rapat Bcc: x@example.com Kopi ☕", decoded)
+
+	require.Equal(t, "Pengingat: Rapat", mailSubject("Pengingat: Rapat"))
+}
+
+func TestNewNotifier(t *testing.T) {
+	notifier, err := NewNotifier(config.ProgramConfig{ReminderNotifier: "log"})
+	require.NoError(t, err)
+	require.IsType(t, LogNotifier{}, notifier)
+
+	notifier, err = NewNotifier(config.ProgramConfig{ReminderNotifier: "log,webhook", WebhookURL: "http://localhost/hook"})
+	require.NoError(t, err)
+	require.Len(t, notifier, 2)
+
+	_, err = NewNotifier(config.ProgramConfig{ReminderNotifier: "smtp"})
+	require.Error(t, err)
+
+	_, err = NewNotifier(config.ProgramConfig{ReminderNotifier: "pigeon"})
+	require.Error(t, err)
+}